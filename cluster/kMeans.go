@@ -28,26 +28,29 @@ func nearestMean(means *matrix.Matrix, observation ObservationWithClusterID, dis
 	return
 }
 
-func observationInit(rawData *matrix.Matrix) ClusteredObservationSet {
-	observationSet := make([]ObservationWithClusterID, len(rawData.Data))
-	for i, d := range rawData.Data {
-		observationSet[i].Observation = d
+func observationInit(rawData matrix.Interface) ClusteredObservationSet {
+	row, _ := rawData.Dims()
+	observationSet := make([]ObservationWithClusterID, row)
+	for i := range observationSet {
+		observationSet[i].Observation = *rawData.Row(i)
 	}
 	return observationSet
 }
 
-func RandomMeans(dataSet *matrix.Matrix, k int) *matrix.Matrix {
-	means := matrix.ZeroMatrix(k, len(dataSet.Data))
+func RandomMeans(dataSet matrix.Interface, k int) *matrix.Matrix {
+	row, col := dataSet.Dims()
+	means := matrix.ZeroMatrix(k, col)
 	rand.Seed(time.Now().UnixNano())
 	for i := 0; i < k; i++ {
-		means.Data[i] = dataSet.Data[rand.Intn(len(dataSet.Data))]
+		copy(means.Data[i], *dataSet.Row(rand.Intn(row)))
 	}
 	return means
 }
 
 // K-means
 // https://en.wikipedia.org/wiki/K-means_clustering
-func KMeans(dataSet *matrix.Matrix, means *matrix.Matrix, distFunc DistFunc, iterLimit int) (ClusteredObservationSet, []int, []int, int) {
+//	dataSet can be any `matrix.Interface` (e.g. SparseMatrix), observations are got by `Row`
+func KMeans(dataSet matrix.Interface, means *matrix.Matrix, distFunc DistFunc, iterLimit int) (ClusteredObservationSet, []int, []int, int) {
 	data := observationInit(dataSet)
 	cnt := 0
 	// Assign Step
@@ -66,12 +69,12 @@ func KMeans(dataSet *matrix.Matrix, means *matrix.Matrix, distFunc DistFunc, ite
 		// generate new means
 		means = matrix.Empty(means)
 		setVolume = make([]int, len(means.Data))
-		for _, d := range data {
-			means.Data[d.ClusterID] = *(means.Data[d.ClusterID].Add(&(d.Observation)))
-			setVolume[d.ClusterID]++
+		for i := range data {
+			matrix.Axpy(1, &data[i].Observation, &means.Data[data[i].ClusterID])
+			setVolume[data[i].ClusterID]++
 		}
 		for i := range means.Data {
-			means.Data[i] = *(means.Data[i].MulNum(1. / float64(setVolume[i])))
+			matrix.Scal(1./float64(setVolume[i]), &means.Data[i])
 		}
 		change = false
 		for i, d := range data {
@@ -99,24 +102,24 @@ func KMeans(dataSet *matrix.Matrix, means *matrix.Matrix, distFunc DistFunc, ite
 //	3. Choose one new data point at random as a new center, using a weighted probability distribution where a point x is chosen with probability proportional to D(x)2.
 //	4. Repeat Steps 2 and 3 until k centers have been chosen.
 //	5. Now that the initial centers have been chosen, proceed using standard k-means clustering.
-func KMeansPP(dataSet *matrix.Matrix, k int, distFunc DistFunc, iterLimit int) (ClusteredObservationSet, []int, []int, int) {
+func KMeansPP(dataSet matrix.Interface, k int, distFunc DistFunc, iterLimit int) (ClusteredObservationSet, []int, []int, int) {
 	means := PPMeans(dataSet, k, distFunc)
 	return KMeans(dataSet, means, distFunc, iterLimit)
 }
 
-func PPMeans(dataSet *matrix.Matrix, k int, distFunc DistFunc) *matrix.Matrix {
-	dataLen := len(dataSet.Data)
-	means := matrix.ZeroMatrix(k, dataLen)
+func PPMeans(dataSet matrix.Interface, k int, distFunc DistFunc) *matrix.Matrix {
+	dataLen, col := dataSet.Dims()
+	means := matrix.ZeroMatrix(k, col)
 	rand.Seed(time.Now().UnixNano())
 	// step 1
-	means.Data[0] = dataSet.Data[rand.Intn(dataLen)]
+	copy(means.Data[0], *dataSet.Row(rand.Intn(dataLen)))
 	// step 2
 	dx2 := make([]float64, dataLen)
 	sum := 0.
 	for i := 1; i < k; i++ {
 		sum = 0.
-		for j, d := range dataSet.Data {
-			_, minDistance := nearestMean(new(matrix.Matrix).Init(means.Data[:i]), ObservationWithClusterID{Observation: d}, distFunc)
+		for j := 0; j < dataLen; j++ {
+			_, minDistance := nearestMean(new(matrix.Matrix).Init(means.Data[:i]), ObservationWithClusterID{Observation: *dataSet.Row(j)}, distFunc)
 			dx2[j] = minDistance * minDistance
 			sum += dx2[j]
 		}
@@ -126,7 +129,7 @@ func PPMeans(dataSet *matrix.Matrix, k int, distFunc DistFunc) *matrix.Matrix {
 		for sum = dx2[0]; sum < target; sum += dx2[idx] {
 			idx++
 		}
-		means.Data[i] = dataSet.Data[idx]
+		copy(means.Data[i], *dataSet.Row(idx))
 	}
 	return means
 }
@@ -68,3 +68,16 @@ func BenchmarkKMeansPP(b *testing.B) {
 		})
 	}
 }
+
+func TestKMeansSparse(t *testing.T) {
+	dataSet := matrix.GenerateRandomSparseMatrix(200, 5, 400)
+	means := RandomMeans(dataSet, 4)
+	clusteredData, _, finalDistribution, _ := KMeans(dataSet, means, spatial.SquaredEuclideanDistance, 50)
+	sum := 0
+	for _, n := range finalDistribution {
+		sum += n
+	}
+	if len(clusteredData) != 200 || sum != 200 {
+		t.Fail()
+	}
+}
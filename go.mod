@@ -1,5 +1,3 @@
 module golina
 
-go 1.12
-
-require golang.org/x/sync v0.0.0-20190423024810-112230192c58
+go 1.18
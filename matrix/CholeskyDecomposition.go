@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -9,13 +10,37 @@ import (
 // 	A = L.Mul(L.T())
 // 	Ljj = sqrt(Ajj - sum((Ljk) ** 2)_from_k=1_to_j-1)
 // 	Lij = (1 / Ljj) * (Aij - sum(Lik * Ljk)_from_k=1_to_j-1)
+//	notice: it does not check positive definiteness, non positive definite input gets NaN or Inf inside L,
+//	use `TryCholeskyDecomposition` for checked version
 func CholeskyDecomposition(t *Matrix) *Matrix {
 	row, col := t.Dims()
 	if row != col {
-		panic("square matrix only")
+		panic(squareError("CholeskyDecomposition", row, col))
 	}
+	L, _ := cholesky(t)
+	return L
+}
+
+// TryCholeskyDecomposition does Cholesky decomposition of matrix like `CholeskyDecomposition`, but reports failure by error
+//	ErrNotSquare for non-square matrix, ErrNotPositiveDefinite if a non-positive pivot is met
+func TryCholeskyDecomposition(t *Matrix) (*Matrix, error) {
+	row, col := t.Dims()
+	if row != col {
+		return nil, squareError("CholeskyDecomposition", row, col)
+	}
+	L, pivot := cholesky(t)
+	if pivot >= 0 {
+		return nil, fmt.Errorf("CholeskyDecomposition: %w (pivot %d)", ErrNotPositiveDefinite, pivot)
+	}
+	return L, nil
+}
+
+// cholesky computes L of square matrix t and returns the index of the first non-positive pivot (-1 if none)
+func cholesky(t *Matrix) (*Matrix, int) {
+	row, col := t.Dims()
 	L := ZeroMatrix(row, col)
 	sum := 0.
+	pivot := -1
 	// Ljj first
 	for i := 0; i < row; i++ {
 		for j := 0; j <= i; j++ {
@@ -24,7 +49,11 @@ func CholeskyDecomposition(t *Matrix) *Matrix {
 				for k := 0; k < j; k++ {
 					sum += L.At(j, k) * L.At(j, k)
 				}
-				L.Set(j, j, math.Sqrt(t.At(j, j)-sum))
+				d := t.At(j, j) - sum
+				if !(d > 0) && pivot < 0 {
+					pivot = j
+				}
+				L.Set(j, j, math.Sqrt(d))
 			} else { // Lij
 				for k := 0; k < j; k++ {
 					sum += L.At(i, k) * L.At(j, k)
@@ -33,5 +62,177 @@ func CholeskyDecomposition(t *Matrix) *Matrix {
 			}
 		}
 	}
-	return L
+	return L, pivot
+}
+
+// Cholesky is Cholesky decomposition A = L * L.T() of symmetric positive definite matrix A
+//	it is factorized once and reused for many right-hand sides, and it can be updated by rank-one changes of A
+type Cholesky struct {
+	L *Matrix
+}
+
+// CholeskyFactorize returns Cholesky decomposition of symmetric positive definite matrix, panics on error
+func CholeskyFactorize(t *Matrix) *Cholesky {
+	c, err := TryCholeskyFactorize(t)
+	must(err)
+	return c
+}
+
+// TryCholeskyFactorize returns Cholesky decomposition like `CholeskyFactorize`, errors of `TryCholeskyDecomposition`
+func TryCholeskyFactorize(t *Matrix) (*Cholesky, error) {
+	L, err := TryCholeskyDecomposition(t)
+	if err != nil {
+		return nil, err
+	}
+	return &Cholesky{L: L}, nil
+}
+
+// solveInPlace overwrites rows of X (n x k) by A⁻¹ * X, L * Y = X by forward and L.T() * X = Y by back substitution
+func (c *Cholesky) solveInPlace(X Data) {
+	L := c.L.Data
+	n := len(L)
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			if l := L[i][k]; l != 0 {
+				for j, v := range X[k] {
+					X[i][j] -= l * v
+				}
+			}
+		}
+		for j := range X[i] {
+			X[i][j] /= L[i][i]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := range X[i] {
+			X[i][j] /= L[i][i]
+		}
+		for k := 0; k < i; k++ {
+			if l := L[i][k]; l != 0 {
+				for j, v := range X[i] {
+					X[k][j] -= l * v
+				}
+			}
+		}
+	}
+}
+
+// TrySolve solves A * x = b, ErrDimensionMismatch if length of b is wrong
+func (c *Cholesky) TrySolve(b *Vector) (*Vector, error) {
+	n, _ := c.L.Dims()
+	if b.Length() != n {
+		return nil, lenError("Cholesky.Solve", n, b.Length())
+	}
+	X := make(Data, n)
+	for i, v := range *b {
+		X[i] = Vector{v}
+	}
+	c.solveInPlace(X)
+	x := make(Vector, n)
+	for i := range x {
+		x[i] = X[i][0]
+	}
+	return &x, nil
+}
+
+// Solve solves A * x = b like `TrySolve`, panics on error
+func (c *Cholesky) Solve(b *Vector) *Vector {
+	x, err := c.TrySolve(b)
+	must(err)
+	return x
+}
+
+// TrySolveMatrix solves A * X = B for all columns of B, ErrDimensionMismatch if rows of B are wrong
+func (c *Cholesky) TrySolveMatrix(B *Matrix) (*Matrix, error) {
+	n, _ := c.L.Dims()
+	if m, k := B.Dims(); m != n {
+		return nil, dimsError("Cholesky.SolveMatrix", n, n, m, k)
+	}
+	X := Copy(B)
+	c.solveInPlace(X.Data)
+	return X, nil
+}
+
+// SolveMatrix solves A * X = B like `TrySolveMatrix`, panics on error
+func (c *Cholesky) SolveMatrix(B *Matrix) *Matrix {
+	X, err := c.TrySolveMatrix(B)
+	must(err)
+	return X
+}
+
+// Det returns determinant of A, product of squared diagonal of L
+func (c *Cholesky) Det() float64 {
+	det := 1.
+	for i, row := range c.L.Data {
+		det *= row[i] * row[i]
+	}
+	return det
+}
+
+// LogDet returns log(det(A)) = 2 * Σ log(L[i][i]), it does not overflow for large matrices like `Det`
+//	e.g. log-likelihood of Gaussian process
+func (c *Cholesky) LogDet() float64 {
+	logDet := 0.
+	for i, row := range c.L.Data {
+		logDet += 2 * math.Log(row[i])
+	}
+	return logDet
+}
+
+// Inverse returns inverse of A
+func (c *Cholesky) Inverse() *Matrix {
+	n, _ := c.L.Dims()
+	inv := IdentityMatrix(n)
+	c.solveInPlace(inv.Data)
+	return inv
+}
+
+// Update changes factorization to A + x * x.T() in place by O(n^2) Givens-like rotations
+//	https://en.wikipedia.org/wiki/Cholesky_decomposition#Rank-one_update
+func (c *Cholesky) Update(x *Vector) {
+	n, _ := c.L.Dims()
+	if x.Length() != n {
+		panic(lenError("Cholesky.Update", n, x.Length()))
+	}
+	c.rankOne(append(Vector{}, *x...), 1, c.L.Data)
+}
+
+// TryDowndate changes factorization to A - x * x.T() in place
+//	ErrDimensionMismatch, or ErrNotPositiveDefinite if A - x * x.T() is not positive definite, then the factorization is unchanged
+func (c *Cholesky) TryDowndate(x *Vector) error {
+	n, _ := c.L.Dims()
+	if x.Length() != n {
+		return lenError("Cholesky.Downdate", n, x.Length())
+	}
+	L := Copy(c.L)
+	if k := c.rankOne(append(Vector{}, *x...), -1, L.Data); k >= 0 {
+		return fmt.Errorf("Cholesky.Downdate: %w (pivot %d)", ErrNotPositiveDefinite, k)
+	}
+	c.L = L
+	return nil
+}
+
+// Downdate changes factorization to A - x * x.T() like `TryDowndate`, panics on error
+func (c *Cholesky) Downdate(x *Vector) {
+	must(c.TryDowndate(x))
+}
+
+// rankOne overwrites L by factor of L * L.T() + sign * x * x.T(), x is used as work space
+//	it returns index of the first non-positive pivot of downdate, or -1
+func (c *Cholesky) rankOne(x Vector, sign float64, L Data) int {
+	n := len(L)
+	for k := 0; k < n; k++ {
+		d := L[k][k]*L[k][k] + sign*x[k]*x[k]
+		if !(d > 0) {
+			return k
+		}
+		r := math.Sqrt(d)
+		cs, sn := r/L[k][k], x[k]/L[k][k]
+		L[k][k] = r
+		for i := k + 1; i < n; i++ {
+			L[i][k] = (L[i][k] + sign*sn*x[i]) / cs
+			x[i] = cs*x[i] - sn*L[i][k]
+		}
+	}
+	return -1
 }
@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	"math"
 	"strconv"
 	"testing"
@@ -15,6 +16,50 @@ func TestMatrix_CholeskyDecomposition(t *testing.T) {
 	}
 }
 
+func TestCholesky(t *testing.T) {
+	a := new(Matrix).Init(Data{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}})
+	c := CholeskyFactorize(a)
+	// det = (2 * 1 * 3)^2
+	if !FloatEqual(c.Det(), 36) || !FloatEqual(c.LogDet(), math.Log(36)) || !MEqual(c.Inverse().Mul(a), IdentityMatrix(3)) {
+		t.Fail()
+	}
+	b := &Vector{1, 2, 3}
+	if !VEqual(a.MulVec(c.Solve(b)), b) {
+		t.Fail()
+	}
+	B := GenerateRandomMatrix(3, 5)
+	if !MEqual(a.Mul(c.SolveMatrix(B)), B) {
+		t.Fail()
+	}
+	if _, err := TryCholeskyFactorize(new(Matrix).Init(Data{{1, 2}, {2, 1}})); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+	if _, err := c.TrySolve(&Vector{1}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
+func TestCholesky_UpdateDowndate(t *testing.T) {
+	r := GenerateRandomMatrix(8, 5)
+	a := r.T().Mul(r).Add(IdentityMatrix(5))
+	c := CholeskyFactorize(a)
+	x := GenerateRandomVector(5)
+	xx := x.ToMatrix(5, 1).Mul(x.ToMatrix(1, 5))
+	c.Update(x)
+	if !MEqual(c.L, CholeskyDecomposition(a.Add(xx))) {
+		t.Fail()
+	}
+	c.Downdate(x)
+	if !MEqual(c.L, CholeskyDecomposition(a)) {
+		t.Fail()
+	}
+	// downdate to indefinite matrix fails and keeps the factorization
+	L := Copy(c.L)
+	if err := c.TryDowndate(x.MulNum(100)); !errors.Is(err, ErrNotPositiveDefinite) || !MEqual(c.L, L) {
+		t.Fail()
+	}
+}
+
 func BenchmarkCholeskyDecomposition(b *testing.B) {
 	for k := 1.0; k <= 3; k++ {
 		n := int(math.Pow(10, k))
@@ -2,6 +2,7 @@ package matrix
 
 import (
 	"math"
+	"math/cmplx"
 )
 
 // EigenDecompose does Eigen decomposition of matrix
@@ -17,13 +18,29 @@ import (
 //	for non-symmetric case: eigen values are disordered
 //	eigen vector in column-wise (Eigen33 in row-wise)
 func EigenDecompose(A *Matrix) (V, D *Matrix) {
+	V, D, err := TryEigenDecompose(A)
+	must(err)
+	return
+}
+
+// TryEigenDecompose does Eigen decomposition of matrix like `EigenDecompose`, ErrNotSquare for non-square matrix
+func TryEigenDecompose(A *Matrix) (V, D *Matrix, err error) {
 	m, n := A.Dims()
 	if m != n {
-		panic("Eigen Decomposition requires Squared Matrix")
+		return nil, nil, squareError("EigenDecompose", m, n)
 	}
-	Va := Copy(A).Data
-	d := make([]float64, n)
-	e := make([]float64, n)
+	Va, d, e := eigen(A)
+	V, D = new(Matrix).Init(Va), getDiagonalMatrix(d, e) // column as vector
+	return V, D, nil
+}
+
+// eigen returns eigen vectors (in columns) with real parts d and imaginary parts e of eigen values
+//	for complex pair with e[k] > 0, V[:, k] +/- i * V[:, k+1] are eigen vectors of d[k] +/- i * e[k]
+func eigen(A *Matrix) (Va Data, d, e []float64) {
+	n := len(A.Data)
+	Va = Copy(A).Data
+	d = make([]float64, n)
+	e = make([]float64, n)
 	if A.IsSymmetric() {
 		// Tridiagonalize.
 		tred2(Va[:], d[:], e[:])
@@ -37,10 +54,96 @@ func EigenDecompose(A *Matrix) (V, D *Matrix) {
 		// Reduce Hessenberg to real Schur form.
 		hqr2(Va[:], H[:], d[:], e[:])
 	}
-	V, D = new(Matrix).Init(Va), getDiagonalMatrix(d, e) // column as vector
 	return
 }
 
+// ComplexEigen is Eigen decomposition of real square matrix A with complex eigen values and eigen vectors
+//	A * Vectors.Col(k) = Values[k] * Vectors.Col(k)
+//	Left.Col(k)ᴴ * A = Values[k] * Left.Col(k)ᴴ
+//	order of eigen values is the same as `EigenDecompose`, complex conjugate pairs are adjacent with positive imaginary part first
+type ComplexEigen struct {
+	Values  []complex128   // eigen values
+	Vectors *ComplexMatrix // right eigen vectors in columns, unit 2-norm, largest element of complex vectors is real
+	Left    *ComplexMatrix // left eigen vectors in columns, unit 2-norm, nil if A is defective (right eigen vectors are dependent)
+	Cond    []float64      // condition numbers 1 / |Left.Col(k)ᴴ * Vectors.Col(k)| of eigen values, +Inf if Left is nil
+}
+
+// EigenDecomposeComplex returns complex Eigen decomposition of square matrix, it panics for non-square matrix
+//	no need to decode the 2 x 2 blocks of `EigenDecompose` result for complex eigen values
+//	large Cond[k] means Values[k] is sensitive to perturbation of A, it is 1 for symmetric (normal) matrix
+func EigenDecomposeComplex(A *Matrix) *ComplexEigen {
+	ce, err := TryEigenDecomposeComplex(A)
+	must(err)
+	return ce
+}
+
+// TryEigenDecomposeComplex does Eigen decomposition like `EigenDecomposeComplex`, ErrNotSquare for non-square matrix
+func TryEigenDecomposeComplex(A *Matrix) (*ComplexEigen, error) {
+	m, n := A.Dims()
+	if m != n {
+		return nil, squareError("EigenDecomposeComplex", m, n)
+	}
+	Va, d, e := eigen(A)
+	ce := &ComplexEigen{Values: make([]complex128, n), Vectors: ZeroComplexMatrix(n, n), Cond: make([]float64, n)}
+	for k := 0; k < n; k++ {
+		ce.Values[k] = complex(d[k], e[k])
+		switch {
+		case e[k] == 0:
+			for i := 0; i < n; i++ {
+				ce.Vectors.Data[i][k] = complex(Va[i][k], 0)
+			}
+		case e[k] > 0:
+			for i := 0; i < n; i++ {
+				ce.Vectors.Data[i][k] = complex(Va[i][k], Va[i][k+1])
+			}
+		default: // conjugate of the previous one
+			for i := 0; i < n; i++ {
+				ce.Vectors.Data[i][k] = cmplx.Conj(ce.Vectors.Data[i][k-1])
+			}
+			continue
+		}
+		normalizeEigenVector(ce.Vectors, k, e[k] != 0)
+	}
+
+	// rows of V⁻¹ are conjugated left eigen vectors, and Vᴴ * U = I gives |u_k|⁻¹ = |u_kᴴ * v_k| / |u_k| for unit v_k
+	U, err := ce.Vectors.H().TryInverse()
+	if err != nil {
+		for k := range ce.Cond {
+			ce.Cond[k] = math.Inf(1)
+		}
+		return ce, nil
+	}
+	for k := 0; k < n; k++ {
+		ce.Cond[k] = normalizeEigenVector(U, k, false)
+	}
+	ce.Left = U
+	return ce, nil
+}
+
+// normalizeEigenVector scales column k of V to unit 2-norm, and rotates it so its largest element is real if rotate is true
+//	it returns the 2-norm before scaling
+func normalizeEigenVector(V *ComplexMatrix, k int, rotate bool) float64 {
+	col := V.Col(k)
+	norm := complexNorm(col)
+	if norm == 0 {
+		return 0
+	}
+	s := complex(1/norm, 0)
+	if rotate {
+		imax := 0
+		for i, v := range col {
+			if cmplx.Abs(v) > cmplx.Abs(col[imax]) {
+				imax = i
+			}
+		}
+		s *= cmplx.Conj(col[imax]) / complex(cmplx.Abs(col[imax]), 0)
+	}
+	for i := range V.Data {
+		V.Data[i][k] *= s
+	}
+	return norm
+}
+
 func getDiagonalMatrix(d, e []float64) *Matrix {
 	n := len(d)
 	D := ZeroMatrix(n, n)
@@ -762,7 +865,7 @@ func hqr2(V, H Data, d, e []float64) {
 							H[i+1][n-1] = (-ra - w*H[i][n-1] + q*H[i][n]) / x
 							H[i+1][n] = (-sa - w*H[i][n] - q*H[i][n-1]) / x
 						} else {
-							cdiv(-r-y*H[i][n-1], -s-y*H[i][n], z, q)
+							cdivr, cdivi = cdiv(-r-y*H[i][n-1], -s-y*H[i][n], z, q)
 							H[i+1][n-1] = cdivr
 							H[i+1][n] = cdivi
 						}
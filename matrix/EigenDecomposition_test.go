@@ -1,8 +1,10 @@
 package matrix
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/cmplx"
 	"strconv"
 	"testing"
 )
@@ -101,3 +103,57 @@ func BenchmarkEigen33(b *testing.B) {
 		}
 	})
 }
+
+func TestEigenDecomposeComplex(t *testing.T) {
+	// rotation generator has eigen values +/- i
+	ce := EigenDecomposeComplex(new(Matrix).Init(Data{{0, -1}, {1, 0}}))
+	if ce.Values[0] != complex(0, 1) || ce.Values[1] != complex(0, -1) {
+		t.Fatal(ce.Values)
+	}
+	for _, c := range ce.Cond {
+		if !FloatEqual(c, 1) {
+			t.Fail()
+		}
+	}
+
+	check := func(A *Matrix) {
+		n := len(A.Data)
+		cA := NewComplexMatrix(A, nil)
+		ce := EigenDecomposeComplex(A)
+		if ce.Left == nil {
+			t.Fatal("missing left eigen vectors")
+		}
+		for k, lambda := range ce.Values {
+			v, u := ce.Vectors.Col(k), ce.Left.Col(k)
+			Av := cA.MulVec(v)
+			uA := cA.H().MulVec(u) // (uᴴ A)ᴴ = Aᴴ u
+			for i := 0; i < n; i++ {
+				if cmplx.Abs(Av[i]-lambda*v[i]) > 1e-9 || cmplx.Abs(uA[i]-cmplx.Conj(lambda)*u[i]) > 1e-9 {
+					t.Fatal(k, lambda)
+				}
+			}
+			if !FloatEqual(complexNorm(v), 1) || !FloatEqual(complexNorm(u), 1) || ce.Cond[k] < 1-1e-9 {
+				t.Fail()
+			}
+			if imag(lambda) > 0 && ce.Values[k+1] != cmplx.Conj(lambda) {
+				t.Fail()
+			}
+		}
+	}
+	check(new(Matrix).Init(Data{{1, 2, 3}, {7, 5, 6}, {7, 4, 9}}))
+	check(new(Matrix).Init(Data{{0.9, 0.1, 0, 0}, {0, 0.5, 0.5, 0}, {0.3, 0, 0.2, 0.5}, {0.6, 0, 0, 0.4}}))
+	check(GenerateRandomSquareMatrix(8))
+	check(GenerateRandomSymmetric33Matrix())
+
+	// triangular 2 x 2: condition number is sqrt(1 + (b / (a - d))^2)
+	ce = EigenDecomposeComplex(new(Matrix).Init(Data{{1, 1e4}, {0, 2}}))
+	for _, c := range ce.Cond {
+		if math.Abs(c-math.Sqrt(1+1e8)) > 1e-6 {
+			t.Fatal(ce.Cond)
+		}
+	}
+
+	if _, err := TryEigenDecomposeComplex(ZeroMatrix(2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,489 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// SymGeneralizedEigenDecompose solves symmetric-definite generalized eigen problem
+//	A * x = lambda * B * x, A symmetric, B symmetric positive definite
+//	reduced to standard problem by `CholeskyDecomposition` B = L * L.T(): L⁻¹ * A * L⁻ᵀ * y = lambda * y, x = L⁻ᵀ * y
+//	eigen values are real and in ascending order, eigen vectors are in columns of V and B-orthonormal: V.T() * B * V = I
+//	only lower triangles of A and B are referenced
+func SymGeneralizedEigenDecompose(A, B *Matrix) (V *Matrix, D *Vector) {
+	V, D, err := TrySymGeneralizedEigenDecompose(A, B)
+	must(err)
+	return
+}
+
+// TrySymGeneralizedEigenDecompose solves A * x = lambda * B * x like `SymGeneralizedEigenDecompose`
+//	ErrNotSquare, ErrDimensionMismatch, or ErrNotPositiveDefinite if B is not positive definite
+func TrySymGeneralizedEigenDecompose(A, B *Matrix) (V *Matrix, D *Vector, err error) {
+	n, err := pencilDims("SymGeneralizedEigenDecompose", A, B)
+	if err != nil {
+		return nil, nil, err
+	}
+	L, err := TryCholeskyDecomposition(lowerSymmetric(B))
+	if err != nil {
+		return nil, nil, fmt.Errorf("SymGeneralizedEigenDecompose: %w", err)
+	}
+	// C = L⁻¹ * A * L⁻ᵀ = L⁻¹ * (L⁻¹ * A)ᵀ as A is symmetric
+	C := solveLowerMatrix(L, solveLowerMatrix(L, lowerSymmetric(A)).T())
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			C.Data[i][j] = (C.Data[i][j] + C.Data[j][i]) / 2
+			C.Data[j][i] = C.Data[i][j]
+		}
+	}
+	W, d := EigenDecompose(C)
+	// V = L⁻ᵀ * W by backward substitution, column by column
+	V = ZeroMatrix(n, n)
+	for k := 0; k < n; k++ {
+		for i := n - 1; i >= 0; i-- {
+			s := W.Data[i][k]
+			for j := i + 1; j < n; j++ {
+				s -= L.Data[j][i] * V.Data[j][k]
+			}
+			V.Data[i][k] = s / L.Data[i][i]
+		}
+	}
+	D = d.GetDiagonalElements()
+	return V, D, nil
+}
+
+// pencilDims checks A and B are square matrices of the same size
+func pencilDims(op string, A, B *Matrix) (int, error) {
+	m, n := A.Dims()
+	if m != n {
+		return 0, squareError(op, m, n)
+	}
+	if m2, n2 := B.Dims(); m2 != m || n2 != n {
+		return 0, dimsError(op, m, n, m2, n2)
+	}
+	return n, nil
+}
+
+// lowerSymmetric returns symmetric matrix built from lower triangle of t
+func lowerSymmetric(t *Matrix) *Matrix {
+	res := Copy(t)
+	for i := range res.Data {
+		for j := 0; j < i; j++ {
+			res.Data[j][i] = res.Data[i][j]
+		}
+	}
+	return res
+}
+
+// solveLowerMatrix returns L⁻¹ * X by forward substitution, L is lower triangular
+func solveLowerMatrix(L, X *Matrix) *Matrix {
+	res := Copy(X)
+	for i := range res.Data {
+		for k := 0; k < i; k++ {
+			if l := L.Data[i][k]; l != 0 {
+				for j := range res.Data[i] {
+					res.Data[i][j] -= l * res.Data[k][j]
+				}
+			}
+		}
+		for j := range res.Data[i] {
+			res.Data[i][j] /= L.Data[i][i]
+		}
+	}
+	return res
+}
+
+// GeneralizedEigen is solution of general generalized eigen problem A * x = lambda * B * x by QZ algorithm
+//	lambda = Alpha[k] / Beta[k], Beta[k] == 0 for infinite eigen value (singular B)
+//	eigen pairs are sorted: finite eigen values by ascending real part then imaginary part, followed by infinite ones
+type GeneralizedEigen struct {
+	Alpha   []complex128   // numerators of eigen values
+	Beta    []float64      // non-negative denominators of eigen values
+	Vectors *ComplexMatrix // eigen vectors in columns, beta * A * x = alpha * B * x, unit 2-norm, largest element is real
+}
+
+// Values returns eigen values Alpha[k] / Beta[k], infinite eigen value is `cmplx.Inf()`
+func (ge *GeneralizedEigen) Values() []complex128 {
+	values := make([]complex128, len(ge.Alpha))
+	for k := range values {
+		if ge.Beta[k] == 0 {
+			values[k] = cmplx.Inf()
+		} else {
+			values[k] = ge.Alpha[k] / complex(ge.Beta[k], 0)
+		}
+	}
+	return values
+}
+
+// GeneralizedEigenDecompose solves generalized eigen problem A * x = lambda * B * x for any square A and B
+//	it uses complex QZ algorithm, Qᴴ * A * Z and Qᴴ * B * Z are reduced to upper triangular S and T,
+//	then lambda = S[k][k] / T[k][k] and eigen vectors are got by back substitution of (T[k][k] * S - S[k][k] * T) * y = 0, x = Z * y
+//	use `SymGeneralizedEigenDecompose` for symmetric A and positive definite B, it gives real and B-orthonormal results
+func GeneralizedEigenDecompose(A, B *Matrix) *GeneralizedEigen {
+	ge, err := TryGeneralizedEigenDecompose(A, B)
+	must(err)
+	return ge
+}
+
+// TryGeneralizedEigenDecompose solves A * x = lambda * B * x like `GeneralizedEigenDecompose`
+//	ErrNotSquare, ErrDimensionMismatch, or ErrBreakdown if QZ iteration does not converge
+func TryGeneralizedEigenDecompose(A, B *Matrix) (*GeneralizedEigen, error) {
+	n, err := pencilDims("GeneralizedEigenDecompose", A, B)
+	if err != nil {
+		return nil, err
+	}
+	S, T := NewComplexMatrix(A, nil), NewComplexMatrix(B, nil)
+	Q, Z := ComplexIdentity(n), ComplexIdentity(n)
+	hessenbergTriangular(S.Data, T.Data, Q.Data, Z.Data)
+	if err = qzIterate(S.Data, T.Data, Q.Data, Z.Data); err != nil {
+		return nil, err
+	}
+
+	ge := &GeneralizedEigen{Alpha: make([]complex128, n), Beta: make([]float64, n), Vectors: ZeroComplexMatrix(n, n)}
+	for k := 0; k < n; k++ {
+		ge.Alpha[k], ge.Beta[k] = S.Data[k][k], real(T.Data[k][k])
+	}
+	Y := qzEigenVectors(S.Data, T.Data)
+	X := Z.Mul(Y)
+	for k := 0; k < n; k++ {
+		normalizeEigenVector(X, k, true)
+	}
+
+	// sort eigen pairs
+	values := ge.Values()
+	order := make([]int, n)
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		va, vb := values[order[a]], values[order[b]]
+		if ia, ib := cmplx.IsInf(va), cmplx.IsInf(vb); ia || ib {
+			return !ia && ib
+		}
+		if real(va) != real(vb) {
+			return real(va) < real(vb)
+		}
+		return imag(va) < imag(vb)
+	})
+	alpha, beta := append([]complex128{}, ge.Alpha...), append([]float64{}, ge.Beta...)
+	for k, o := range order {
+		ge.Alpha[k], ge.Beta[k] = alpha[o], beta[o]
+		for i := 0; i < n; i++ {
+			ge.Vectors.Data[i][k] = X.Data[i][o]
+		}
+	}
+	return ge, nil
+}
+
+// givens returns complex plane rotation [c s; -conj(s) c] mapping [f; g] to [r; 0], c is real
+func givens(f, g complex128) (c float64, s, r complex128) {
+	if g == 0 {
+		return 1, 0, f
+	}
+	if f == 0 {
+		absG := cmplx.Abs(g)
+		return 0, cmplx.Conj(g) / complex(absG, 0), complex(absG, 0)
+	}
+	absF := cmplx.Abs(f)
+	norm := math.Hypot(absF, cmplx.Abs(g))
+	phase := f / complex(absF, 0)
+	return absF / norm, phase * cmplx.Conj(g) / complex(norm, 0), phase * complex(norm, 0)
+}
+
+// rotateRows applies rotation to rows x, y of M on columns [from, to)
+func rotateRows(M [][]complex128, x, y, from, to int, c float64, s complex128) {
+	cc := complex(c, 0)
+	for j := from; j < to; j++ {
+		a, b := M[x][j], M[y][j]
+		M[x][j] = cc*a + s*b
+		M[y][j] = cc*b - cmplx.Conj(s)*a
+	}
+}
+
+// rotateCols applies rotation to columns x, y of M on rows [from, to)
+func rotateCols(M [][]complex128, x, y, from, to int, c float64, s complex128) {
+	cc := complex(c, 0)
+	for i := from; i < to; i++ {
+		a, b := M[i][x], M[i][y]
+		M[i][x] = cc*a + s*b
+		M[i][y] = cc*b - cmplx.Conj(s)*a
+	}
+}
+
+// hessenbergTriangular reduces H to upper Hessenberg and T to upper triangular form by Givens rotations
+//	rotations are accumulated: Q * H * Zᴴ and Q * T * Zᴴ are kept unchanged
+func hessenbergTriangular(H, T, Q, Z [][]complex128) {
+	n := len(H)
+	// QR of T
+	for k := 0; k < n; k++ {
+		for i := n - 1; i > k; i-- {
+			c, s, r := givens(T[i-1][k], T[i][k])
+			T[i-1][k], T[i][k] = r, 0
+			rotateRows(T, i-1, i, k+1, n, c, s)
+			rotateRows(H, i-1, i, 0, n, c, s)
+			rotateCols(Q, i-1, i, 0, n, c, cmplx.Conj(s))
+		}
+	}
+	// zero H below sub-diagonal column by column, restore T from the right
+	for j := 0; j < n-2; j++ {
+		for i := n - 1; i > j+1; i-- {
+			c, s, r := givens(H[i-1][j], H[i][j])
+			H[i-1][j], H[i][j] = r, 0
+			rotateRows(H, i-1, i, j+1, n, c, s)
+			rotateRows(T, i-1, i, i-1, n, c, s)
+			rotateCols(Q, i-1, i, 0, n, c, cmplx.Conj(s))
+
+			c, s, r = givens(T[i][i], T[i][i-1])
+			T[i][i], T[i][i-1] = r, 0
+			rotateCols(T, i, i-1, 0, i, c, s)
+			rotateCols(H, i, i-1, 0, n, c, s)
+			rotateCols(Z, i, i-1, 0, n, c, s)
+		}
+	}
+}
+
+// abs1 is cheap complex magnitude |re| + |im|
+func abs1(z complex128) float64 {
+	return math.Abs(real(z)) + math.Abs(imag(z))
+}
+
+// qzIterate reduces Hessenberg-triangular pair (H, T) to generalized Schur form (both upper triangular) by single shift QZ iteration
+//	diagonal of T is made real and non-negative, this is derived from LAPACK zhgeqz
+func qzIterate(H, T, Q, Z [][]complex128) error {
+	n := len(H)
+	if n == 0 {
+		return nil
+	}
+	const safmin = 0x1p-1022
+	ulp := math.Pow(2, -52)
+	var anorm, bnorm float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			anorm = math.Hypot(anorm, cmplx.Abs(H[i][j]))
+			bnorm = math.Hypot(bnorm, cmplx.Abs(T[i][j]))
+		}
+	}
+	atol := math.Max(safmin, ulp*anorm)
+	btol := math.Max(safmin, ulp*bnorm)
+
+	ilast := n - 1
+	iiter := 0
+	var eshift complex128
+	for sweeps := 0; ilast >= 0; {
+		// action for the active block ending at ilast: split off infinite eigen value, accept eigen value, or QZ sweep from ifirst
+		const (
+			found = iota
+			infinite
+			sweep
+		)
+		action, ifirst := found, 0
+		switch {
+		case ilast == 0:
+		case abs1(H[ilast][ilast-1]) <= math.Max(safmin, ulp*(abs1(H[ilast][ilast])+abs1(H[ilast-1][ilast-1]))):
+			H[ilast][ilast-1] = 0
+		case cmplx.Abs(T[ilast][ilast]) <= btol:
+			T[ilast][ilast] = 0
+			action = infinite
+		default:
+			for j := ilast - 1; j >= 0; j-- {
+				ilazro := j == 0
+				if !ilazro && abs1(H[j][j-1]) <= math.Max(safmin, ulp*(abs1(H[j][j])+abs1(H[j-1][j-1]))) {
+					H[j][j-1] = 0
+					ilazro = true
+				}
+				if cmplx.Abs(T[j][j]) >= btol {
+					if ilazro {
+						action, ifirst = sweep, j
+						break
+					}
+					continue
+				}
+				T[j][j] = 0
+				// two consecutive small sub-diagonals of H
+				ilazr2 := !ilazro && abs1(H[j][j-1])*abs1(H[j+1][j]) <= abs1(H[j][j])*atol
+				action = infinite
+				if ilazro || ilazr2 {
+					// leading diagonal element of T in the block is zero, split a 1 x 1 block off at the top
+					for jch := j; jch < ilast; jch++ {
+						c, s, r := givens(H[jch][jch], H[jch+1][jch])
+						H[jch][jch], H[jch+1][jch] = r, 0
+						rotateRows(H, jch, jch+1, jch+1, n, c, s)
+						rotateRows(T, jch, jch+1, jch+1, n, c, s)
+						rotateCols(Q, jch, jch+1, 0, n, c, cmplx.Conj(s))
+						if ilazr2 {
+							H[jch][jch-1] *= complex(c, 0)
+						}
+						ilazr2 = false
+						if cmplx.Abs(T[jch+1][jch+1]) >= btol {
+							if jch+1 >= ilast {
+								action = found
+							} else {
+								action, ifirst = sweep, jch+1
+							}
+							break
+						}
+						T[jch+1][jch+1] = 0
+					}
+				} else {
+					// chase the zero to T[ilast][ilast]
+					for jch := j; jch < ilast; jch++ {
+						c, s, r := givens(T[jch][jch+1], T[jch+1][jch+1])
+						T[jch][jch+1], T[jch+1][jch+1] = r, 0
+						rotateRows(T, jch, jch+1, jch+2, n, c, s)
+						rotateRows(H, jch, jch+1, jch-1, n, c, s)
+						rotateCols(Q, jch, jch+1, 0, n, c, cmplx.Conj(s))
+
+						c, s, r = givens(H[jch+1][jch], H[jch+1][jch-1])
+						H[jch+1][jch], H[jch+1][jch-1] = r, 0
+						rotateCols(H, jch, jch-1, 0, jch+1, c, s)
+						rotateCols(T, jch, jch-1, 0, jch, c, s)
+						rotateCols(Z, jch, jch-1, 0, n, c, s)
+					}
+				}
+				break
+			}
+		}
+
+		switch action {
+		case infinite:
+			// T[ilast][ilast] is zero, clear H[ilast][ilast-1] to split off a 1 x 1 block
+			c, s, r := givens(H[ilast][ilast], H[ilast][ilast-1])
+			H[ilast][ilast], H[ilast][ilast-1] = r, 0
+			rotateCols(H, ilast, ilast-1, 0, ilast, c, s)
+			rotateCols(T, ilast, ilast-1, 0, ilast, c, s)
+			rotateCols(Z, ilast, ilast-1, 0, n, c, s)
+			fallthrough
+		case found:
+			// H[ilast][ilast-1] is zero, make T[ilast][ilast] real and non-negative
+			if absB := cmplx.Abs(T[ilast][ilast]); absB > safmin {
+				sign := cmplx.Conj(T[ilast][ilast] / complex(absB, 0))
+				T[ilast][ilast] = complex(absB, 0)
+				for i := 0; i < ilast; i++ {
+					T[i][ilast] *= sign
+				}
+				for i := 0; i <= ilast; i++ {
+					H[i][ilast] *= sign
+				}
+				for i := 0; i < n; i++ {
+					Z[i][ilast] *= sign
+				}
+			} else {
+				T[ilast][ilast] = 0
+			}
+			ilast--
+			iiter = 0
+			eshift = 0
+			continue
+		}
+
+		// QZ sweep on H[ifirst:ilast+1][ifirst:ilast+1]
+		if sweeps++; sweeps > 30*n {
+			return fmt.Errorf("GeneralizedEigenDecompose: %w (QZ iteration does not converge)", ErrBreakdown)
+		}
+		iiter++
+		var shift complex128
+		if iiter%10 != 0 {
+			// Wilkinson-like shift from the trailing 2 x 2 block of T⁻¹ * H
+			u12 := T[ilast-1][ilast] / T[ilast][ilast]
+			ad11 := H[ilast-1][ilast-1] / T[ilast-1][ilast-1]
+			ad21 := H[ilast][ilast-1] / T[ilast-1][ilast-1]
+			ad12 := H[ilast-1][ilast] / T[ilast][ilast]
+			ad22 := H[ilast][ilast] / T[ilast][ilast]
+			abi22 := ad22 - u12*ad21
+			abi12 := ad12 - u12*ad11
+			shift = abi22
+			ctemp := cmplx.Sqrt(abi12) * cmplx.Sqrt(ad21)
+			if temp := abs1(ctemp); ctemp != 0 {
+				x := (ad11 - shift) / 2
+				temp2 := abs1(x)
+				temp = math.Max(temp, temp2)
+				y := complex(temp, 0) * cmplx.Sqrt((x/complex(temp, 0))*(x/complex(temp, 0))+(ctemp/complex(temp, 0))*(ctemp/complex(temp, 0)))
+				if temp2 > 0 && real(x/complex(temp2, 0))*real(y)+imag(x/complex(temp2, 0))*imag(y) < 0 {
+					y = -y
+				}
+				shift -= ctemp * (ctemp / (x + y))
+			}
+		} else {
+			// exceptional shift
+			if iiter%20 == 0 && abs1(T[ilast][ilast]) > safmin {
+				eshift += H[ilast][ilast] / T[ilast][ilast]
+			} else {
+				eshift += H[ilast][ilast-1] / T[ilast-1][ilast-1]
+			}
+			shift = eshift
+		}
+
+		// look for two consecutive small sub-diagonals to start the sweep
+		istart := ifirst
+		ctemp := H[ifirst][ifirst] - shift*T[ifirst][ifirst]
+		for j := ilast - 1; j > ifirst; j-- {
+			c := H[j][j] - shift*T[j][j]
+			temp, temp2 := abs1(c), abs1(H[j+1][j])
+			if tempr := math.Max(temp, temp2); tempr < 1 && tempr != 0 {
+				temp, temp2 = temp/tempr, temp2/tempr
+			}
+			if abs1(H[j][j-1])*temp2 <= temp*atol {
+				istart, ctemp = j, c
+				break
+			}
+		}
+
+		// implicit single shift QZ sweep
+		c, s, _ := givens(ctemp, H[istart+1][istart])
+		for j := istart; j < ilast; j++ {
+			if j > istart {
+				var r complex128
+				c, s, r = givens(H[j][j-1], H[j+1][j-1])
+				H[j][j-1], H[j+1][j-1] = r, 0
+			}
+			rotateRows(H, j, j+1, j, n, c, s)
+			rotateRows(T, j, j+1, j, n, c, s)
+			rotateCols(Q, j, j+1, 0, n, c, cmplx.Conj(s))
+
+			var r complex128
+			c, s, r = givens(T[j+1][j+1], T[j+1][j])
+			T[j+1][j+1], T[j+1][j] = r, 0
+			rotateCols(H, j+1, j, 0, MinInt(j+2, ilast)+1, c, s)
+			rotateCols(T, j+1, j, 0, j+1, c, s)
+			rotateCols(Z, j+1, j, 0, n, c, s)
+		}
+	}
+	return nil
+}
+
+// qzEigenVectors returns eigen vectors y of upper triangular pair (S, T) in columns: (T[k][k] * S - S[k][k] * T) * y = 0
+func qzEigenVectors(S, T [][]complex128) *ComplexMatrix {
+	n := len(S)
+	const safmin = 0x1p-1022
+	ulp := math.Pow(2, -52)
+	var anorm, bnorm float64
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			anorm = math.Max(anorm, abs1(S[i][j]))
+			bnorm = math.Max(bnorm, abs1(T[i][j]))
+		}
+	}
+	Y := ZeroComplexMatrix(n, n)
+	for k := 0; k < n; k++ {
+		// scaled coefficients a = alpha, b = beta of the k-th eigen value
+		a, b := S[k][k], T[k][k]
+		if scale := math.Max(abs1(a), abs1(b)); scale > 0 {
+			a, b = a/complex(scale, 0), b/complex(scale, 0)
+		}
+		dmin := math.Max(safmin, ulp*math.Max(abs1(a)*bnorm, abs1(b)*anorm))
+		Y.Data[k][k] = 1
+		for i := k - 1; i >= 0; i-- {
+			var sum complex128
+			for j := i + 1; j <= k; j++ {
+				sum += (b*S[i][j] - a*T[i][j]) * Y.Data[j][k]
+			}
+			d := b*S[i][i] - a*T[i][i]
+			if abs1(d) < dmin {
+				d = complex(dmin, 0)
+			}
+			Y.Data[i][k] = -sum / d
+		}
+	}
+	return Y
+}
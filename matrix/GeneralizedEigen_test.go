@@ -0,0 +1,142 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// randomSPD returns random n x n symmetric positive definite matrix
+func randomSPD(n int) *Matrix {
+	X := GenerateRandomMatrix(n+2, n)
+	return X.T().Mul(X).Add(IdentityMatrix(n))
+}
+
+// pencilResidual returns max relative residual |beta * A * x - alpha * B * x| of eigen pairs
+func pencilResidual(A, B *Matrix, ge *GeneralizedEigen) float64 {
+	cA, cB := NewComplexMatrix(A, nil), NewComplexMatrix(B, nil)
+	res := 0.
+	for k := range ge.Alpha {
+		x := ge.Vectors.Col(k)
+		Ax, Bx := cA.MulVec(x), cB.MulVec(x)
+		r := make([]complex128, len(x))
+		for i := range r {
+			r[i] = complex(ge.Beta[k], 0)*Ax[i] - ge.Alpha[k]*Bx[i]
+		}
+		res = math.Max(res, complexNorm(r)/(ge.Beta[k]*A.Norm()+cmplx.Abs(ge.Alpha[k])*B.Norm()))
+	}
+	return res
+}
+
+func TestSymGeneralizedEigenDecompose(t *testing.T) {
+	n := 6
+	A := GenerateRandomSquareMatrix(n)
+	A = A.Add(A.T())
+	B := randomSPD(n)
+	V, D := SymGeneralizedEigenDecompose(A, B)
+	for k := 1; k < n; k++ {
+		if (*D)[k-1] > (*D)[k] {
+			t.Fatal(D)
+		}
+	}
+	lambda := ZeroMatrix(n, n)
+	for k, d := range *D {
+		lambda.Data[k][k] = d
+	}
+	if !MEqual(A.Mul(V), B.Mul(V).Mul(lambda)) || !MEqual(V.T().Mul(B).Mul(V), IdentityMatrix(n)) {
+		t.Fail()
+	}
+	// QZ gives the same eigen values
+	ge := GeneralizedEigenDecompose(A, B)
+	for k, v := range ge.Values() {
+		if math.Abs(imag(v)) > 1e-9 || math.Abs(real(v)-(*D)[k]) > 1e-9 {
+			t.Fatal(ge.Values(), D)
+		}
+	}
+
+	// only lower triangles are referenced
+	upper := Copy(A)
+	for i := range upper.Data {
+		for j := i + 1; j < n; j++ {
+			upper.Data[i][j] = 100
+		}
+	}
+	if _, D2 := SymGeneralizedEigenDecompose(upper, B); !VEqual(D, D2) {
+		t.Fail()
+	}
+
+	if _, _, err := TrySymGeneralizedEigenDecompose(A, A.MulNum(-1)); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+	if _, _, err := TrySymGeneralizedEigenDecompose(A, IdentityMatrix(2)); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
+func TestGeneralizedEigenDecompose(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 12} {
+		A, B := GenerateRandomSquareMatrix(n), GenerateRandomSquareMatrix(n)
+		ge := GeneralizedEigenDecompose(A, B)
+		if r := pencilResidual(A, B, ge); r > 1e-12 {
+			t.Fatal(n, r)
+		}
+		values := ge.Values()
+		for k := 1; k < n; k++ {
+			if real(values[k-1]) > real(values[k]) {
+				t.Fatal(values)
+			}
+		}
+	}
+
+	// B = I is the standard eigen problem
+	A := new(Matrix).Init(Data{{1, 2, 3}, {7, 5, 6}, {7, 4, 9}})
+	ge := GeneralizedEigenDecompose(A, IdentityMatrix(3))
+	ce := EigenDecomposeComplex(A)
+	for _, lambda := range ce.Values {
+		found := false
+		for _, v := range ge.Values() {
+			found = found || cmplx.Abs(v-lambda) < 1e-9
+		}
+		if !found {
+			t.Fatal(ce.Values, ge.Values())
+		}
+	}
+
+	// rotation pencil has eigen values +/- i
+	ge = GeneralizedEigenDecompose(new(Matrix).Init(Data{{0, -2}, {2, 0}}), IdentityMatrix(2).MulNum(2))
+	if values := ge.Values(); cmplx.Abs(values[0]-complex(0, -1)) > 1e-12 || cmplx.Abs(values[1]-complex(0, 1)) > 1e-12 {
+		t.Fatal(values)
+	}
+}
+
+func TestGeneralizedEigenDecompose_Singular(t *testing.T) {
+	// infinite eigen values are sorted last
+	ge := GeneralizedEigenDecompose(new(Matrix).Init(Data{{3, 0, 0}, {0, 1, 0}, {0, 0, 2}}), new(Matrix).Init(Data{{0, 0, 0}, {0, 1, 0}, {0, 0, 1}}))
+	values := ge.Values()
+	if cmplx.Abs(values[0]-1) > 1e-12 || cmplx.Abs(values[1]-2) > 1e-12 || !cmplx.IsInf(values[2]) || ge.Beta[2] != 0 {
+		t.Fatal(values)
+	}
+
+	// B of rank n - 1 gives one infinite eigen value
+	n := 6
+	A := GenerateRandomSquareMatrix(n)
+	B := GenerateRandomMatrix(n, n-1).Mul(GenerateRandomMatrix(n-1, n))
+	ge = GeneralizedEigenDecompose(A, B)
+	if r := pencilResidual(A, B, ge); r > 1e-10 {
+		t.Fatal(r)
+	}
+	infinite := 0
+	for _, v := range ge.Values() {
+		if cmplx.IsInf(v) || cmplx.Abs(v) > 1e10 {
+			infinite++
+		}
+	}
+	if infinite != 1 {
+		t.Fatal(ge.Values())
+	}
+
+	if _, err := TryGeneralizedEigenDecompose(ZeroMatrix(2, 3), ZeroMatrix(2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+}
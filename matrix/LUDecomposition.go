@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -14,6 +15,20 @@ import (
  *        where S is the number of row exchanges needed for determinant computation, det(P)=(-1)^S
  */
 func LUPDecompose(t *Matrix, N int, Tol float64) (*Matrix, *[]int) {
+	nt, P, err := TryLUPDecompose(t, N, Tol)
+	if err != nil {
+		return nil, nil
+	}
+	return nt, P
+}
+
+// TryLUPDecompose does LUP decomposition of matrix like `LUPDecompose`, but reports failure by error
+//	ErrNotSquare if t is not N x N, ErrSingular if the matrix is degenerate with tolerance Tol
+func TryLUPDecompose(t *Matrix, N int, Tol float64) (*Matrix, *[]int, error) {
+	row, col := t.Dims()
+	if row != N || col != N {
+		return nil, nil, squareError("LUPDecompose", row, col)
+	}
 	nt := Copy(t)
 	P := make([]int, N+1)
 	imax := 0
@@ -34,7 +49,7 @@ func LUPDecompose(t *Matrix, N int, Tol float64) (*Matrix, *[]int) {
 		}
 
 		if maxT < Tol { //failure, matrix is degenerate
-			return nil, nil
+			return nil, nil, fmt.Errorf("LUPDecompose: %w (pivot %d below tolerance %g)", ErrSingular, i, Tol)
 		}
 
 		if imax != i {
@@ -60,7 +75,7 @@ func LUPDecompose(t *Matrix, N int, Tol float64) (*Matrix, *[]int) {
 			}
 		}
 	}
-	return nt, &P
+	return nt, &P, nil
 }
 
 // LUPSolve LUP decomposition and solve equations
@@ -139,3 +154,195 @@ func LUPRank(t *Matrix, N int) int {
 	}
 	return rank
 }
+
+// decomposeLU does LUP decomposition in the layout of `LUPDecompose` with partial pivoting by the largest entry of each column
+//	it never fails: zero pivot is kept (the matrix is singular) and its column is skipped
+func decomposeLU(t *Matrix) (*Matrix, *[]int) {
+	N, _ := t.Dims()
+	nt := Copy(t)
+	P := make([]int, N+1)
+	for i := range P {
+		P[i] = i
+	}
+	for i := 0; i < N; i++ {
+		imax := i
+		for k := i + 1; k < N; k++ {
+			if math.Abs(nt.Data[k][i]) > math.Abs(nt.Data[imax][i]) {
+				imax = k
+			}
+		}
+		if imax != i {
+			P[i], P[imax] = P[imax], P[i]
+			nt.Data[i], nt.Data[imax] = nt.Data[imax], nt.Data[i]
+			P[N]++
+		}
+		if nt.Data[i][i] == 0 {
+			continue
+		}
+		for j := i + 1; j < N; j++ {
+			f := nt.Data[j][i] / nt.Data[i][i]
+			nt.Data[j][i] = f
+			if f == 0 {
+				continue
+			}
+			for k := i + 1; k < N; k++ {
+				nt.Data[j][k] -= f * nt.Data[i][k]
+			}
+		}
+	}
+	return nt, &P
+}
+
+// LU is LUP decomposition P * A = L * U of square matrix A, it is factorized once and reused for many right-hand sides
+//	LU holds L - E + U like the output of `LUPDecompose`, and P is its permutation of N + 1 integers,
+//	so both can be passed to `LUPSolve`, `LUPInvert`, `LUPDeterminant` and `LUPCondEst`
+type LU struct {
+	LU    *Matrix
+	P     []int
+	norm1 float64 // 1-norm of A for condition estimation
+}
+
+// LUFactorize returns LUP decomposition of square matrix with partial pivoting, panics for non-square matrix
+//	singular matrix is factorized too (with zero pivot), then `Det` is 0 and solves report ErrSingular
+func LUFactorize(t *Matrix) *LU {
+	lu, err := TryLUFactorize(t)
+	must(err)
+	return lu
+}
+
+// TryLUFactorize returns LUP decomposition like `LUFactorize`, ErrNotSquare for non-square matrix
+func TryLUFactorize(t *Matrix) (*LU, error) {
+	row, col := t.Dims()
+	if row != col {
+		return nil, squareError("LUFactorize", row, col)
+	}
+	nt, P := decomposeLU(t)
+	return &LU{LU: nt, P: *P, norm1: t.Norm1()}, nil
+}
+
+// size returns N of N x N factorized matrix
+func (lu *LU) size() int {
+	return len(lu.P) - 1
+}
+
+// singular returns ErrSingular with the first zero pivot, or nil
+func (lu *LU) singular(op string) error {
+	for i := 0; i < lu.size(); i++ {
+		if lu.LU.Data[i][i] == 0 {
+			return fmt.Errorf("%s: %w (zero pivot %d)", op, ErrSingular, i)
+		}
+	}
+	return nil
+}
+
+// TrySolve solves A * x = b, ErrDimensionMismatch or ErrSingular
+func (lu *LU) TrySolve(b *Vector) (*Vector, error) {
+	N := lu.size()
+	if b.Length() != N {
+		return nil, lenError("LU.Solve", N, b.Length())
+	}
+	if err := lu.singular("LU.Solve"); err != nil {
+		return nil, err
+	}
+	return LUPSolve(lu.LU, &lu.P, N, b), nil
+}
+
+// Solve solves A * x = b like `TrySolve`, panics on error
+func (lu *LU) Solve(b *Vector) *Vector {
+	x, err := lu.TrySolve(b)
+	must(err)
+	return x
+}
+
+// TrySolveMatrix solves A * X = B for all columns of B, ErrDimensionMismatch or ErrSingular
+func (lu *LU) TrySolveMatrix(B *Matrix) (*Matrix, error) {
+	N := lu.size()
+	if m, n := B.Dims(); m != N {
+		return nil, dimsError("LU.SolveMatrix", N, N, m, n)
+	}
+	if err := lu.singular("LU.SolveMatrix"); err != nil {
+		return nil, err
+	}
+	// row i of X starts as row P[i] of B, then forward and back substitution run on whole rows
+	X := ZeroMatrix(B.Dims())
+	for i := 0; i < N; i++ {
+		copy(X.Data[i], B.Data[lu.P[i]])
+		for k := 0; k < i; k++ {
+			if l := lu.LU.Data[i][k]; l != 0 {
+				for j, v := range X.Data[k] {
+					X.Data[i][j] -= l * v
+				}
+			}
+		}
+	}
+	for i := N - 1; i >= 0; i-- {
+		for k := i + 1; k < N; k++ {
+			if u := lu.LU.Data[i][k]; u != 0 {
+				for j, v := range X.Data[k] {
+					X.Data[i][j] -= u * v
+				}
+			}
+		}
+		for j := range X.Data[i] {
+			X.Data[i][j] /= lu.LU.Data[i][i]
+		}
+	}
+	return X, nil
+}
+
+// SolveMatrix solves A * X = B like `TrySolveMatrix`, panics on error
+func (lu *LU) SolveMatrix(B *Matrix) *Matrix {
+	X, err := lu.TrySolveMatrix(B)
+	must(err)
+	return X
+}
+
+// Det returns determinant of A
+func (lu *LU) Det() float64 {
+	if lu.size() == 0 {
+		return 1
+	}
+	return LUPDeterminant(lu.LU, &lu.P, lu.size())
+}
+
+// LogDet returns log(|det(A)|) and sign of det(A), it does not overflow for large matrices like `Det`
+//	singular matrix gets -Inf and sign 0
+func (lu *LU) LogDet() (logAbs, sign float64) {
+	N := lu.size()
+	sign = 1
+	if (lu.P[N]-N)%2 == 1 {
+		sign = -1
+	}
+	for i := 0; i < N; i++ {
+		u := lu.LU.Data[i][i]
+		if u == 0 {
+			return math.Inf(-1), 0
+		}
+		if u < 0 {
+			sign = -sign
+		}
+		logAbs += math.Log(math.Abs(u))
+	}
+	return
+}
+
+// TryInverse returns inverse of A, ErrSingular for singular matrix
+func (lu *LU) TryInverse() (*Matrix, error) {
+	if err := lu.singular("LU.Inverse"); err != nil {
+		return nil, err
+	}
+	return LUPInvert(lu.LU, &lu.P, lu.size()), nil
+}
+
+// Inverse returns inverse of A like `TryInverse`, panics on error
+func (lu *LU) Inverse() *Matrix {
+	inv, err := lu.TryInverse()
+	must(err)
+	return inv
+}
+
+// Cond1Est returns estimation of 1-norm condition number of A by `LUPCondEst`, +Inf for singular matrix
+//	results of solves lose about log10(Cond1Est()) digits
+func (lu *LU) Cond1Est() float64 {
+	return LUPCondEst(lu.LU, &lu.P, lu.size(), lu.norm1)
+}
@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	"math"
 	"strconv"
 	"testing"
@@ -67,6 +68,52 @@ func TestLUPRank(t *testing.T) {
 	}
 }
 
+func TestLU(t *testing.T) {
+	a := new(Matrix).Init(Data{{10, 20, 10}, {-20, -30, 5}, {30, 50, 10}})
+	lu := LUFactorize(a)
+	if !VEqual(lu.Solve(&Vector{40, -40, 80}), &Vector{-4, 4, 0}) || !FloatEqual(lu.Det(), a.Det()) {
+		t.Fail()
+	}
+	if logAbs, sign := lu.LogDet(); !FloatEqual(sign*math.Exp(logAbs), lu.Det()) {
+		t.Fail()
+	}
+	if !MEqual(lu.Inverse().Mul(a), IdentityMatrix(3)) {
+		t.Fail()
+	}
+	B := GenerateRandomMatrix(3, 4)
+	if !MEqual(a.Mul(lu.SolveMatrix(B)), B) {
+		t.Fail()
+	}
+	// zero leading pivot needs row exchange
+	a = new(Matrix).Init(Data{{0, 2, 1}, {1, 0, 0}, {0, 1, 3}})
+	if lu = LUFactorize(a); !VEqual(a.MulVec(lu.Solve(&Vector{1, 2, 3})), &Vector{1, 2, 3}) || !FloatEqual(lu.Det(), -5) {
+		t.Fail()
+	}
+	// large matrix: determinant overflows but log determinant does not
+	a = IdentityMatrix(400).MulNum(10)
+	a.Set(0, 0, -10)
+	if logAbs, sign := LUFactorize(a).LogDet(); !math.IsInf(LUFactorize(a).Det(), -1) || sign != -1 || !FloatEqual(logAbs, 400*math.Log(10)) {
+		t.Fail()
+	}
+	// singular matrix
+	lu = LUFactorize(new(Matrix).Init(Data{{1, 2}, {2, 4}}))
+	if _, err := lu.TrySolve(&Vector{1, 2}); !errors.Is(err, ErrSingular) || lu.Det() != 0 || !math.IsInf(lu.Cond1Est(), 1) {
+		t.Fail()
+	}
+	if _, err := lu.TryInverse(); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if logAbs, sign := lu.LogDet(); !math.IsInf(logAbs, -1) || sign != 0 {
+		t.Fail()
+	}
+	if _, err := TryLUFactorize(ZeroMatrix(2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, err := LUFactorize(IdentityMatrix(2)).TrySolveMatrix(ZeroMatrix(3, 1)); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
 func BenchmarkLUPDeterminant(b *testing.B) {
 	for k := 1.0; k <= 3; k++ {
 		n := int(math.Pow(10, k))
@@ -107,3 +154,12 @@ func BenchmarkLUPDecompose(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkLU_Solve(b *testing.B) {
+	lu := LUFactorize(GenerateRandomSquareMatrix(100))
+	v := GenerateRandomVector(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lu.Solve(v)
+	}
+}
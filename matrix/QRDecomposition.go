@@ -1,5 +1,10 @@
 package matrix
 
+import (
+	"fmt"
+	"math"
+)
+
 // Sign returns sign (float64) of input number (float64)
 func Sign(a float64) float64 {
 	if a > 0 {
@@ -33,24 +38,321 @@ func Householder(t *Matrix) *Matrix {
 	return IdentityMatrix(m).Sub(prod.MulNum(beta))
 }
 
-// QRDecomposition does QR-Decomposition based on `Householder`
+// QRDecomposition does QR-Decomposition based on compact Householder `QR`, returns full m x m Q and m x n R
 func QRDecomposition(t *Matrix) (*Matrix, *Matrix) {
-	// TODO: need optimize, many `Mul` -> optimize `Mul` to run in parallel
+	qr := NewQR(t)
+	return qr.Q(), qr.R()
+}
+
+// EconomyQRDecomposition does thin QR-Decomposition, returns m x k Q and k x n R, k = min(m, n)
+//	for tall-skinny matrix it costs O(m*n^2) instead of O(m^3) of the full one
+func EconomyQRDecomposition(t *Matrix) (*Matrix, *Matrix) {
+	qr := NewQR(t)
+	return qr.ThinQ(), qr.ThinR()
+}
+
+// QR is compact Householder QR decomposition A * P = Q * R of m x n matrix A
+//	https://en.wikipedia.org/wiki/QR_decomposition#Using_Householder_reflections
+//	R is stored in upper triangle of QR, reflector v_k (v_k[k] = 1 implicitly) below the diagonal of column k,
+//	H_k = I - Tau[k] * v_k * v_k', Q = H_0 * H_1 * ... * H_{min(m, n)-1}, neither Q nor H_k is formed explicitly
+//	Perm is column permutation of pivoted QR (column j of A * P is column Perm[j] of A), nil means P = I
+type QR struct {
+	QR   *Matrix
+	Tau  []float64
+	Perm []int
+}
+
+// NewQR returns compact Householder QR decomposition of t without pivoting, t is not modified
+func NewQR(t *Matrix) *QR {
+	m, n := t.Dims()
+	qr := &QR{QR: Copy(t), Tau: make([]float64, MinInt(m, n))}
+	for k := range qr.Tau {
+		qr.reflect(k)
+	}
+	return qr
+}
+
+// NewPivotedQR returns column pivoted QR decomposition (Businger-Golub), t is not modified
+//	at step k the remaining column with the largest norm is moved to column k, so |R[0][0]| >= |R[1][1]| >= ...
+//	and the decomposition is rank revealing, see `QR.Rank`
+//	column norms are downdated at each step and recomputed when cancellation occurs (LAPACK Working Note 176)
+func NewPivotedQR(t *Matrix) *QR {
 	m, n := t.Dims()
-	q := IdentityMatrix(m)
-	r := Copy(t)
-
-	last := n - 1
-	if m == n {
-		last--
-	}
-	for i := 0; i <= last; i++ {
-		b := r.GetSubMatrix(i, i, m-i, n-i)
-		x := b.Col(0).ToMatrix(n-i, 1)
-		h := IdentityMatrix(m)
-		h.SetSubMatrix(i, i, Householder(x))
-		q = q.Mul(h)
-		r = h.Mul(r)
-	}
-	return q, r
+	qr := &QR{QR: Copy(t), Tau: make([]float64, MinInt(m, n)), Perm: make([]int, n)}
+	a := qr.QR.Data
+	norms, ref := make([]float64, n), make([]float64, n)
+	for j := range qr.Perm {
+		qr.Perm[j] = j
+		norms[j] = qr.colNorm(j, 0)
+		ref[j] = norms[j]
+	}
+	tol := math.Sqrt(math.Pow(2, -52))
+	for k := range qr.Tau {
+		p := k
+		for j := k + 1; j < n; j++ {
+			if norms[j] > norms[p] {
+				p = j
+			}
+		}
+		if p != k {
+			for i := range a {
+				a[i][k], a[i][p] = a[i][p], a[i][k]
+			}
+			qr.Perm[k], qr.Perm[p] = qr.Perm[p], qr.Perm[k]
+			norms[p], ref[p] = norms[k], ref[k]
+		}
+		qr.reflect(k)
+		for j := k + 1; j < n; j++ {
+			if norms[j] == 0 {
+				continue
+			}
+			r := math.Abs(a[k][j]) / norms[j]
+			r = math.Max(0, (1+r)*(1-r))
+			if r*(norms[j]/ref[j])*(norms[j]/ref[j]) <= tol {
+				norms[j] = qr.colNorm(j, k+1)
+				ref[j] = norms[j]
+			} else {
+				norms[j] *= math.Sqrt(r)
+			}
+		}
+	}
+	return qr
+}
+
+// reflect computes reflector H_k annihilating QR[k+1:][k] and applies it to the trailing columns
+func (qr *QR) reflect(k int) {
+	a := qr.QR.Data
+	m, n := qr.QR.Dims()
+	alpha, xNorm := a[k][k], qr.colNorm(k, k+1)
+	if xNorm == 0 {
+		qr.Tau[k] = 0
+		return
+	}
+	beta := -math.Copysign(math.Hypot(alpha, xNorm), alpha)
+	qr.Tau[k] = (beta - alpha) / beta
+	s := 1 / (alpha - beta)
+	for i := k + 1; i < m; i++ {
+		a[i][k] *= s
+	}
+	a[k][k] = beta
+	for j := k + 1; j < n; j++ {
+		w := a[k][j]
+		for i := k + 1; i < m; i++ {
+			w += a[i][k] * a[i][j]
+		}
+		w *= qr.Tau[k]
+		a[k][j] -= w
+		for i := k + 1; i < m; i++ {
+			a[i][j] -= w * a[i][k]
+		}
+	}
+}
+
+// colNorm returns 2-norm of QR[from:][j]
+func (qr *QR) colNorm(j, from int) float64 {
+	scale, ssq := 0., 1.
+	for _, row := range qr.QR.Data[from:] {
+		if row[j] == 0 {
+			continue
+		}
+		a := math.Abs(row[j])
+		if scale < a {
+			ssq = 1 + ssq*(scale/a)*(scale/a)
+			scale = a
+		} else {
+			ssq += (a / scale) * (a / scale)
+		}
+	}
+	return scale * math.Sqrt(ssq)
+}
+
+// applyReflector applies H_k to vector x in place
+func (qr *QR) applyReflector(k int, x []float64) {
+	if qr.Tau[k] == 0 {
+		return
+	}
+	a := qr.QR.Data
+	w := x[k]
+	for i := k + 1; i < len(x); i++ {
+		w += a[i][k] * x[i]
+	}
+	w *= qr.Tau[k]
+	x[k] -= w
+	for i := k + 1; i < len(x); i++ {
+		x[i] -= w * a[i][k]
+	}
+}
+
+// qCols returns first cols columns of Q as m x cols matrix, by applying H_{k-1}, ..., H_0 backwards to identity columns
+func (qr *QR) qCols(cols int) *Matrix {
+	m, _ := qr.QR.Dims()
+	q := ZeroMatrix(cols, m) // column j of Q is built as row j, then transposed
+	for j := range q.Data {
+		q.Data[j][j] = 1
+		for k := len(qr.Tau) - 1; k >= 0; k-- {
+			qr.applyReflector(k, q.Data[j])
+		}
+	}
+	return q.T()
+}
+
+// Q returns full m x m orthogonal factor
+func (qr *QR) Q() *Matrix {
+	m, _ := qr.QR.Dims()
+	return qr.qCols(m)
+}
+
+// ThinQ returns economy m x min(m, n) orthogonal factor
+func (qr *QR) ThinQ() *Matrix {
+	return qr.qCols(len(qr.Tau))
+}
+
+// rRows returns first rows rows of upper triangular factor
+func (qr *QR) rRows(rows int) *Matrix {
+	_, n := qr.QR.Dims()
+	r := ZeroMatrix(rows, n)
+	for i := 0; i < rows && i < len(qr.Tau); i++ {
+		copy(r.Data[i][i:], qr.QR.Data[i][i:])
+	}
+	return r
+}
+
+// R returns full m x n upper triangular factor
+func (qr *QR) R() *Matrix {
+	m, _ := qr.QR.Dims()
+	return qr.rRows(m)
+}
+
+// ThinR returns economy min(m, n) x n upper triangular factor
+func (qr *QR) ThinR() *Matrix {
+	return qr.rRows(len(qr.Tau))
+}
+
+// P returns n x n permutation matrix, A * P = Q * R
+func (qr *QR) P() *Matrix {
+	_, n := qr.QR.Dims()
+	p := ZeroMatrix(n, n)
+	for j := 0; j < n; j++ {
+		if qr.Perm != nil {
+			p.Data[qr.Perm[j]][j] = 1
+		} else {
+			p.Data[j][j] = 1
+		}
+	}
+	return p
+}
+
+// QTMulVec returns Q' * b without forming Q
+func (qr *QR) QTMulVec(b *Vector) *Vector {
+	m, _ := qr.QR.Dims()
+	if b.Length() != m {
+		panic(lenError("QR.QTMulVec", m, b.Length()))
+	}
+	res := append(Vector{}, *b...)
+	for k := range qr.Tau {
+		qr.applyReflector(k, res)
+	}
+	return &res
+}
+
+// QMulVec returns Q * b without forming Q
+func (qr *QR) QMulVec(b *Vector) *Vector {
+	m, _ := qr.QR.Dims()
+	if b.Length() != m {
+		panic(lenError("QR.QMulVec", m, b.Length()))
+	}
+	res := append(Vector{}, *b...)
+	for k := len(qr.Tau) - 1; k >= 0; k-- {
+		qr.applyReflector(k, res)
+	}
+	return &res
+}
+
+// Rank returns numerical rank, number of |R[k][k]| > tol * |R[0][0]|
+//	tol <= 0 means max(m, n) * 2^-52, it is only reliable with `NewPivotedQR`, use SVD in doubtful cases
+func (qr *QR) Rank(tol float64) int {
+	m, n := qr.QR.Dims()
+	if len(qr.Tau) == 0 {
+		return 0
+	}
+	if tol <= 0 {
+		tol = float64(MaxInt(m, n)) * math.Pow(2, -52)
+	}
+	limit := tol * math.Abs(qr.QR.Data[0][0])
+	rank := 0
+	for k := range qr.Tau {
+		if math.Abs(qr.QR.Data[k][k]) > limit {
+			rank++
+		}
+	}
+	return rank
+}
+
+// LeastSquares is least squares solution of min ||A * X - b||
+//	Residual = ||A * X - b||, Rank is numerical rank of A used by the solution
+type LeastSquares struct {
+	X        *Vector
+	Residual float64
+	Rank     int
+}
+
+// TrySolveLeastSquares solves min ||A * x - b|| with the decomposition
+//	the leading rank columns of A * P are used and the other components of x are zero (basic solution),
+//	rank is `Rank(0)` with pivoting or min(m, n) without, in the latter case ErrSingular if some R[k][k] is zero
+func (qr *QR) TrySolveLeastSquares(b *Vector) (*LeastSquares, error) {
+	m, n := qr.QR.Dims()
+	if b.Length() != m {
+		return nil, lenError("QR.SolveLeastSquares", m, b.Length())
+	}
+	rank := len(qr.Tau)
+	if qr.Perm != nil {
+		rank = qr.Rank(0)
+	}
+	a := qr.QR.Data
+	c := *qr.QTMulVec(b)
+	y := make(Vector, n)
+	for k := rank - 1; k >= 0; k-- {
+		if a[k][k] == 0 {
+			return nil, fmt.Errorf("QR.SolveLeastSquares: %w (zero diagonal of R at %d)", ErrSingular, k)
+		}
+		s := c[k]
+		for j := k + 1; j < rank; j++ {
+			s -= a[k][j] * y[j]
+		}
+		y[k] = s / a[k][k]
+	}
+	x := y
+	if qr.Perm != nil {
+		x = make(Vector, n)
+		for j, p := range qr.Perm {
+			x[p] = y[j]
+		}
+	}
+	res := c[rank:]
+	return &LeastSquares{X: &x, Residual: res.Norm(), Rank: rank}, nil
+}
+
+// SolveLeastSquares solves like `TrySolveLeastSquares`, panics on error
+func (qr *QR) SolveLeastSquares(b *Vector) *LeastSquares {
+	ls, err := qr.TrySolveLeastSquares(b)
+	must(err)
+	return ls
+}
+
+// TrySolveLeastSquares solves min ||A * x - b|| by column pivoted QR, which is preferred over normal equations
+//	A' * A * x = A' * b as it does not square the condition number of A
+//	for rank deficient A basic solution is returned, see `QR.TrySolveLeastSquares`
+func TrySolveLeastSquares(A *Matrix, b *Vector) (*LeastSquares, error) {
+	m, _ := A.Dims()
+	if b.Length() != m {
+		return nil, lenError("SolveLeastSquares", m, b.Length())
+	}
+	return NewPivotedQR(A).TrySolveLeastSquares(b)
+}
+
+// SolveLeastSquares solves like `TrySolveLeastSquares`, panics on error
+func SolveLeastSquares(A *Matrix, b *Vector) *LeastSquares {
+	ls, err := TrySolveLeastSquares(A, b)
+	must(err)
+	return ls
 }
@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	"math"
 	"strconv"
 	"testing"
@@ -34,6 +35,98 @@ func TestQR(t *testing.T) {
 	}
 }
 
+func TestEconomyQR(t *testing.T) {
+	a := GenerateRandomMatrix(50, 4)
+	q, r := EconomyQRDecomposition(a)
+	if m, n := q.Dims(); m != 50 || n != 4 {
+		t.Fatal(m, n)
+	}
+	if m, n := r.Dims(); m != 4 || n != 4 {
+		t.Fatal(m, n)
+	}
+	if !MEqual(q.T().Mul(q), IdentityMatrix(4)) || !MEqual(q.Mul(r), a) {
+		t.Fail()
+	}
+	for i := 1; i < 4; i++ {
+		for j := 0; j < i; j++ {
+			if r.At(i, j) != 0 {
+				t.Fail()
+			}
+		}
+	}
+	// full factors of tall and wide matrices
+	for _, a := range []*Matrix{a, GenerateRandomMatrix(3, 7)} {
+		m, _ := a.Dims()
+		q, r := QRDecomposition(a)
+		if !MEqual(q.T().Mul(q), IdentityMatrix(m)) || !MEqual(q.Mul(r), a) {
+			t.Fail()
+		}
+	}
+}
+
+func TestQRMulVec(t *testing.T) {
+	a := GenerateRandomMatrix(8, 5)
+	qr := NewQR(a)
+	b := GenerateRandomVector(8)
+	if !VEqual(qr.QTMulVec(b), qr.Q().T().MulVec(b)) || !VEqual(qr.QMulVec(qr.QTMulVec(b)), b) {
+		t.Fail()
+	}
+}
+
+func TestPivotedQR(t *testing.T) {
+	// rank 2: third column = first + second, fourth = 2 * first
+	a := new(Matrix).Init(Data{{1, 2, 3, 2}, {4, 5, 9, 8}, {7, 8, 15, 14}, {1, 0, 1, 2}, {2, -1, 1, 4}})
+	qr := NewPivotedQR(a)
+	if qr.Rank(0) != 2 || NewPivotedQR(IdentityMatrix(3)).Rank(0) != 3 {
+		t.Fatal(qr.Rank(0))
+	}
+	if !MEqual(a.Mul(qr.P()), qr.Q().Mul(qr.R())) {
+		t.Fail()
+	}
+	for k := 1; k < 4; k++ {
+		if math.Abs(qr.QR.At(k, k)) > math.Abs(qr.QR.At(k-1, k-1)) {
+			t.Fail()
+		}
+	}
+}
+
+func TestSolveLeastSquares(t *testing.T) {
+	// fit y = 1 + 2x with noise, compared with normal equations
+	a := new(Matrix).Init(Data{{1, 0}, {1, 1}, {1, 2}, {1, 3}, {1, 4}})
+	b := &Vector{1.1, 2.9, 5.2, 6.8, 9.1}
+	ls := SolveLeastSquares(a, b)
+	x := a.T().Mul(a).Inverse().MulVec(a.T().MulVec(b))
+	if !VEqual(ls.X, x) || ls.Rank != 2 {
+		t.Fatal(ls.X, x)
+	}
+	if !FloatEqual(ls.Residual, a.MulVec(ls.X).Sub(b).Norm()) {
+		t.Fail()
+	}
+	// unpivoted gives the same result
+	if !VEqual(NewQR(a).SolveLeastSquares(b).X, x) {
+		t.Fail()
+	}
+	// consistent system has zero residual
+	b = a.MulVec(&Vector{3, -1})
+	if ls = SolveLeastSquares(a, b); !VEqual(ls.X, &Vector{3, -1}) || ls.Residual > 1e-12 {
+		t.Fail()
+	}
+	// rank deficient: basic solution still minimizes the residual
+	a = new(Matrix).Init(Data{{1, 1, 2}, {1, 2, 3}, {1, 3, 4}, {1, 4, 5}})
+	b = &Vector{1, 3, 2, 5}
+	ls = SolveLeastSquares(a, b)
+	if ls.Rank != 2 || !FloatEqual(ls.Residual, a.MulVec(ls.X).Sub(b).Norm()) ||
+		!FloatEqual(ls.Residual, SolveLeastSquares(a.GetSubMatrix(0, 0, 4, 2), b).Residual) {
+		t.Fail()
+	}
+	if _, err := NewQR(a).TrySolveLeastSquares(b); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if _, err := TrySolveLeastSquares(a, &Vector{1}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
 func BenchmarkQRDecomposition(b *testing.B) {
 	for k := 1.0; k <= 2; k++ {
 		n := int(math.Pow(10, k))
@@ -46,3 +139,11 @@ func BenchmarkQRDecomposition(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkEconomyQRDecomposition(b *testing.B) {
+	m := GenerateRandomMatrix(10000, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EconomyQRDecomposition(m)
+	}
+}
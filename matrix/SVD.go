@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -16,11 +17,18 @@ import (
 // 	Code from `Jama`, derived from LINPACK code
 // 	https://github.com/fiji/Jama/blob/master/src/main/java/Jama/SingularValueDecomposition.java
 func SVD(t *Matrix) (U, S, V *Matrix) {
+	U, S, V, err := TrySVD(t)
+	must(err)
+	return
+}
+
+// TrySVD does singular value decomposition like `SVD`, ErrDimensionMismatch if rows are less than columns
+func TrySVD(t *Matrix) (U, S, V *Matrix, err error) {
 	// Initialize
 	A := Copy(t).Data
 	m, n := t.Dims()
 	if m < n {
-		panic("error dimensions of input matrix, rows should be larger or equal to columns")
+		return nil, nil, nil, fmt.Errorf("SVD: %w (%d x %d, rows should be larger or equal to columns)", ErrDimensionMismatch, m, n)
 	}
 
 	nu := MinInt(m, n)
@@ -467,5 +475,5 @@ func SVD(t *Matrix) (U, S, V *Matrix) {
 	}
 	V = new(Matrix).Init(v)
 
-	return
+	return U, S, V, nil
 }
@@ -0,0 +1,533 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Ordering is the fill-reducing ordering applied before sparse factorization
+type Ordering int
+
+const (
+	NaturalOrdering Ordering = iota // keep original order
+	RCMOrdering                     // reverse Cuthill-McKee, reduces bandwidth and profile
+)
+
+// permutation returns the permutation of ordering on CSR matrix, perm[k] is the original index of new index k
+func (o Ordering) permutation(a *CSR) []int {
+	switch o {
+	case NaturalOrdering:
+		perm := make([]int, a.Rows)
+		for i := range perm {
+			perm[i] = i
+		}
+		return perm
+	case RCMOrdering:
+		return ReverseCuthillMcKee(a)
+	}
+	panic(fmt.Sprintf("unknown ordering %d", o))
+}
+
+// symmetricPattern returns adjacency lists of pattern of A + A' without self loops
+func symmetricPattern(a *CSR) [][]int {
+	n := a.Rows
+	at := a.T()
+	adj := make([][]int, n)
+	mark := make([]int, n)
+	for i := range mark {
+		mark[i] = -1
+	}
+	for i := 0; i < n; i++ {
+		mark[i] = i
+		for _, c := range [2]*CSR{a, at} {
+			cols, _ := c.RowNonZeros(i)
+			for _, j := range cols {
+				if mark[j] != i {
+					mark[j] = i
+					adj[i] = append(adj[i], j)
+				}
+			}
+		}
+	}
+	return adj
+}
+
+// bfsLevels does breadth first search from start inside nodes not done, returns visiting order and level of each visited node
+func bfsLevels(adj [][]int, start int, done []bool, dist []int) (order, levels []int) {
+	order = []int{start}
+	dist[start] = 0
+	for head := 0; head < len(order); head++ {
+		i := order[head]
+		for _, j := range adj[i] {
+			if !done[j] && dist[j] < 0 {
+				dist[j] = dist[i] + 1
+				order = append(order, j)
+			}
+		}
+	}
+	levels = make([]int, len(order))
+	for k, i := range order {
+		levels[k] = dist[i]
+		dist[i] = -1
+	}
+	return order, levels
+}
+
+// ReverseCuthillMcKee returns reverse Cuthill-McKee ordering of square CSR matrix on pattern of A + A'
+//	https://en.wikipedia.org/wiki/Cuthill%E2%80%93McKee_algorithm
+//	each connected component starts from a pseudo-peripheral node, perm[k] is the original index of new index k
+func ReverseCuthillMcKee(a *CSR) []int {
+	if a.Rows != a.Cols {
+		panic(squareError("ReverseCuthillMcKee", a.Rows, a.Cols))
+	}
+	n := a.Rows
+	adj := symmetricPattern(a)
+	done := make([]bool, n)
+	dist := make([]int, n)
+	for i := range dist {
+		dist[i] = -1
+	}
+	perm := make([]int, 0, n)
+	for len(perm) < n {
+		start := -1
+		for i := 0; i < n; i++ {
+			if !done[i] && (start < 0 || len(adj[i]) < len(adj[start])) {
+				start = i
+			}
+		}
+		// George-Liu pseudo-peripheral node: move to a minimal degree node of the last level while eccentricity grows
+		order, levels := bfsLevels(adj, start, done, dist)
+		for {
+			ecc := levels[len(levels)-1]
+			cand := order[len(order)-1]
+			for k := len(order) - 1; k >= 0 && levels[k] == ecc; k-- {
+				if len(adj[order[k]]) < len(adj[cand]) {
+					cand = order[k]
+				}
+			}
+			nOrder, nLevels := bfsLevels(adj, cand, done, dist)
+			if nLevels[len(nLevels)-1] <= ecc {
+				break
+			}
+			start, order, levels = cand, nOrder, nLevels
+		}
+		done[start] = true
+		head := len(perm)
+		perm = append(perm, start)
+		for ; head < len(perm); head++ {
+			i := perm[head]
+			k := len(perm)
+			for _, j := range adj[i] {
+				if !done[j] {
+					done[j] = true
+					perm = append(perm, j)
+				}
+			}
+			next := perm[k:]
+			sort.Slice(next, func(x, y int) bool {
+				if len(adj[next[x]]) != len(adj[next[y]]) {
+					return len(adj[next[x]]) < len(adj[next[y]])
+				}
+				return next[x] < next[y]
+			})
+		}
+	}
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
+
+// permutationSign returns sign (+1 or -1) of permutation by counting cycles
+func permutationSign(perm []int) float64 {
+	visited := make([]bool, len(perm))
+	sign := 1.
+	for i := range perm {
+		if visited[i] {
+			continue
+		}
+		l := 0
+		for j := i; !visited[j]; j = perm[j] {
+			visited[j] = true
+			l++
+		}
+		if l%2 == 0 {
+			sign = -sign
+		}
+	}
+	return sign
+}
+
+// SparseCholesky is the sparse Cholesky factorization P A P' = L L' of symmetric positive definite matrix
+//	L is stored in CSC with the diagonal element first in each column
+type SparseCholesky struct {
+	Perm []int // P, Perm[k] is the original index of row / column k
+	L    *CSC
+}
+
+// NewSparseCholesky factorizes symmetric positive definite CSR matrix, only its upper triangle is referenced
+//	https://www.cise.ufl.edu/research/sparse/CSparse/
+//	symbolic analysis builds elimination tree and column structures of L, numeric factorization is left-looking
+//	returns error wrapping ErrNotPositiveDefinite if a non-positive pivot is met
+func NewSparseCholesky(a *CSR, ordering Ordering) (*SparseCholesky, error) {
+	if a.Rows != a.Cols {
+		return nil, squareError("NewSparseCholesky", a.Rows, a.Cols)
+	}
+	n := a.Rows
+	perm := ordering.permutation(a)
+	pinv := make([]int, n)
+	for k, i := range perm {
+		pinv[i] = k
+	}
+	// upper triangle of C = P A P'
+	entries := make([]Entry, 0, a.NNZ()/2+n)
+	for i := 0; i < n; i++ {
+		cols, values := a.RowNonZeros(i)
+		for k, j := range cols {
+			if j >= i {
+				r, c := pinv[i], pinv[j]
+				if r > c {
+					r, c = c, r
+				}
+				entries = append(entries, Entry{Value: values[k], Row: r, Col: c})
+			}
+		}
+	}
+	U := NewCSR(n, n, entries)
+	Ut := U.T()
+	// elimination tree (Liu's algorithm with path compression)
+	parent, ancestor := make([]int, n), make([]int, n)
+	for k := 0; k < n; k++ {
+		parent[k], ancestor[k] = -1, -1
+		rows, _ := Ut.RowNonZeros(k)
+		for _, i := range rows {
+			for i != -1 && i < k {
+				next := ancestor[i]
+				ancestor[i] = k
+				if next == -1 {
+					parent[i] = k
+				}
+				i = next
+			}
+		}
+	}
+	// column structures: struct(L_j) = struct(A_j) + struct(L_c) \ {j} for children c of j
+	children := make([][]int, n)
+	for j, p := range parent {
+		if p >= 0 {
+			children[p] = append(children[p], j)
+		}
+	}
+	structs := make([][]int, n)
+	mark := make([]int, n)
+	for i := range mark {
+		mark[i] = -1
+	}
+	colPtr := make([]int, n+1)
+	for j := 0; j < n; j++ {
+		mark[j] = j
+		var s []int
+		cols, _ := U.RowNonZeros(j)
+		for _, i := range cols {
+			if mark[i] != j {
+				mark[i] = j
+				s = append(s, i)
+			}
+		}
+		for _, c := range children[j] {
+			for _, i := range structs[c] {
+				if mark[i] != j {
+					mark[i] = j
+					s = append(s, i)
+				}
+			}
+		}
+		sort.Ints(s)
+		structs[j] = s
+		colPtr[j+1] = colPtr[j] + 1 + len(s)
+	}
+	rowIdx := make([]int, colPtr[n])
+	for j := 0; j < n; j++ {
+		rowIdx[colPtr[j]] = j
+		copy(rowIdx[colPtr[j]+1:colPtr[j+1]], structs[j])
+	}
+	// numeric left-looking factorization, columns k with L(j, k) != 0 are linked in list of row j
+	val := make([]float64, colPtr[n])
+	x := make([]float64, n)
+	head, next, first := make([]int, n), make([]int, n), make([]int, n)
+	for i := range head {
+		head[i] = -1
+	}
+	link := func(k int) {
+		if first[k] < colPtr[k+1] {
+			r := rowIdx[first[k]]
+			next[k] = head[r]
+			head[r] = k
+		}
+	}
+	for j := 0; j < n; j++ {
+		cols, values := U.RowNonZeros(j)
+		for p, i := range cols {
+			x[i] = values[p]
+		}
+		for k := head[j]; k != -1; {
+			nk := next[k]
+			p := first[k]
+			ljk := val[p]
+			for q := p; q < colPtr[k+1]; q++ {
+				x[rowIdx[q]] -= val[q] * ljk
+			}
+			first[k] = p + 1
+			link(k)
+			k = nk
+		}
+		d := x[j]
+		x[j] = 0
+		if d <= 0 || math.IsNaN(d) {
+			return nil, fmt.Errorf("NewSparseCholesky: %w (pivot %d is %g)", ErrNotPositiveDefinite, j, d)
+		}
+		ljj := math.Sqrt(d)
+		val[colPtr[j]] = ljj
+		for q := colPtr[j] + 1; q < colPtr[j+1]; q++ {
+			val[q] = x[rowIdx[q]] / ljj
+			x[rowIdx[q]] = 0
+		}
+		first[j] = colPtr[j] + 1
+		link(j)
+	}
+	return &SparseCholesky{
+		Perm: perm,
+		L:    &CSC{Rows: n, Cols: n, ColPtr: colPtr, RowIdx: rowIdx, Values: val},
+	}, nil
+}
+
+// Solve solves A x = b with the factorization, it can be called repeatedly with different b
+func (f *SparseCholesky) Solve(b *Vector) *Vector {
+	L := f.L
+	n := L.Rows
+	if b.Length() != n {
+		panic(lenError("SparseCholesky.Solve", n, b.Length()))
+	}
+	y := make(Vector, n)
+	for k, i := range f.Perm {
+		y[k] = (*b)[i]
+	}
+	for j := 0; j < n; j++ {
+		d := L.ColPtr[j]
+		y[j] /= L.Values[d]
+		for q := d + 1; q < L.ColPtr[j+1]; q++ {
+			y[L.RowIdx[q]] -= L.Values[q] * y[j]
+		}
+	}
+	for j := n - 1; j >= 0; j-- {
+		d := L.ColPtr[j]
+		for q := d + 1; q < L.ColPtr[j+1]; q++ {
+			y[j] -= L.Values[q] * y[L.RowIdx[q]]
+		}
+		y[j] /= L.Values[d]
+	}
+	x := make(Vector, n)
+	for k, i := range f.Perm {
+		x[i] = y[k]
+	}
+	return &x
+}
+
+// LogDet returns log of determinant
+func (f *SparseCholesky) LogDet() float64 {
+	s := 0.
+	for j := 0; j < f.L.Cols; j++ {
+		s += math.Log(f.L.Values[f.L.ColPtr[j]])
+	}
+	return 2 * s
+}
+
+// Det returns determinant, it may overflow for large matrices, use `LogDet` instead
+func (f *SparseCholesky) Det() float64 {
+	return math.Exp(f.LogDet())
+}
+
+// SparseLU is the sparse LU factorization with partial pivoting P A Q = L U
+//	L is unit lower triangular stored in CSC with the diagonal element first in each column,
+//	U is upper triangular stored in CSC with the diagonal element last in each column
+type SparseLU struct {
+	RowPerm []int // P, RowPerm[k] is the original row pivoted at step k
+	ColPerm []int // Q, ColPerm[k] is the original column of column k
+	L, U    *CSC
+}
+
+// NewSparseLU factorizes square CSR matrix with left-looking Gilbert-Peierls algorithm
+//	https://www.cise.ufl.edu/research/sparse/CSparse/
+//	columns are ordered by the fill-reducing ordering on pattern of A + A', rows by partial pivoting
+//	returns error wrapping ErrSingular if no non-zero pivot is found in a column
+func NewSparseLU(a *CSR, ordering Ordering) (*SparseLU, error) {
+	if a.Rows != a.Cols {
+		return nil, squareError("NewSparseLU", a.Rows, a.Cols)
+	}
+	n := a.Rows
+	q := ordering.permutation(a)
+	A := a.ToCSC()
+	pinv := make([]int, n)
+	for i := range pinv {
+		pinv[i] = -1
+	}
+	lPtr, uPtr := make([]int, n+1), make([]int, n+1)
+	lIdx, uIdx := make([]int, 0, 4*A.NNZ()+n), make([]int, 0, 4*A.NNZ()+n)
+	lVal, uVal := make([]float64, 0, 4*A.NNZ()+n), make([]float64, 0, 4*A.NNZ()+n)
+	x := make([]float64, n)
+	marked := make([]bool, n)
+	reach := make([]int, 0, n) // topological order of nodes reachable in graph of L, reversed
+	stack := make([]int, 0, n) // dfs stack of nodes
+	childPos := make([]int, n) // next child position of node on stack
+	for k := 0; k < n; k++ {
+		col := q[k]
+		rows, values := A.ColNonZeros(col)
+		// symbolic: nodes reachable from pattern of A(:, col) in graph of L (cs_reach)
+		reach = reach[:0]
+		for _, r := range rows {
+			if marked[r] {
+				continue
+			}
+			stack = append(stack[:0], r)
+			marked[r] = true
+			if pinv[r] >= 0 {
+				childPos[r] = lPtr[pinv[r]]
+			}
+			for len(stack) > 0 {
+				j := stack[len(stack)-1]
+				J := pinv[j]
+				done := true
+				if J >= 0 {
+					for p := childPos[j]; p < lPtr[J+1]; p++ {
+						i := lIdx[p]
+						if marked[i] {
+							continue
+						}
+						childPos[j] = p + 1
+						marked[i] = true
+						if pinv[i] >= 0 {
+							childPos[i] = lPtr[pinv[i]]
+						}
+						stack = append(stack, i)
+						done = false
+						break
+					}
+				}
+				if done {
+					stack = stack[:len(stack)-1]
+					reach = append(reach, j)
+				}
+			}
+		}
+		// numeric: x = L \ A(:, col), in topological order
+		for p, r := range rows {
+			x[r] = values[p]
+		}
+		for t := len(reach) - 1; t >= 0; t-- {
+			j := reach[t]
+			J := pinv[j]
+			if J < 0 {
+				continue
+			}
+			xj := x[j] // unit diagonal
+			for p := lPtr[J] + 1; p < lPtr[J+1]; p++ {
+				x[lIdx[p]] -= lVal[p] * xj
+			}
+		}
+		// partial pivoting among non-pivotal rows, the diagonal is kept on ties
+		ipiv, maxAbs := -1, 0.
+		for _, i := range reach {
+			marked[i] = false
+			if pinv[i] < 0 {
+				if v := math.Abs(x[i]); v > maxAbs || (v == maxAbs && i == col && v > 0) {
+					ipiv, maxAbs = i, v
+				}
+			} else {
+				uIdx = append(uIdx, pinv[i])
+				uVal = append(uVal, x[i])
+			}
+		}
+		if ipiv < 0 || maxAbs == 0 || math.IsNaN(maxAbs) {
+			return nil, fmt.Errorf("NewSparseLU: %w (no pivot in column %d)", ErrSingular, col)
+		}
+		pivot := x[ipiv]
+		uIdx = append(uIdx, k)
+		uVal = append(uVal, pivot)
+		uPtr[k+1] = len(uIdx)
+		pinv[ipiv] = k
+		lIdx = append(lIdx, ipiv)
+		lVal = append(lVal, 1)
+		for _, i := range reach {
+			if pinv[i] < 0 {
+				lIdx = append(lIdx, i)
+				lVal = append(lVal, x[i]/pivot)
+			}
+			x[i] = 0
+		}
+		lPtr[k+1] = len(lIdx)
+	}
+	// row indexes of L to pivot order
+	for p, i := range lIdx {
+		lIdx[p] = pinv[i]
+	}
+	rowPerm := make([]int, n)
+	for i, k := range pinv {
+		rowPerm[k] = i
+	}
+	return &SparseLU{
+		RowPerm: rowPerm,
+		ColPerm: q,
+		L:       &CSC{Rows: n, Cols: n, ColPtr: lPtr, RowIdx: lIdx, Values: lVal},
+		U:       &CSC{Rows: n, Cols: n, ColPtr: uPtr, RowIdx: uIdx, Values: uVal},
+	}, nil
+}
+
+// Solve solves A x = b with the factorization, it can be called repeatedly with different b
+func (f *SparseLU) Solve(b *Vector) *Vector {
+	L, U := f.L, f.U
+	n := L.Rows
+	if b.Length() != n {
+		panic(lenError("SparseLU.Solve", n, b.Length()))
+	}
+	y := make(Vector, n)
+	for k, i := range f.RowPerm {
+		y[k] = (*b)[i]
+	}
+	for j := 0; j < n; j++ {
+		for p := L.ColPtr[j] + 1; p < L.ColPtr[j+1]; p++ {
+			y[L.RowIdx[p]] -= L.Values[p] * y[j]
+		}
+	}
+	for j := n - 1; j >= 0; j-- {
+		d := U.ColPtr[j+1] - 1
+		y[j] /= U.Values[d]
+		for p := U.ColPtr[j]; p < d; p++ {
+			y[U.RowIdx[p]] -= U.Values[p] * y[j]
+		}
+	}
+	x := make(Vector, n)
+	for k, j := range f.ColPerm {
+		x[j] = y[k]
+	}
+	return &x
+}
+
+// LogDet returns log of absolute value of determinant and its sign
+func (f *SparseLU) LogDet() (logAbsDet, sign float64) {
+	sign = permutationSign(f.RowPerm) * permutationSign(f.ColPerm)
+	for j := 0; j < f.U.Cols; j++ {
+		d := f.U.Values[f.U.ColPtr[j+1]-1]
+		if d < 0 {
+			sign = -sign
+		}
+		logAbsDet += math.Log(math.Abs(d))
+	}
+	return logAbsDet, sign
+}
+
+// Det returns determinant, it may overflow for large matrices, use `LogDet` instead
+func (f *SparseLU) Det() float64 {
+	logAbsDet, sign := f.LogDet()
+	return sign * math.Exp(logAbsDet)
+}
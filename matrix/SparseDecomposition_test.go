@@ -0,0 +1,178 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// randomSparseSPD returns a random sparse symmetric diagonally dominant matrix with positive diagonal, it is SPD
+func randomSparseSPD(n, entriesNum int) *SparseMatrix {
+	R := GenerateRandomSparseMatrix(n, n, entriesNum)
+	sm := R.Add(R.T())
+	for i := 0; i < n; i++ {
+		s := 1.
+		for _, v := range *sm.Row(i) {
+			s += math.Abs(v)
+		}
+		sm.Set(i, i, s)
+	}
+	return sm
+}
+
+// csrOf returns CSR matrix of dense data
+func csrOf(data Data) *CSR {
+	var entries []Entry
+	for i, row := range data {
+		for j, v := range row {
+			entries = append(entries, Entry{Value: v, Row: i, Col: j})
+		}
+	}
+	return NewCSR(len(data), len(data[0]), entries)
+}
+
+func TestReverseCuthillMcKee(t *testing.T) {
+	// a path graph 0-2-4-1-3 (shuffled labels) has bandwidth 1 after ordering
+	sm := ZeroSparseMatrix(5, 5)
+	path := []int{0, 2, 4, 1, 3}
+	for k, i := range path {
+		sm.Set(i, i, 2)
+		if k > 0 {
+			sm.Set(i, path[k-1], -1)
+			sm.Set(path[k-1], i, -1)
+		}
+	}
+	perm := ReverseCuthillMcKee(sm.ToCSR())
+	pinv := make([]int, 5)
+	seen := make([]bool, 5)
+	for k, i := range perm {
+		pinv[i] = k
+		seen[i] = true
+	}
+	for _, s := range seen {
+		if !s {
+			t.Fatal("not a permutation")
+		}
+	}
+	for idx := range sm.Data {
+		r, c := sm.IndexToRowCol(idx)
+		if AbsInt(pinv[r]-pinv[c]) > 1 {
+			t.Errorf("bandwidth > 1: %v", perm)
+		}
+	}
+	// disconnected components are all ordered
+	if len(ReverseCuthillMcKee(csrOf(IdentityMatrix(4).Data))) != 4 {
+		t.Fail()
+	}
+}
+
+func TestSparseCholesky(t *testing.T) {
+	for _, ordering := range []Ordering{NaturalOrdering, RCMOrdering} {
+		sm := randomSparseSPD(60, 120)
+		A := sm.ToCSR()
+		f, err := NewSparseCholesky(A, ordering)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// repeated solves
+		for k := 0; k < 3; k++ {
+			x := GenerateRandomVector(60)
+			if !VEqual(f.Solve(A.MulVec(x)), x) {
+				t.Fail()
+			}
+		}
+		dense := sm.ToMatrix()
+		if !FloatEqual(f.LogDet(), math.Log(dense.Det())) {
+			t.Errorf("log det %v vs %v", f.LogDet(), math.Log(dense.Det()))
+		}
+		// P A P' = L L'
+		L := f.L.ToMatrix()
+		P := ZeroMatrix(60, 60)
+		for k, i := range f.Perm {
+			P.Set(k, i, 1)
+		}
+		if !MEqual(L.Mul(L.T()), P.Mul(dense).Mul(P.T())) {
+			t.Fail()
+		}
+	}
+	lap := laplacian2D(10).ToCSR()
+	f, err := NewSparseCholesky(lap, RCMOrdering)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := GenerateRandomVector(100)
+	if !VEqual(lap.MulVec(f.Solve(b)), b) {
+		t.Fail()
+	}
+	if !FloatEqual(f.Det(), lap.ToMatrix().Det()) {
+		t.Fail()
+	}
+}
+
+func TestSparseCholeskyErrors(t *testing.T) {
+	if _, err := NewSparseCholesky(ZeroSparseMatrix(2, 3).ToCSR(), NaturalOrdering); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	notPD := csrOf(Data{{1, 2}, {2, 1}})
+	if _, err := NewSparseCholesky(notPD, RCMOrdering); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+}
+
+func TestSparseLU(t *testing.T) {
+	for _, ordering := range []Ordering{NaturalOrdering, RCMOrdering} {
+		sm := convectionDiffusion2D(8, 3)
+		// a few off-pattern entries and a zero diagonal make pivoting necessary
+		sm.Set(0, 0, 0)
+		sm.Set(5, 40, 7)
+		sm.Set(63, 2, -3)
+		A := sm.ToCSR()
+		f, err := NewSparseLU(A, ordering)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k := 0; k < 3; k++ {
+			x := GenerateRandomVector(64)
+			if !VEqual(f.Solve(A.MulVec(x)), x) {
+				t.Fail()
+			}
+		}
+		dense := sm.ToMatrix()
+		det := dense.Det()
+		if !FloatEqual(f.Det(), det) {
+			t.Errorf("det %v vs %v", f.Det(), det)
+		}
+		logAbs, sign := f.LogDet()
+		if !FloatEqual(logAbs, math.Log(math.Abs(det))) || sign != math.Copysign(1, det) {
+			t.Fail()
+		}
+		// P A Q = L U
+		P, Q := ZeroMatrix(64, 64), ZeroMatrix(64, 64)
+		for k := 0; k < 64; k++ {
+			P.Set(k, f.RowPerm[k], 1)
+			Q.Set(f.ColPerm[k], k, 1)
+		}
+		if !MEqual(f.L.ToMatrix().Mul(f.U.ToMatrix()), P.Mul(dense).Mul(Q)) {
+			t.Fail()
+		}
+	}
+	// pivoting is required
+	A := csrOf(Data{{0, 1, 0}, {1, 0, 0}, {0, 0, 2}})
+	f, err := NewSparseLU(A, NaturalOrdering)
+	if err != nil || !FloatEqual(f.Det(), -2) || !VEqual(f.Solve(&Vector{1, 2, 4}), &Vector{2, 1, 2}) {
+		t.Fail()
+	}
+	if _, err := NewSparseLU(csrOf(Data{{1, 2}, {2, 4}}), RCMOrdering); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+}
+
+func TestSparseMatrix_DetSparse(t *testing.T) {
+	sm := convectionDiffusion2D(6, 1)
+	if !FloatEqual(sm.Det(), sm.ToMatrix().Det()) {
+		t.Fail()
+	}
+	if ZeroSparseMatrix(3, 3).Det() != 0 {
+		t.Fail()
+	}
+}
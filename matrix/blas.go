@@ -0,0 +1,319 @@
+package matrix
+
+import "math"
+
+// BLAS-style kernels, they work on existing storage of their destination argument and never allocate
+//	http://www.netlib.org/blas/
+//	beta == 0 means the destination is overwritten, so NaN or Inf inside it does not propagate (as in reference BLAS)
+//	notice: triangular solvers do not check singularity, zero diagonal element gives Inf or NaN,
+//	use `TriangularMatrix.TrySolve` for checked version
+
+// Axpy computes y = alpha * x + y
+func Axpy(alpha float64, x, y *Vector) {
+	if len(*x) != len(*y) {
+		panic(lenError("Axpy", len(*x), len(*y)))
+	}
+	if alpha == 0 {
+		return
+	}
+	yy := (*y)[:len(*x)]
+	for i, v := range *x {
+		yy[i] += alpha * v
+	}
+}
+
+// Scal computes x = alpha * x
+func Scal(alpha float64, x *Vector) {
+	for i := range *x {
+		(*x)[i] *= alpha
+	}
+}
+
+// Dot returns x · y
+func Dot(x, y *Vector) float64 {
+	if len(*x) != len(*y) {
+		panic(lenError("Dot", len(*x), len(*y)))
+	}
+	return dot(*x, *y)
+}
+
+// dot returns x · y of two slices of the same length
+func dot(x, y []float64) float64 {
+	sum := 0.
+	y = y[:len(x)]
+	for i, v := range x {
+		sum += v * y[i]
+	}
+	return sum
+}
+
+// Nrm2 returns 2-norm of x, scaled so that it does not overflow or underflow for extreme elements
+func Nrm2(x *Vector) float64 {
+	scale, ssq := 0., 1.
+	for _, v := range *x {
+		if v == 0 {
+			continue
+		}
+		a := math.Abs(v)
+		if scale < a {
+			ssq = 1 + ssq*(scale/a)*(scale/a)
+			scale = a
+		} else {
+			ssq += (a / scale) * (a / scale)
+		}
+	}
+	return scale * math.Sqrt(ssq)
+}
+
+// scaleBy computes x = beta * x, x is zeroed for beta == 0
+func scaleBy(beta float64, x []float64) {
+	switch beta {
+	case 1:
+	case 0:
+		for i := range x {
+			x[i] = 0
+		}
+	default:
+		for i := range x {
+			x[i] *= beta
+		}
+	}
+}
+
+// Gemv computes y = alpha * A * x + beta * y, or y = alpha * A.T() * x + beta * y if trans
+func Gemv(trans bool, alpha float64, A *Matrix, x *Vector, beta float64, y *Vector) {
+	m, n := A.Dims()
+	if trans {
+		m, n = n, m
+	}
+	if n != len(*x) || m != len(*y) {
+		panic(dimsError("Gemv", m, n, len(*x), len(*y)))
+	}
+	if !trans {
+		for i, row := range A.Data {
+			if beta == 0 {
+				(*y)[i] = alpha * dot(row, *x)
+			} else {
+				(*y)[i] = alpha*dot(row, *x) + beta*(*y)[i]
+			}
+		}
+		return
+	}
+	scaleBy(beta, *y)
+	if alpha == 0 {
+		return
+	}
+	for k, row := range A.Data {
+		if a := alpha * (*x)[k]; a != 0 {
+			yy := (*y)[:len(row)]
+			for i, v := range row {
+				yy[i] += a * v
+			}
+		}
+	}
+}
+
+// Ger computes A = alpha * x * y.T() + A
+func Ger(alpha float64, x, y *Vector, A *Matrix) {
+	m, n := A.Dims()
+	if m != len(*x) || n != len(*y) {
+		panic(dimsError("Ger", m, n, len(*x), len(*y)))
+	}
+	for i, row := range A.Data {
+		if a := alpha * (*x)[i]; a != 0 {
+			row = row[:len(*y)]
+			for j, v := range *y {
+				row[j] += a * v
+			}
+		}
+	}
+}
+
+// Trsv solves A * z = x, or A.T() * z = x if trans, and overwrites x by z
+func Trsv(trans bool, A *TriangularMatrix, x *Vector) {
+	n := A.N
+	if n != len(*x) {
+		panic(dimsError("Trsv", n, n, len(*x), 1))
+	}
+	xx := *x
+	if !trans {
+		// row oriented, forward substitution for lower and back substitution for upper
+		for s := 0; s < n; s++ {
+			i := s
+			if A.Upper {
+				i = n - 1 - s
+			}
+			lo, _ := A.span(i)
+			sum := xx[i]
+			for k, a := range A.row(i) {
+				if j := lo + k; j != i {
+					sum -= a * xx[j]
+				}
+			}
+			xx[i] = sum / A.diag(i)
+		}
+		return
+	}
+	// column oriented, A.T() is upper for lower A and vice versa
+	for s := 0; s < n; s++ {
+		i := n - 1 - s
+		if A.Upper {
+			i = s
+		}
+		xx[i] /= A.diag(i)
+		lo, _ := A.span(i)
+		for k, a := range A.row(i) {
+			if j := lo + k; j != i {
+				xx[j] -= a * xx[i]
+			}
+		}
+	}
+}
+
+// Gemm computes C = alpha * op(A) * op(B) + beta * C, op(X) is X.T() if corresponding trans flag is set
+func Gemm(transA, transB bool, alpha float64, A, B *Matrix, beta float64, C *Matrix) {
+	m, k := A.Dims()
+	if transA {
+		m, k = k, m
+	}
+	k2, n := B.Dims()
+	if transB {
+		k2, n = n, k2
+	}
+	if k != k2 {
+		panic(dimsError("Gemm", m, k, k2, n))
+	}
+	if r, c := C.Dims(); r != m || c != n {
+		panic(dimsError("Gemm", m, n, r, c))
+	}
+	for i := range C.Data {
+		scaleBy(beta, C.Data[i])
+	}
+	if alpha == 0 {
+		return
+	}
+	switch {
+	case !transA && !transB:
+		for i, ci := range C.Data {
+			for l, a := range A.Data[i] {
+				if a *= alpha; a != 0 {
+					for j, b := range B.Data[l][:len(ci)] {
+						ci[j] += a * b
+					}
+				}
+			}
+		}
+	case transA && !transB:
+		for l, al := range A.Data {
+			bl := B.Data[l]
+			for i, a := range al {
+				if a *= alpha; a != 0 {
+					ci := C.Data[i][:len(bl)]
+					for j, b := range bl {
+						ci[j] += a * b
+					}
+				}
+			}
+		}
+	case !transA && transB:
+		for i, ci := range C.Data {
+			for j := range ci {
+				ci[j] += alpha * dot(A.Data[i], B.Data[j])
+			}
+		}
+	default:
+		for i, ci := range C.Data {
+			for j := range ci {
+				bj := B.Data[j]
+				sum := 0.
+				for l, al := range A.Data {
+					sum += al[i] * bj[l]
+				}
+				ci[j] += alpha * sum
+			}
+		}
+	}
+}
+
+// Syrk computes symmetric rank-k update C = alpha * A * A.T() + beta * C, or C = alpha * A.T() * A + beta * C if trans
+//	only the packed lower triangle of C is touched, so it takes about half of the flops of `Gemm`
+func Syrk(trans bool, alpha float64, A *Matrix, beta float64, C *SymmetricMatrix) {
+	m, n := A.Dims()
+	if trans {
+		m, n = n, m
+	}
+	if C.N != m {
+		panic(dimsError("Syrk", m, n, C.N, C.N))
+	}
+	scaleBy(beta, C.Data)
+	if alpha == 0 {
+		return
+	}
+	if !trans {
+		for i := 0; i < m; i++ {
+			ci := C.Data[i*(i+1)/2 : i*(i+1)/2+i+1]
+			for j := range ci {
+				ci[j] += alpha * dot(A.Data[i], A.Data[j])
+			}
+		}
+		return
+	}
+	for _, row := range A.Data {
+		for i, v := range row {
+			if a := alpha * v; a != 0 {
+				ci := C.Data[i*(i+1)/2 : i*(i+1)/2+i+1]
+				for j, w := range row[:i+1] {
+					ci[j] += a * w
+				}
+			}
+		}
+	}
+}
+
+// Trsm solves A * X = alpha * B, or A.T() * X = alpha * B if trans, and overwrites B by X
+func Trsm(trans bool, alpha float64, A *TriangularMatrix, B *Matrix) {
+	n := A.N
+	if r, c := B.Dims(); r != n {
+		panic(dimsError("Trsm", n, n, r, c))
+	}
+	if alpha != 1 {
+		for i := range B.Data {
+			scaleBy(alpha, B.Data[i])
+		}
+	}
+	X := B.Data
+	for s := 0; s < n; s++ {
+		i := s
+		if A.Upper != trans {
+			i = n - 1 - s
+		}
+		xi := X[i]
+		lo, _ := A.span(i)
+		if !trans {
+			// row i of X depends on already solved rows
+			for k, a := range A.row(i) {
+				if j := lo + k; j != i && a != 0 {
+					for c, x := range X[j][:len(xi)] {
+						xi[c] -= a * x
+					}
+				}
+			}
+		}
+		if d := A.diag(i); d != 1 {
+			for c := range xi {
+				xi[c] /= d
+			}
+		}
+		if trans {
+			// solved row i is eliminated from the rows depending on it
+			for k, a := range A.row(i) {
+				if j := lo + k; j != i && a != 0 {
+					xj := X[j][:len(xi)]
+					for c, x := range xi {
+						xj[c] -= a * x
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevel1(t *testing.T) {
+	x, y := GenerateRandomVector(7), GenerateRandomVector(7)
+	want := y.Add(x.MulNum(-2))
+	Axpy(-2, x, y)
+	if !VEqual(y, want) || !FloatEqual(Dot(x, y), x.Dot(y)) || !FloatEqual(Nrm2(x), x.Norm()) {
+		t.Fail()
+	}
+	want = x.MulNum(3)
+	if Scal(3, x); !VEqual(x, want) {
+		t.Fail()
+	}
+	// no overflow for huge elements
+	if big := (Vector{3e200, 4e200}); !FloatEqual(Nrm2(&big), 5e200) || Nrm2(&Vector{}) != 0 {
+		t.Fail()
+	}
+}
+
+func TestLevel2(t *testing.T) {
+	a := GenerateRandomMatrix(4, 3)
+	x, y := GenerateRandomVector(3), GenerateRandomVector(4)
+	want := a.MulVec(x).MulNum(2).Add(y.MulNum(0.5))
+	if Gemv(false, 2, a, x, 0.5, y); !VEqual(y, want) {
+		t.Fail()
+	}
+	want = a.T().MulVec(y)
+	z := Vector{math.NaN(), 0, 0}
+	// beta == 0 overwrites NaN
+	if Gemv(true, 1, a, y, 0, &z); !VEqual(&z, want) {
+		t.Fail()
+	}
+	b := Copy(a)
+	Ger(-1, y, x, b)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			if !FloatEqual(b.At(i, j), a.At(i, j)-y.At(i)*x.At(j)) {
+				t.Fail()
+			}
+		}
+	}
+
+	m := GenerateRandomSquareMatrix(5).Add(IdentityMatrix(5).MulNum(3))
+	for _, upper := range []bool{false, true} {
+		for _, unit := range []bool{false, true} {
+			tr := NewTriangularMatrix(m, upper, unit)
+			for _, trans := range []bool{false, true} {
+				v := GenerateRandomVector(5)
+				s := append(Vector{}, *v...)
+				Trsv(trans, tr, &s)
+				op := tr.ToMatrix()
+				if trans {
+					op = op.T()
+				}
+				if !VEqual(op.MulVec(&s), v) {
+					t.Fatal(upper, unit, trans)
+				}
+			}
+		}
+	}
+}
+
+func TestLevel3(t *testing.T) {
+	a, b := GenerateRandomMatrix(4, 3), GenerateRandomMatrix(3, 5)
+	c := GenerateRandomMatrix(4, 5)
+	for _, transA := range []bool{false, true} {
+		for _, transB := range []bool{false, true} {
+			opA, opB := a, b
+			if transA {
+				opA = a.T()
+			}
+			if transB {
+				opB = b.T()
+			}
+			dst := Copy(c)
+			Gemm(transA, transB, 1.5, opA, opB, -1, dst)
+			if !MEqual(dst, a.Mul(b).MulNum(1.5).Sub(c)) {
+				t.Fatal(transA, transB)
+			}
+		}
+	}
+
+	s := GenerateRandomMatrix(6, 4).CovSymmetricMatrix()
+	want := a.Mul(a.T()).MulNum(2).Add(s.ToMatrix().GetSubMatrix(0, 0, 4, 4).MulNum(3))
+	cs := NewSymmetricMatrix(s.ToMatrix().GetSubMatrix(0, 0, 4, 4))
+	if Syrk(false, 2, a, 3, cs); !MEqual(cs.ToMatrix(), want) {
+		t.Fail()
+	}
+	ct := ZeroSymmetricMatrix(3)
+	if Syrk(true, 1, a, 0, ct); !MEqual(ct.ToMatrix(), a.T().Mul(a)) {
+		t.Fail()
+	}
+
+	m := GenerateRandomSquareMatrix(4).Add(IdentityMatrix(4).MulNum(3))
+	for _, upper := range []bool{false, true} {
+		for _, trans := range []bool{false, true} {
+			tr := NewTriangularMatrix(m, upper, false)
+			x := Copy(a)
+			Trsm(trans, 2, tr, x)
+			op := tr.ToMatrix()
+			if trans {
+				op = op.T()
+			}
+			if !MEqual(op.Mul(x), a.MulNum(2)) {
+				t.Fatal(upper, trans)
+			}
+		}
+	}
+}
+
+func TestBLASAllocs(t *testing.T) {
+	a, c := GenerateRandomMatrix(20, 20), ZeroMatrix(20, 20)
+	x, y := GenerateRandomVector(20), GenerateRandomVector(20)
+	tr := NewTriangularMatrix(a.Add(IdentityMatrix(20).MulNum(20)), false, false)
+	s := ZeroSymmetricMatrix(20)
+	allocs := testing.AllocsPerRun(10, func() {
+		Axpy(0.5, x, y)
+		Scal(0.5, y)
+		Dot(x, y)
+		Nrm2(x)
+		Gemv(false, 1, a, x, 0, y)
+		Ger(1e-3, x, y, c)
+		Trsv(true, tr, y)
+		Gemm(false, true, 1, a, a, 0.5, c)
+		Syrk(true, 1, a, 0, s)
+		Trsm(false, 1, tr, c)
+	})
+	if allocs != 0 {
+		t.Fatal(allocs)
+	}
+}
+
+func BenchmarkAxpy(b *testing.B) {
+	x, y := GenerateRandomVector(1000), GenerateRandomVector(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Axpy(1e-3, x, y)
+	}
+}
+
+func BenchmarkGemv(b *testing.B) {
+	a := GenerateRandomMatrix(200, 200)
+	x, y := GenerateRandomVector(200), GenerateRandomVector(200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Gemv(false, 1, a, x, 0, y)
+	}
+}
+
+func BenchmarkGemm(b *testing.B) {
+	a, c := GenerateRandomMatrix(100, 100), ZeroMatrix(100, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Gemm(false, false, 1, a, a, 0, c)
+	}
+}
+
+func BenchmarkSyrk(b *testing.B) {
+	a, c := GenerateRandomMatrix(500, 50), ZeroSymmetricMatrix(50)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Syrk(true, 1, a, 0, c)
+	}
+}
+
+func BenchmarkGer(b *testing.B) {
+	a := GenerateRandomMatrix(200, 200)
+	x, y := GenerateRandomVector(200), GenerateRandomVector(200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Ger(1e-6, x, y, a)
+	}
+}
+
+func BenchmarkTrsv(b *testing.B) {
+	tr := NewTriangularMatrix(GenerateRandomSquareMatrix(200).Add(IdentityMatrix(200).MulNum(200)), false, false)
+	rhs, x := GenerateRandomVector(200), make(Vector, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(x, *rhs)
+		Trsv(false, tr, &x)
+	}
+}
+
+func BenchmarkTrsm(b *testing.B) {
+	tr := NewTriangularMatrix(GenerateRandomSquareMatrix(100).Add(IdentityMatrix(100).MulNum(100)), true, false)
+	rhs, c := GenerateRandomMatrix(100, 20), ZeroMatrix(100, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for k := range c.Data {
+			copy(c.Data[k], rhs.Data[k])
+		}
+		Trsm(false, 1, tr, c)
+	}
+}
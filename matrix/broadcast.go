@@ -0,0 +1,435 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// element-wise operations with NumPy style broadcasting
+//	https://numpy.org/doc/stable/user/basics.broadcasting.html
+//	operands are *Matrix (or any `Interface`), *Vector (row vector, 1 x n), *ColumnVector (n x 1) and numbers (1 x 1),
+//	two shapes are compatible if every dimension is equal or one of them is 1, the dimension of 1 is stretched
+//	e.g. t.SubElem(t.Mean(0)) centers columns of t without `Vector.Tile`
+
+// ColumnVector is vector used as column (n x 1) operand of element-wise operations, vectors are rows by default
+type ColumnVector Vector
+
+// AsColumn returns v as column operand, no copy
+func AsColumn(v *Vector) *ColumnVector {
+	return (*ColumnVector)(v)
+}
+
+// operand is broadcasting view of an element-wise operation argument
+type operand struct {
+	rows, cols int
+	row        func(i int) []float64
+}
+
+// toOperand wraps supported argument types, scalars are 1 x 1
+func toOperand(op string, x interface{}) (operand, error) {
+	switch v := x.(type) {
+	case *Matrix:
+		r, c := v.Dims()
+		return operand{r, c, func(i int) []float64 { return v.Data[i] }}, nil
+	case *Vector:
+		return operand{1, len(*v), func(int) []float64 { return *v }}, nil
+	case *ColumnVector:
+		return operand{len(*v), 1, func(i int) []float64 { return (*v)[i : i+1] }}, nil
+	case Interface:
+		r, c := v.Dims()
+		return operand{r, c, func(i int) []float64 { return *v.Row(i) }}, nil
+	case uint8, int8, uint16, int16, uint32, int32, uint64, int64, int, float32, float64:
+		s := []float64{GetFloat64(v)}
+		return operand{1, 1, func(int) []float64 { return s }}, nil
+	}
+	return operand{}, fmt.Errorf("%s: unsupported operand type %T", op, x)
+}
+
+// broadcastDim returns common size of one dimension, -1 if incompatible
+func broadcastDim(n1, n2 int) int {
+	switch {
+	case n1 == n2 || n2 == 1:
+		return n1
+	case n1 == 1:
+		return n2
+	}
+	return -1
+}
+
+// broadcastOperands wraps a and b and returns their common shape, ErrDimensionMismatch if incompatible
+func broadcastOperands(op string, a, b interface{}) (oa, ob operand, rows, cols int, err error) {
+	if oa, err = toOperand(op, a); err != nil {
+		return
+	}
+	if ob, err = toOperand(op, b); err != nil {
+		return
+	}
+	rows, cols = broadcastDim(oa.rows, ob.rows), broadcastDim(oa.cols, ob.cols)
+	if rows < 0 || cols < 0 {
+		err = dimsError(op, oa.rows, oa.cols, ob.rows, ob.cols)
+	}
+	return
+}
+
+// step returns 0 for stretched dimension of size 1 and 1 otherwise
+func step(n int) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// forEach calls f(i, j, x, y) for every element (i, j) of the common shape, stretched rows and columns are re-read
+func forEach(rows, cols int, oa, ob operand, f func(i, j int, x, y float64)) {
+	ra, rb := step(oa.rows), step(ob.rows)
+	ca, cb := step(oa.cols), step(ob.cols)
+	for i := 0; i < rows; i++ {
+		xa, xb := oa.row(i*ra), ob.row(i*rb)
+		for j := 0; j < cols; j++ {
+			f(i, j, xa[j*ca], xb[j*cb])
+		}
+	}
+}
+
+// TryApply returns f(a, b) element-wise on broadcast operands, ErrDimensionMismatch for incompatible shapes
+func TryApply(a, b interface{}, f func(x, y float64) float64) (*Matrix, error) {
+	return tryApply("Apply", a, b, f)
+}
+
+// Apply returns f(a, b) element-wise like `TryApply`, panics on error
+//	e.g. Apply(t, AsColumn(w), math.Max) clips every row i of t from below by w[i]
+func Apply(a, b interface{}, f func(x, y float64) float64) *Matrix {
+	nt, err := tryApply("Apply", a, b, f)
+	must(err)
+	return nt
+}
+
+// tryApply is `TryApply` with operation name for errors
+func tryApply(op string, a, b interface{}, f func(x, y float64) float64) (*Matrix, error) {
+	oa, ob, rows, cols, err := broadcastOperands(op, a, b)
+	if err != nil {
+		return nil, err
+	}
+	nt := ZeroMatrix(rows, cols)
+	ra, rb, ca, cb := step(oa.rows), step(ob.rows), step(oa.cols), step(ob.cols)
+	for i, ni := range nt.Data {
+		xa, xb := oa.row(i*ra), ob.row(i*rb)
+		for j := range ni {
+			ni[j] = f(xa[j*ca], xb[j*cb])
+		}
+	}
+	return nt, nil
+}
+
+// AddElem returns t + b element-wise with broadcasting
+func (t *Matrix) AddElem(b interface{}) *Matrix {
+	nt, err := tryApply("AddElem", t, b, func(x, y float64) float64 { return x + y })
+	must(err)
+	return nt
+}
+
+// SubElem returns t - b element-wise with broadcasting
+func (t *Matrix) SubElem(b interface{}) *Matrix {
+	nt, err := tryApply("SubElem", t, b, func(x, y float64) float64 { return x - y })
+	must(err)
+	return nt
+}
+
+// MulElem returns Hadamard product t ⊙ b (element-wise) with broadcasting
+func (t *Matrix) MulElem(b interface{}) *Matrix {
+	nt, err := t.TryMulElem(b)
+	must(err)
+	return nt
+}
+
+// TryMulElem returns t ⊙ b like `MulElem`, ErrDimensionMismatch for incompatible shapes
+func (t *Matrix) TryMulElem(b interface{}) (*Matrix, error) {
+	return tryApply("MulElem", t, b, func(x, y float64) float64 { return x * y })
+}
+
+// DivElem returns t / b element-wise with broadcasting, division by zero gives Inf or NaN
+func (t *Matrix) DivElem(b interface{}) *Matrix {
+	nt, err := t.TryDivElem(b)
+	must(err)
+	return nt
+}
+
+// TryDivElem returns t / b like `DivElem`, ErrDimensionMismatch for incompatible shapes
+func (t *Matrix) TryDivElem(b interface{}) (*Matrix, error) {
+	return tryApply("DivElem", t, b, func(x, y float64) float64 { return x / y })
+}
+
+// PowElem returns t ** b element-wise with broadcasting (`math.Pow`)
+func (t *Matrix) PowElem(b interface{}) *Matrix {
+	nt, err := tryApply("PowElem", t, b, math.Pow)
+	must(err)
+	return nt
+}
+
+// Mask is boolean matrix of element-wise comparison results, element (i, j) locates at Data[i*Cols+j]
+type Mask struct {
+	Rows, Cols int
+	Data       []bool
+}
+
+// TryCompare returns cmp(a, b) element-wise on broadcast operands, ErrDimensionMismatch for incompatible shapes
+func TryCompare(a, b interface{}, cmp func(x, y float64) bool) (*Mask, error) {
+	return tryCompare("Compare", a, b, cmp)
+}
+
+// Compare returns cmp(a, b) element-wise like `TryCompare`, panics on error
+func Compare(a, b interface{}, cmp func(x, y float64) bool) *Mask {
+	m, err := tryCompare("Compare", a, b, cmp)
+	must(err)
+	return m
+}
+
+// tryCompare is `TryCompare` with operation name for errors
+func tryCompare(op string, a, b interface{}, cmp func(x, y float64) bool) (*Mask, error) {
+	oa, ob, rows, cols, err := broadcastOperands(op, a, b)
+	if err != nil {
+		return nil, err
+	}
+	m := &Mask{Rows: rows, Cols: cols, Data: make([]bool, rows*cols)}
+	forEach(rows, cols, oa, ob, func(i, j int, x, y float64) {
+		m.Data[i*cols+j] = cmp(x, y)
+	})
+	return m, nil
+}
+
+// compare is `Compare` with operation name for errors
+func compare(op string, a, b interface{}, cmp func(x, y float64) bool) *Mask {
+	m, err := tryCompare(op, a, b, cmp)
+	must(err)
+	return m
+}
+
+// Greater returns mask of t > b with broadcasting
+func (t *Matrix) Greater(b interface{}) *Mask {
+	return compare("Greater", t, b, func(x, y float64) bool { return x > y })
+}
+
+// GreaterEqual returns mask of t >= b with broadcasting
+func (t *Matrix) GreaterEqual(b interface{}) *Mask {
+	return compare("GreaterEqual", t, b, func(x, y float64) bool { return x >= y })
+}
+
+// Less returns mask of t < b with broadcasting
+func (t *Matrix) Less(b interface{}) *Mask {
+	return compare("Less", t, b, func(x, y float64) bool { return x < y })
+}
+
+// LessEqual returns mask of t <= b with broadcasting
+func (t *Matrix) LessEqual(b interface{}) *Mask {
+	return compare("LessEqual", t, b, func(x, y float64) bool { return x <= y })
+}
+
+// Equal returns mask of t == b (exact comparison) with broadcasting, use `Compare` with `FloatEqual` for tolerance
+func (t *Matrix) Equal(b interface{}) *Mask {
+	return compare("Equal", t, b, func(x, y float64) bool { return x == y })
+}
+
+// NotEqual returns mask of t != b (exact comparison) with broadcasting
+func (t *Matrix) NotEqual(b interface{}) *Mask {
+	return compare("NotEqual", t, b, func(x, y float64) bool { return x != y })
+}
+
+// At returns mask value at row i, column j
+func (m *Mask) At(i, j int) bool {
+	if i < 0 || j < 0 || i >= m.Rows || j >= m.Cols {
+		panic("index out of range")
+	}
+	return m.Data[i*m.Cols+j]
+}
+
+// combine returns f of two masks of the same shape element-wise
+func (m *Mask) combine(op string, m2 *Mask, f func(x, y bool) bool) *Mask {
+	if m.Rows != m2.Rows || m.Cols != m2.Cols {
+		panic(dimsError(op, m.Rows, m.Cols, m2.Rows, m2.Cols))
+	}
+	nm := &Mask{Rows: m.Rows, Cols: m.Cols, Data: make([]bool, len(m.Data))}
+	for i, x := range m.Data {
+		nm.Data[i] = f(x, m2.Data[i])
+	}
+	return nm
+}
+
+// And returns m && m2 element-wise
+func (m *Mask) And(m2 *Mask) *Mask {
+	return m.combine("Mask.And", m2, func(x, y bool) bool { return x && y })
+}
+
+// Or returns m || m2 element-wise
+func (m *Mask) Or(m2 *Mask) *Mask {
+	return m.combine("Mask.Or", m2, func(x, y bool) bool { return x || y })
+}
+
+// Not returns !m element-wise
+func (m *Mask) Not() *Mask {
+	return m.combine("Mask.Not", m, func(x, _ bool) bool { return !x })
+}
+
+// Count returns number of true elements
+func (m *Mask) Count() int {
+	n := 0
+	for _, x := range m.Data {
+		if x {
+			n++
+		}
+	}
+	return n
+}
+
+// AllRows returns for every row whether all its elements are true, e.g. to select rows by `SelectRowsMask`
+func (m *Mask) AllRows() []bool {
+	rows := make([]bool, m.Rows)
+	for i := range rows {
+		rows[i] = true
+		for _, x := range m.Data[i*m.Cols : (i+1)*m.Cols] {
+			rows[i] = rows[i] && x
+		}
+	}
+	return rows
+}
+
+// AnyRows returns for every row whether any of its elements is true
+func (m *Mask) AnyRows() []bool {
+	rows := make([]bool, m.Rows)
+	for i := range rows {
+		for _, x := range m.Data[i*m.Cols : (i+1)*m.Cols] {
+			rows[i] = rows[i] || x
+		}
+	}
+	return rows
+}
+
+// checkMask panics if mask shape is not the shape of t
+func (t *Matrix) checkMask(op string, m *Mask) {
+	row, col := t.Dims()
+	if m.Rows != row || m.Cols != col {
+		panic(dimsError(op, row, col, m.Rows, m.Cols))
+	}
+}
+
+// Masked returns elements where mask is true in row-major order, like t[mask] of NumPy
+func (t *Matrix) Masked(m *Mask) *Vector {
+	t.checkMask("Masked", m)
+	v := make(Vector, 0, m.Count())
+	for i := range t.Data {
+		for j, x := range t.Data[i] {
+			if m.Data[i*m.Cols+j] {
+				v = append(v, x)
+			}
+		}
+	}
+	return &v
+}
+
+// SetMasked sets value to elements where mask is true in place, like t[mask] = value of NumPy
+func (t *Matrix) SetMasked(m *Mask, value float64) {
+	t.checkMask("SetMasked", m)
+	for i := range t.Data {
+		for j := range t.Data[i] {
+			if m.Data[i*m.Cols+j] {
+				t.Data[i][j] = value
+			}
+		}
+	}
+}
+
+// Where returns matrix taking elements of a where mask is true and of b elsewhere, a and b are broadcast to mask
+func Where(m *Mask, a, b interface{}) *Matrix {
+	oa, err := toOperand("Where", a)
+	must(err)
+	ob, err := toOperand("Where", b)
+	must(err)
+	for _, o := range []operand{oa, ob} {
+		if broadcastDim(m.Rows, o.rows) != m.Rows || broadcastDim(m.Cols, o.cols) != m.Cols {
+			panic(dimsError("Where", m.Rows, m.Cols, o.rows, o.cols))
+		}
+	}
+	nt := ZeroMatrix(m.Rows, m.Cols)
+	forEach(m.Rows, m.Cols, oa, ob, func(i, j int, x, y float64) {
+		if m.Data[i*m.Cols+j] {
+			nt.Data[i][j] = x
+		} else {
+			nt.Data[i][j] = y
+		}
+	})
+	return nt
+}
+
+// SelectRows returns a new matrix of rows at idx (copy), indices may repeat and be in any order
+func (t *Matrix) SelectRows(idx []int) *Matrix {
+	row, col := t.Dims()
+	nt := ZeroMatrix(len(idx), col)
+	for k, i := range idx {
+		if i < 0 || i >= row {
+			panic("row index out of range")
+		}
+		copy(nt.Data[k], t.Data[i])
+	}
+	return nt
+}
+
+// SelectCols returns a new matrix of columns at idx (copy), indices may repeat and be in any order
+func (t *Matrix) SelectCols(idx []int) *Matrix {
+	row, col := t.Dims()
+	for _, j := range idx {
+		if j < 0 || j >= col {
+			panic("column index out of range")
+		}
+	}
+	nt := ZeroMatrix(row, len(idx))
+	for i := range t.Data {
+		for k, j := range idx {
+			nt.Data[i][k] = t.Data[i][j]
+		}
+	}
+	return nt
+}
+
+// maskIndices returns indices of true elements, panics if length of keep is not n
+func maskIndices(op string, keep []bool, n int) []int {
+	if len(keep) != n {
+		panic(lenError(op, n, len(keep)))
+	}
+	idx := make([]int, 0, n)
+	for i, k := range keep {
+		if k {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// SelectRowsMask returns a new matrix of rows whose keep flag is true,
+//	e.g. t.SelectRowsMask(t.Greater(0).AllRows()) keeps rows of all positive elements
+//	notice: the result has no rows if nothing is kept, `Dims` panics on it like on any empty matrix
+func (t *Matrix) SelectRowsMask(keep []bool) *Matrix {
+	row, _ := t.Dims()
+	return t.SelectRows(maskIndices("SelectRowsMask", keep, row))
+}
+
+// SelectColsMask returns a new matrix of columns whose keep flag is true
+func (t *Matrix) SelectColsMask(keep []bool) *Matrix {
+	_, col := t.Dims()
+	return t.SelectCols(maskIndices("SelectColsMask", keep, col))
+}
+
+// Select returns a new vector of elements at idx (copy), indices may repeat and be in any order
+func (v *Vector) Select(idx []int) *Vector {
+	nv := make(Vector, len(idx))
+	for k, i := range idx {
+		if i < 0 || i >= len(*v) {
+			panic("index out of range")
+		}
+		nv[k] = (*v)[i]
+	}
+	return &nv
+}
+
+// SelectMask returns a new vector of elements whose keep flag is true
+func (v *Vector) SelectMask(keep []bool) *Vector {
+	return v.Select(maskIndices("SelectMask", keep, len(*v)))
+}
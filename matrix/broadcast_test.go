@@ -0,0 +1,169 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMatrix_Broadcast(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 2, 3}, {4, 5, 6}})
+	row := Vector{10, 20, 30}
+	col := Vector{2, -1}
+	if !MEqual(a.AddElem(&row), new(Matrix).Init(Data{{11, 22, 33}, {14, 25, 36}})) {
+		t.Fail()
+	}
+	if !MEqual(a.MulElem(AsColumn(&col)), new(Matrix).Init(Data{{2, 4, 6}, {-4, -5, -6}})) {
+		t.Fail()
+	}
+	if !MEqual(a.DivElem(2), a.MulNum(0.5)) || !MEqual(a.SubElem(a), ZeroMatrix(2, 3)) {
+		t.Fail()
+	}
+	if !MEqual(a.PowElem(2.), a.MulElem(a)) {
+		t.Fail()
+	}
+	// row and column vectors broadcast to outer product shape
+	outer := Apply(&row, AsColumn(&col), func(x, y float64) float64 { return x * y })
+	if !MEqual(outer, new(Matrix).Init(Data{{20, 40, 60}, {-10, -20, -30}})) {
+		t.Fail()
+	}
+	// 1 x n matrix and Interface operands
+	if !MEqual(a.SubElem(a.GetSubMatrix(1, 0, 1, 3)), new(Matrix).Init(Data{{-3, -3, -3}, {0, 0, 0}})) {
+		t.Fail()
+	}
+	if !MEqual(a.MulElem(a.ToDense()), a.PowElem(2)) {
+		t.Fail()
+	}
+	// same result as the Tile workaround
+	x := GenerateRandomMatrix(20, 4)
+	if !MEqual(x.SubElem(x.Mean(0)), x.Sub(x.Mean(0).Tile(0, 20))) {
+		t.Fail()
+	}
+	if !MEqual(x.SubElem(AsColumn(x.Mean(1))), x.Sub(x.Mean(1).Tile(1, 4))) {
+		t.Fail()
+	}
+
+	if _, err := a.TryMulElem(&col); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatal(err)
+	}
+	if _, err := a.TryDivElem("2"); err == nil {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	a.AddElem(GenerateRandomMatrix(3, 3))
+}
+
+func TestMatrix_Compare(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, -2, 3}, {-4, 5, math.NaN()}})
+	m := a.Greater(0)
+	if m.Rows != 2 || m.Cols != 3 || m.Count() != 3 || !m.At(1, 1) || m.At(1, 2) {
+		t.Fail()
+	}
+	if a.Less(0).Count() != 2 || a.GreaterEqual(3).Count() != 2 || a.LessEqual(-2).Count() != 2 {
+		t.Fail()
+	}
+	if a.Equal(&Vector{1, 5, 3}).Count() != 3 || a.NotEqual(&Vector{1, 5, 3}).Count() != 3 {
+		t.Fail()
+	}
+	// NaN compares false, so only negated masks keep it
+	if m.Or(a.Less(0)).Count() != 5 || m.And(m.Not()).Count() != 0 || a.Less(0).Not().Count() != 4 {
+		t.Fail()
+	}
+	thr := Vector{0, 10}
+	if c := a.Greater(AsColumn(&thr)); c.Count() != 2 || c.AnyRows()[1] || !c.AnyRows()[0] {
+		t.Fail()
+	}
+	near := Compare(a, 1+EPS/10, FloatEqual)
+	if near.Count() != 1 || !near.At(0, 0) {
+		t.Fail()
+	}
+	if _, err := TryCompare(a, &Vector{1, 2}, FloatEqual); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatal(err)
+	}
+}
+
+func TestMatrix_Masked(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, -2, 3}, {-4, 5, -6}})
+	neg := a.Less(0)
+	if !VEqual(a.Masked(neg), &Vector{-2, -4, -6}) {
+		t.Fail()
+	}
+	if !MEqual(Where(neg, 0, a), new(Matrix).Init(Data{{1, 0, 3}, {0, 5, 0}})) {
+		t.Fail()
+	}
+	col := Vector{-1, 1}
+	if !MEqual(Where(neg, AsColumn(&col), &Vector{7, 8, 9}), new(Matrix).Init(Data{{7, -1, 9}, {1, 8, 1}})) {
+		t.Fail()
+	}
+	b := Copy(a)
+	b.SetMasked(neg, 0)
+	if !MEqual(b, Where(neg, 0, a)) || a.At(0, 1) != -2 {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	a.Masked(a.T().Less(0))
+}
+
+func TestMatrix_Select(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+	rows := a.SelectRows([]int{2, 0, 2})
+	if !MEqual(rows, new(Matrix).Init(Data{{7, 8, 9}, {1, 2, 3}, {7, 8, 9}})) {
+		t.Fail()
+	}
+	rows.Data[1][0] = 0
+	if a.At(0, 0) != 1 {
+		t.Fail()
+	}
+	if !MEqual(a.SelectCols([]int{1}), new(Matrix).Init(Data{{2}, {5}, {8}})) {
+		t.Fail()
+	}
+	if !MEqual(a.SelectRowsMask(a.Greater(3).AllRows()), a.GetSubMatrix(1, 0, 2, 3)) {
+		t.Fail()
+	}
+	if !MEqual(a.SelectColsMask([]bool{true, false, true}), a.SelectCols([]int{0, 2})) {
+		t.Fail()
+	}
+	v := Vector{1, 2, 3}
+	if !VEqual(v.Select([]int{2, 2, 0}), &Vector{3, 3, 1}) || !VEqual(v.SelectMask([]bool{false, true, true}), &Vector{2, 3}) {
+		t.Fail()
+	}
+	for _, f := range []func(){
+		func() { a.SelectRows([]int{3}) },
+		func() { a.SelectCols([]int{-1}) },
+		func() { a.SelectRowsMask([]bool{true}) },
+		func() { v.Select([]int{3}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fail()
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func BenchmarkMatrix_SubElem(b *testing.B) {
+	x := GenerateRandomMatrix(1000, 3)
+	mean := x.Mean(0)
+	for i := 0; i < b.N; i++ {
+		x.SubElem(mean)
+	}
+}
+
+func BenchmarkMatrix_SubTile(b *testing.B) {
+	x := GenerateRandomMatrix(1000, 3)
+	mean := x.Mean(0)
+	for i := 0; i < b.N; i++ {
+		x.Sub(mean.Tile(0, 1000))
+	}
+}
@@ -0,0 +1,268 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// ComplexMatrix is dense complex matrix stored row by row like `Matrix`
+//	it carries complex results (e.g. eigenvectors of non-symmetric matrix) of real matrices
+type ComplexMatrix struct {
+	Data [][]complex128
+}
+
+// ZeroComplexMatrix returns rows x cols complex matrix of zeros
+func ZeroComplexMatrix(rows, cols int) *ComplexMatrix {
+	data := make([][]complex128, rows)
+	buf := make([]complex128, rows*cols)
+	for i := range data {
+		data[i] = buf[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return &ComplexMatrix{Data: data}
+}
+
+// NewComplexMatrix returns complex matrix re + i*im, im can be nil for a real matrix
+func NewComplexMatrix(re, im *Matrix) *ComplexMatrix {
+	m, n := re.Dims()
+	if im != nil {
+		if m2, n2 := im.Dims(); m2 != m || n2 != n {
+			panic(dimsError("NewComplexMatrix", m, n, m2, n2))
+		}
+	}
+	cm := ZeroComplexMatrix(m, n)
+	for i := range cm.Data {
+		for j := range cm.Data[i] {
+			if im != nil {
+				cm.Data[i][j] = complex(re.Data[i][j], im.Data[i][j])
+			} else {
+				cm.Data[i][j] = complex(re.Data[i][j], 0)
+			}
+		}
+	}
+	return cm
+}
+
+// ComplexIdentity returns n x n complex identity matrix
+func ComplexIdentity(n int) *ComplexMatrix {
+	cm := ZeroComplexMatrix(n, n)
+	for i := 0; i < n; i++ {
+		cm.Data[i][i] = 1
+	}
+	return cm
+}
+
+// Dims returns numbers of rows and columns
+func (cm *ComplexMatrix) Dims() (int, int) {
+	if len(cm.Data) == 0 {
+		return 0, 0
+	}
+	return len(cm.Data), len(cm.Data[0])
+}
+
+// At returns element at row i, column j
+func (cm *ComplexMatrix) At(i, j int) complex128 {
+	return cm.Data[i][j]
+}
+
+// Set sets element at row i, column j
+func (cm *ComplexMatrix) Set(i, j int, v complex128) {
+	cm.Data[i][j] = v
+}
+
+// Row returns a copy of row i
+func (cm *ComplexMatrix) Row(i int) []complex128 {
+	return append([]complex128{}, cm.Data[i]...)
+}
+
+// Col returns a copy of column j
+func (cm *ComplexMatrix) Col(j int) []complex128 {
+	col := make([]complex128, len(cm.Data))
+	for i := range cm.Data {
+		col[i] = cm.Data[i][j]
+	}
+	return col
+}
+
+// Copy returns a deep copy
+func (cm *ComplexMatrix) Copy() *ComplexMatrix {
+	m, n := cm.Dims()
+	res := ZeroComplexMatrix(m, n)
+	for i := range cm.Data {
+		copy(res.Data[i], cm.Data[i])
+	}
+	return res
+}
+
+// Real returns real part as `Matrix`
+func (cm *ComplexMatrix) Real() *Matrix {
+	m, n := cm.Dims()
+	res := ZeroMatrix(m, n)
+	for i := range cm.Data {
+		for j, v := range cm.Data[i] {
+			res.Data[i][j] = real(v)
+		}
+	}
+	return res
+}
+
+// Imag returns imaginary part as `Matrix`
+func (cm *ComplexMatrix) Imag() *Matrix {
+	m, n := cm.Dims()
+	res := ZeroMatrix(m, n)
+	for i := range cm.Data {
+		for j, v := range cm.Data[i] {
+			res.Data[i][j] = imag(v)
+		}
+	}
+	return res
+}
+
+// T returns transpose (without conjugation)
+func (cm *ComplexMatrix) T() *ComplexMatrix {
+	m, n := cm.Dims()
+	res := ZeroComplexMatrix(n, m)
+	for i := range cm.Data {
+		for j, v := range cm.Data[i] {
+			res.Data[j][i] = v
+		}
+	}
+	return res
+}
+
+// H returns conjugate transpose
+func (cm *ComplexMatrix) H() *ComplexMatrix {
+	m, n := cm.Dims()
+	res := ZeroComplexMatrix(n, m)
+	for i := range cm.Data {
+		for j, v := range cm.Data[i] {
+			res.Data[j][i] = cmplx.Conj(v)
+		}
+	}
+	return res
+}
+
+// Mul returns cm * cm2
+func (cm *ComplexMatrix) Mul(cm2 *ComplexMatrix) *ComplexMatrix {
+	m, n := cm.Dims()
+	m2, n2 := cm2.Dims()
+	if n != m2 {
+		panic(dimsError("ComplexMatrix.Mul", m, n, m2, n2))
+	}
+	res := ZeroComplexMatrix(m, n2)
+	for i := range cm.Data {
+		for k, a := range cm.Data[i] {
+			if a == 0 {
+				continue
+			}
+			for j, b := range cm2.Data[k] {
+				res.Data[i][j] += a * b
+			}
+		}
+	}
+	return res
+}
+
+// MulVec returns cm * v
+func (cm *ComplexMatrix) MulVec(v []complex128) []complex128 {
+	m, n := cm.Dims()
+	if n != len(v) {
+		panic(dimsError("ComplexMatrix.MulVec", m, n, len(v), 1))
+	}
+	res := make([]complex128, m)
+	for i := range cm.Data {
+		for j, a := range cm.Data[i] {
+			res[i] += a * v[j]
+		}
+	}
+	return res
+}
+
+// TryInverse returns inverse by LU decomposition with partial pivoting, ErrNotSquare or ErrSingular
+func (cm *ComplexMatrix) TryInverse() (*ComplexMatrix, error) {
+	m, n := cm.Dims()
+	if m != n {
+		return nil, squareError("ComplexMatrix.Inverse", m, n)
+	}
+	lu := cm.Copy().Data
+	inv := ComplexIdentity(n).Data
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if cmplx.Abs(lu[i][k]) > cmplx.Abs(lu[p][k]) {
+				p = i
+			}
+		}
+		if lu[p][k] == 0 {
+			return nil, fmt.Errorf("ComplexMatrix.Inverse: %w (zero pivot in column %d)", ErrSingular, k)
+		}
+		lu[k], lu[p] = lu[p], lu[k]
+		inv[k], inv[p] = inv[p], inv[k]
+		for i := k + 1; i < n; i++ {
+			f := lu[i][k] / lu[k][k]
+			if f == 0 {
+				continue
+			}
+			for j := k; j < n; j++ {
+				lu[i][j] -= f * lu[k][j]
+			}
+			for j := range inv[i] {
+				inv[i][j] -= f * inv[k][j]
+			}
+		}
+	}
+	// back substitution, row by row of the identity columns together
+	for k := n - 1; k >= 0; k-- {
+		for j := range inv[k] {
+			s := inv[k][j]
+			for i := k + 1; i < n; i++ {
+				s -= lu[k][i] * inv[i][j]
+			}
+			inv[k][j] = s / lu[k][k]
+		}
+	}
+	return &ComplexMatrix{Data: inv}, nil
+}
+
+// Inverse returns inverse like `TryInverse`, panics on error
+func (cm *ComplexMatrix) Inverse() *ComplexMatrix {
+	inv, err := cm.TryInverse()
+	must(err)
+	return inv
+}
+
+// CEqual checks whether two complex matrices are equal, based on `FloatEqual` of real and imaginary parts
+func CEqual(cm1, cm2 *ComplexMatrix) bool {
+	m, n := cm1.Dims()
+	if m2, n2 := cm2.Dims(); m != m2 || n != n2 {
+		return false
+	}
+	for i := range cm1.Data {
+		for j, v := range cm1.Data[i] {
+			if !FloatEqual(real(v), real(cm2.Data[i][j])) || !FloatEqual(imag(v), imag(cm2.Data[i][j])) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// complexNorm returns 2-norm of complex vector
+func complexNorm(v []complex128) float64 {
+	scale, ssq := 0., 1.
+	for _, x := range v {
+		for _, a := range [2]float64{real(x), imag(x)} {
+			if a == 0 {
+				continue
+			}
+			a = math.Abs(a)
+			if scale < a {
+				ssq = 1 + ssq*(scale/a)*(scale/a)
+				scale = a
+			} else {
+				ssq += (a / scale) * (a / scale)
+			}
+		}
+	}
+	return scale * math.Sqrt(ssq)
+}
@@ -0,0 +1,35 @@
+package matrix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestComplexMatrix(t *testing.T) {
+	re := new(Matrix).Init(Data{{1, 2}, {3, 4}})
+	im := new(Matrix).Init(Data{{0, -1}, {1, 0}})
+	cm := NewComplexMatrix(re, im)
+	if cm.At(0, 1) != complex(2, -1) || !MEqual(cm.Real(), re) || !MEqual(cm.Imag(), im) {
+		t.Fail()
+	}
+	if cm.H().At(1, 0) != complex(2, 1) || cm.T().At(1, 0) != complex(2, -1) {
+		t.Fail()
+	}
+	// real input gives real product
+	if !MEqual(NewComplexMatrix(re, nil).Mul(NewComplexMatrix(re, nil)).Real(), re.Mul(re)) {
+		t.Fail()
+	}
+	if !CEqual(cm.Mul(cm.Inverse()), ComplexIdentity(2)) || !CEqual(cm.Inverse().Mul(cm), ComplexIdentity(2)) {
+		t.Fail()
+	}
+	v := cm.MulVec([]complex128{1, 1i})
+	if v[0] != complex(2, 2) || v[1] != complex(3, 5) {
+		t.Fatal(v)
+	}
+	if _, err := ZeroComplexMatrix(2, 2).TryInverse(); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if _, err := ZeroComplexMatrix(2, 3).TryInverse(); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,528 @@
+package matrix
+
+import (
+	"sort"
+	"sync"
+)
+
+// CSR struct
+//	https://en.wikipedia.org/wiki/Sparse_matrix#Compressed_sparse_row_(CSR,_CRS_or_Yale_format)
+//	Compressed sparse row, non-zeros of row i are Values[RowPtr[i]:RowPtr[i+1]] at columns ColIdx[RowPtr[i]:RowPtr[i+1]],
+//	column indexes inside one row are in ascending order
+type CSR struct {
+	Rows, Cols int
+	RowPtr     []int
+	ColIdx     []int
+	Values     []float64
+}
+
+// CSC struct
+//	Compressed sparse column, non-zeros of column j are Values[ColPtr[j]:ColPtr[j+1]] at rows RowIdx[ColPtr[j]:ColPtr[j+1]],
+//	row indexes inside one column are in ascending order
+type CSC struct {
+	Rows, Cols int
+	ColPtr     []int
+	RowIdx     []int
+	Values     []float64
+}
+
+// NewCSR assembles a CSR matrix from coordinate (COO) entries, duplicated entries are summed and zeros are dropped
+func NewCSR(rows, cols int, entries []Entry) *CSR {
+	for _, e := range entries {
+		if e.Row < 0 || e.Row >= rows || e.Col < 0 || e.Col >= cols {
+			panic("invalid index")
+		}
+	}
+	ptr := make([]int, rows+1)
+	for _, e := range entries {
+		ptr[e.Row+1]++
+	}
+	for i := 0; i < rows; i++ {
+		ptr[i+1] += ptr[i]
+	}
+	idx := make([]int, len(entries))
+	val := make([]float64, len(entries))
+	next := make([]int, rows)
+	copy(next, ptr[:rows])
+	for _, e := range entries {
+		p := next[e.Row]
+		idx[p], val[p] = e.Col, e.Value
+		next[e.Row]++
+	}
+	// sort each row by column, then merge duplicates and drop zeros in place
+	nnz := 0
+	start := 0
+	for i := 0; i < rows; i++ {
+		end := ptr[i+1]
+		sort.Sort(indexValueSorter{idx: idx[start:end], val: val[start:end]})
+		rowStart := nnz
+		for p := start; p < end; p++ {
+			if nnz > rowStart && idx[nnz-1] == idx[p] {
+				val[nnz-1] += val[p]
+				continue
+			}
+			idx[nnz], val[nnz] = idx[p], val[p]
+			nnz++
+		}
+		// drop zeros of this row
+		k := rowStart
+		for p := rowStart; p < nnz; p++ {
+			if val[p] != 0 {
+				idx[k], val[k] = idx[p], val[p]
+				k++
+			}
+		}
+		nnz = k
+		start = end
+		ptr[i+1] = nnz
+	}
+	return &CSR{
+		Rows:   rows,
+		Cols:   cols,
+		RowPtr: ptr,
+		ColIdx: idx[:nnz:nnz],
+		Values: val[:nnz:nnz],
+	}
+}
+
+// indexValueSorter sorts two parallel slices by index
+type indexValueSorter struct {
+	idx []int
+	val []float64
+}
+
+func (s indexValueSorter) Len() int           { return len(s.idx) }
+func (s indexValueSorter) Less(i, j int) bool { return s.idx[i] < s.idx[j] }
+func (s indexValueSorter) Swap(i, j int) {
+	s.idx[i], s.idx[j] = s.idx[j], s.idx[i]
+	s.val[i], s.val[j] = s.val[j], s.val[i]
+}
+
+// Entries returns all non-zero entries of sparse matrix as coordinate (COO) list, unordered
+func (sm *SparseMatrix) Entries() []Entry {
+	entries := make([]Entry, 0, len(sm.Data))
+	for idx, value := range sm.Data {
+		r, c := sm.IndexToRowCol(idx)
+		if r < 0 || r >= sm.Rows || c < 0 || c >= sm.Cols {
+			continue // out of a sub-sparse-matrix view
+		}
+		entries = append(entries, Entry{Value: value, Row: r, Col: c})
+	}
+	return entries
+}
+
+// ToCSR converts sparse matrix (DOK, used for assembly) to compressed sparse row format
+func (sm *SparseMatrix) ToCSR() *CSR {
+	return NewCSR(sm.Rows, sm.Cols, sm.Entries())
+}
+
+// ToCSC converts sparse matrix (DOK, used for assembly) to compressed sparse column format
+func (sm *SparseMatrix) ToCSC() *CSC {
+	return sm.ToCSR().ToCSC()
+}
+
+// NNZ returns number of stored non-zeros
+func (c *CSR) NNZ() int {
+	return len(c.Values)
+}
+
+// Dims returns CSR matrix dimensions in row, col
+func (c *CSR) Dims() (row, col int) {
+	return c.Rows, c.Cols
+}
+
+// RowNonZeros returns column indexes and values of non-zeros in row i, they are views of internal storage
+func (c *CSR) RowNonZeros(i int) (cols []int, values []float64) {
+	if i < 0 || i >= c.Rows {
+		panic("row index out of range")
+	}
+	s, e := c.RowPtr[i], c.RowPtr[i+1]
+	return c.ColIdx[s:e:e], c.Values[s:e:e]
+}
+
+// find returns position of (i, j) in storage and whether it exists, binary search inside row i
+func (c *CSR) find(i, j int) (int, bool) {
+	if i < 0 || i >= c.Rows || j < 0 || j >= c.Cols {
+		panic("invalid index")
+	}
+	s, e := c.RowPtr[i], c.RowPtr[i+1]
+	p := s + sort.SearchInts(c.ColIdx[s:e], j)
+	return p, p < e && c.ColIdx[p] == j
+}
+
+// At returns element value at row i, column j
+func (c *CSR) At(i, j int) float64 {
+	if p, ok := c.find(i, j); ok {
+		return c.Values[p]
+	}
+	return 0
+}
+
+// Set sets value at row i, column j
+//	notice: value == 0. indicates deletion, inserting or deleting costs O(nnz), build with `NewCSR` or `SparseMatrix` instead
+func (c *CSR) Set(i, j int, value float64) {
+	p, ok := c.find(i, j)
+	switch {
+	case ok && value != 0:
+		c.Values[p] = value
+		return
+	case ok:
+		c.ColIdx = append(c.ColIdx[:p], c.ColIdx[p+1:]...)
+		c.Values = append(c.Values[:p], c.Values[p+1:]...)
+		for r := i + 1; r <= c.Rows; r++ {
+			c.RowPtr[r]--
+		}
+	case value != 0:
+		c.ColIdx = append(c.ColIdx, 0)
+		c.Values = append(c.Values, 0)
+		copy(c.ColIdx[p+1:], c.ColIdx[p:])
+		copy(c.Values[p+1:], c.Values[p:])
+		c.ColIdx[p], c.Values[p] = j, value
+		for r := i + 1; r <= c.Rows; r++ {
+			c.RowPtr[r]++
+		}
+	}
+}
+
+// Row returns a new dense row vector
+func (c *CSR) Row(i int) *Vector {
+	v := make(Vector, c.Cols)
+	cols, values := c.RowNonZeros(i)
+	for k, j := range cols {
+		v[j] = values[k]
+	}
+	return &v
+}
+
+// Col returns a new dense column vector
+//	notice: it costs O(Rows * log(nnz per row)), use `ToCSC` for repeated column access
+func (c *CSR) Col(j int) *Vector {
+	if j < 0 || j >= c.Cols {
+		panic("column index out of range")
+	}
+	v := make(Vector, c.Rows)
+	for i := range v {
+		v[i] = c.At(i, j)
+	}
+	return &v
+}
+
+// Copy returns a deep copy of CSR matrix
+func (c *CSR) Copy() *CSR {
+	return &CSR{
+		Rows:   c.Rows,
+		Cols:   c.Cols,
+		RowPtr: append([]int(nil), c.RowPtr...),
+		ColIdx: append([]int(nil), c.ColIdx...),
+		Values: append([]float64(nil), c.Values...),
+	}
+}
+
+// compressedTranspose transposes compressed storage by counting sort: (n major, m minor) -> (m major, n major)
+func compressedTranspose(n, m int, ptr, idx []int, val []float64) ([]int, []int, []float64) {
+	nPtr := make([]int, m+1)
+	for _, j := range idx {
+		nPtr[j+1]++
+	}
+	for j := 0; j < m; j++ {
+		nPtr[j+1] += nPtr[j]
+	}
+	nIdx := make([]int, len(idx))
+	nVal := make([]float64, len(val))
+	next := make([]int, m)
+	copy(next, nPtr[:m])
+	for i := 0; i < n; i++ {
+		for p := ptr[i]; p < ptr[i+1]; p++ {
+			q := next[idx[p]]
+			nIdx[q], nVal[q] = i, val[p]
+			next[idx[p]]++
+		}
+	}
+	return nPtr, nIdx, nVal
+}
+
+// ToCSC converts CSR to CSC of the same matrix in O(nnz)
+func (c *CSR) ToCSC() *CSC {
+	ptr, idx, val := compressedTranspose(c.Rows, c.Cols, c.RowPtr, c.ColIdx, c.Values)
+	return &CSC{Rows: c.Rows, Cols: c.Cols, ColPtr: ptr, RowIdx: idx, Values: val}
+}
+
+// T returns transpose matrix in CSR format in O(nnz), no hashing involved
+func (c *CSR) T() *CSR {
+	ptr, idx, val := compressedTranspose(c.Rows, c.Cols, c.RowPtr, c.ColIdx, c.Values)
+	return &CSR{Rows: c.Cols, Cols: c.Rows, RowPtr: ptr, ColIdx: idx, Values: val}
+}
+
+// RowSlice returns a new CSR matrix consists of rows [i0, i1) in O(nnz of the slice)
+func (c *CSR) RowSlice(i0, i1 int) *CSR {
+	if i0 < 0 || i1 > c.Rows || i0 > i1 {
+		panic("row slice out of range")
+	}
+	s, e := c.RowPtr[i0], c.RowPtr[i1]
+	ptr := make([]int, i1-i0+1)
+	for i := range ptr {
+		ptr[i] = c.RowPtr[i0+i] - s
+	}
+	return &CSR{
+		Rows:   i1 - i0,
+		Cols:   c.Cols,
+		RowPtr: ptr,
+		ColIdx: append([]int(nil), c.ColIdx[s:e]...),
+		Values: append([]float64(nil), c.Values[s:e]...),
+	}
+}
+
+// ToMatrix transfers CSR matrix into matrix (dense)
+func (c *CSR) ToMatrix() *Matrix {
+	nm := ZeroMatrix(c.Rows, c.Cols)
+	for i := 0; i < c.Rows; i++ {
+		for p := c.RowPtr[i]; p < c.RowPtr[i+1]; p++ {
+			nm.Data[i][c.ColIdx[p]] = c.Values[p]
+		}
+	}
+	return nm
+}
+
+// ToSparseMatrix transfers CSR matrix into dictionary of keys sparse matrix
+func (c *CSR) ToSparseMatrix() *SparseMatrix {
+	sm := ZeroSparseMatrix(c.Rows, c.Cols)
+	for i := 0; i < c.Rows; i++ {
+		for p := c.RowPtr[i]; p < c.RowPtr[i+1]; p++ {
+			sm.Set(i, c.ColIdx[p], c.Values[p])
+		}
+	}
+	return sm
+}
+
+// spmvMinNNZ is the minimal non-zeros for `CSR.MulVec` to run in parallel
+const spmvMinNNZ = 1 << 16
+
+// MulVec multiplies CSR matrix with input vector and returns a new vector in O(nnz)
+//	rows are split among `MulConfig.Workers` goroutines for large matrices
+func (c *CSR) MulVec(v *Vector) *Vector {
+	if c.Cols != v.Length() {
+		panic(dimsError("CSR.MulVec", c.Rows, c.Cols, v.Length(), 1))
+	}
+	res := make(Vector, c.Rows)
+	x := *v
+	spmv := func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			s := 0.
+			for p := c.RowPtr[i]; p < c.RowPtr[i+1]; p++ {
+				s += c.Values[p] * x[c.ColIdx[p]]
+			}
+			res[i] = s
+		}
+	}
+	workers := GetMulConfig().Workers
+	if workers <= 1 || c.NNZ() < spmvMinNNZ || c.Rows < workers {
+		spmv(0, c.Rows)
+		return &res
+	}
+	var wg sync.WaitGroup
+	chunk := (c.Rows + workers - 1) / workers
+	for i0 := 0; i0 < c.Rows; i0 += chunk {
+		wg.Add(1)
+		go func(i0, i1 int) {
+			defer wg.Done()
+			spmv(i0, i1)
+		}(i0, MinInt(i0+chunk, c.Rows))
+	}
+	wg.Wait()
+	return &res
+}
+
+// MulVecT multiplies transpose of CSR matrix with input vector in O(nnz) and returns a new vector
+func (c *CSR) MulVecT(v *Vector) *Vector {
+	if c.Rows != v.Length() {
+		panic(dimsError("CSR.MulVecT", c.Cols, c.Rows, v.Length(), 1))
+	}
+	// CSR of A is CSC of A.T()
+	t := &CSC{Rows: c.Cols, Cols: c.Rows, ColPtr: c.RowPtr, RowIdx: c.ColIdx, Values: c.Values}
+	return t.MulVec(v)
+}
+
+// Mul does sparse-sparse matrix multiplication (Gustavson's algorithm) and returns a new CSR matrix
+//	it costs O(flops) with a dense accumulator of length Cols
+func (c *CSR) Mul(c2 *CSR) *CSR {
+	if c.Cols != c2.Rows {
+		panic(dimsError("CSR.Mul", c.Rows, c.Cols, c2.Rows, c2.Cols))
+	}
+	ptr := make([]int, c.Rows+1)
+	idx := make([]int, 0, c.NNZ()+c2.NNZ())
+	val := make([]float64, 0, c.NNZ()+c2.NNZ())
+	acc := make([]float64, c2.Cols)
+	mark := make([]int, c2.Cols)
+	for j := range mark {
+		mark[j] = -1
+	}
+	touched := make([]int, 0, c2.Cols)
+	for i := 0; i < c.Rows; i++ {
+		touched = touched[:0]
+		for p := c.RowPtr[i]; p < c.RowPtr[i+1]; p++ {
+			k, a := c.ColIdx[p], c.Values[p]
+			for q := c2.RowPtr[k]; q < c2.RowPtr[k+1]; q++ {
+				j := c2.ColIdx[q]
+				if mark[j] != i {
+					mark[j] = i
+					acc[j] = 0
+					touched = append(touched, j)
+				}
+				acc[j] += a * c2.Values[q]
+			}
+		}
+		sort.Ints(touched)
+		for _, j := range touched {
+			if acc[j] != 0 {
+				idx = append(idx, j)
+				val = append(val, acc[j])
+			}
+		}
+		ptr[i+1] = len(idx)
+	}
+	return &CSR{Rows: c.Rows, Cols: c2.Cols, RowPtr: ptr, ColIdx: idx, Values: val}
+}
+
+// Add sums two CSR matrices and returns a new CSR matrix, merging sorted rows in O(nnz)
+func (c *CSR) Add(c2 *CSR) *CSR {
+	if c.Rows != c2.Rows || c.Cols != c2.Cols {
+		panic(dimsError("CSR.Add", c.Rows, c.Cols, c2.Rows, c2.Cols))
+	}
+	ptr := make([]int, c.Rows+1)
+	idx := make([]int, 0, c.NNZ()+c2.NNZ())
+	val := make([]float64, 0, c.NNZ()+c2.NNZ())
+	push := func(j int, v float64) {
+		if v != 0 {
+			idx = append(idx, j)
+			val = append(val, v)
+		}
+	}
+	for i := 0; i < c.Rows; i++ {
+		p, pe := c.RowPtr[i], c.RowPtr[i+1]
+		q, qe := c2.RowPtr[i], c2.RowPtr[i+1]
+		for p < pe || q < qe {
+			switch {
+			case q == qe || (p < pe && c.ColIdx[p] < c2.ColIdx[q]):
+				push(c.ColIdx[p], c.Values[p])
+				p++
+			case p == pe || c2.ColIdx[q] < c.ColIdx[p]:
+				push(c2.ColIdx[q], c2.Values[q])
+				q++
+			default:
+				push(c.ColIdx[p], c.Values[p]+c2.Values[q])
+				p++
+				q++
+			}
+		}
+		ptr[i+1] = len(idx)
+	}
+	return &CSR{Rows: c.Rows, Cols: c.Cols, RowPtr: ptr, ColIdx: idx, Values: val}
+}
+
+// MulNum multiplies CSR matrix elements with input number and returns a new CSR matrix
+func (c *CSR) MulNum(n float64) *CSR {
+	if n == 0 {
+		return &CSR{Rows: c.Rows, Cols: c.Cols, RowPtr: make([]int, c.Rows+1)}
+	}
+	nc := c.Copy()
+	for p := range nc.Values {
+		nc.Values[p] *= n
+	}
+	return nc
+}
+
+// NNZ returns number of stored non-zeros
+func (c *CSC) NNZ() int {
+	return len(c.Values)
+}
+
+// Dims returns CSC matrix dimensions in row, col
+func (c *CSC) Dims() (row, col int) {
+	return c.Rows, c.Cols
+}
+
+// ColNonZeros returns row indexes and values of non-zeros in column j, they are views of internal storage
+func (c *CSC) ColNonZeros(j int) (rows []int, values []float64) {
+	if j < 0 || j >= c.Cols {
+		panic("column index out of range")
+	}
+	s, e := c.ColPtr[j], c.ColPtr[j+1]
+	return c.RowIdx[s:e:e], c.Values[s:e:e]
+}
+
+// transposed returns the CSR view of transpose (same storage), it is used to share CSR implementations
+func (c *CSC) transposed() *CSR {
+	return &CSR{Rows: c.Cols, Cols: c.Rows, RowPtr: c.ColPtr, ColIdx: c.RowIdx, Values: c.Values}
+}
+
+// At returns element value at row i, column j
+func (c *CSC) At(i, j int) float64 {
+	return c.transposed().At(j, i)
+}
+
+// Set sets value at row i, column j
+//	notice: value == 0. indicates deletion, inserting or deleting costs O(nnz)
+func (c *CSC) Set(i, j int, value float64) {
+	t := c.transposed()
+	t.Set(j, i, value)
+	c.RowIdx, c.Values = t.ColIdx, t.Values
+}
+
+// Row returns a new dense row vector
+//	notice: it costs O(Cols * log(nnz per column)), use `ToCSR` for repeated row access
+func (c *CSC) Row(i int) *Vector {
+	return c.transposed().Col(i)
+}
+
+// Col returns a new dense column vector
+func (c *CSC) Col(j int) *Vector {
+	return c.transposed().Row(j)
+}
+
+// ToCSR converts CSC to CSR of the same matrix in O(nnz)
+func (c *CSC) ToCSR() *CSR {
+	return c.transposed().T()
+}
+
+// T returns transpose matrix in CSC format in O(nnz)
+func (c *CSC) T() *CSC {
+	t := c.transposed().T()
+	return &CSC{Rows: c.Cols, Cols: c.Rows, ColPtr: t.RowPtr, RowIdx: t.ColIdx, Values: t.Values}
+}
+
+// ColSlice returns a new CSC matrix consists of columns [j0, j1) in O(nnz of the slice)
+func (c *CSC) ColSlice(j0, j1 int) *CSC {
+	t := c.transposed().RowSlice(j0, j1)
+	return &CSC{Rows: c.Rows, Cols: j1 - j0, ColPtr: t.RowPtr, RowIdx: t.ColIdx, Values: t.Values}
+}
+
+// MulVec multiplies CSC matrix with input vector and returns a new vector in O(nnz)
+func (c *CSC) MulVec(v *Vector) *Vector {
+	if c.Cols != v.Length() {
+		panic(dimsError("CSC.MulVec", c.Rows, c.Cols, v.Length(), 1))
+	}
+	res := make(Vector, c.Rows)
+	for j := 0; j < c.Cols; j++ {
+		x := (*v)[j]
+		if x == 0 {
+			continue
+		}
+		for p := c.ColPtr[j]; p < c.ColPtr[j+1]; p++ {
+			res[c.RowIdx[p]] += c.Values[p] * x
+		}
+	}
+	return &res
+}
+
+// MulVecT multiplies transpose of CSC matrix with input vector in O(nnz) and returns a new vector
+func (c *CSC) MulVecT(v *Vector) *Vector {
+	if c.Rows != v.Length() {
+		panic(dimsError("CSC.MulVecT", c.Cols, c.Rows, v.Length(), 1))
+	}
+	return c.transposed().MulVec(v)
+}
+
+// ToMatrix transfers CSC matrix into matrix (dense)
+func (c *CSC) ToMatrix() *Matrix {
+	return c.ToCSR().ToMatrix()
+}
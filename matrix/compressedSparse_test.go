@@ -0,0 +1,190 @@
+package matrix
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestNewCSR(t *testing.T) {
+	c := NewCSR(3, 4, []Entry{
+		{Value: 1, Row: 2, Col: 3},
+		{Value: 2, Row: 0, Col: 1},
+		{Value: 3, Row: 0, Col: 0},
+		{Value: 4, Row: 2, Col: 3}, // duplicate, summed
+		{Value: 5, Row: 1, Col: 2},
+		{Value: -5, Row: 1, Col: 2}, // cancelled, dropped
+	})
+	if c.NNZ() != 3 {
+		t.Fail()
+	}
+	if !MEqual(c.ToMatrix(), new(Matrix).Init(Data{{3, 2, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 5}})) {
+		t.Fail()
+	}
+	if c.RowPtr[0] != 0 || c.RowPtr[1] != 2 || c.RowPtr[2] != 2 || c.RowPtr[3] != 3 {
+		t.Fail()
+	}
+	if c.ColIdx[0] != 0 || c.ColIdx[1] != 1 {
+		t.Fail()
+	}
+}
+
+func TestSparseMatrix_ToCSR(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(30, 20, 80)
+	c := sm.ToCSR()
+	if c.NNZ() != len(sm.Data) || !MEqual(c.ToMatrix(), sm.ToMatrix()) {
+		t.Fail()
+	}
+	if !MEqual(c.ToSparseMatrix().ToMatrix(), sm.ToMatrix()) {
+		t.Fail()
+	}
+	csc := sm.ToCSC()
+	if !MEqual(csc.ToMatrix(), sm.ToMatrix()) || !MEqual(csc.ToCSR().ToMatrix(), sm.ToMatrix()) {
+		t.Fail()
+	}
+	for i := 0; i < 30; i++ {
+		if !VEqual(c.Row(i), sm.Row(i)) || !VEqual(csc.Row(i), sm.Row(i)) {
+			t.Fail()
+		}
+	}
+	for j := 0; j < 20; j++ {
+		if !VEqual(c.Col(j), sm.Col(j)) || !VEqual(csc.Col(j), sm.Col(j)) {
+			t.Fail()
+		}
+	}
+}
+
+func TestCSR_AtSet(t *testing.T) {
+	c := NewCSR(2, 3, []Entry{{Value: 1, Row: 0, Col: 1}, {Value: 2, Row: 1, Col: 2}})
+	c.Set(0, 0, 3)
+	c.Set(1, 2, 4)
+	c.Set(0, 1, 0)
+	if c.NNZ() != 2 || !MEqual(c.ToMatrix(), new(Matrix).Init(Data{{3, 0, 0}, {0, 0, 4}})) {
+		t.Fail()
+	}
+	if c.At(0, 0) != 3 || c.At(0, 1) != 0 || c.At(1, 2) != 4 {
+		t.Fail()
+	}
+	csc := c.ToCSC()
+	csc.Set(1, 0, 5)
+	csc.Set(0, 0, 0)
+	if csc.NNZ() != 2 || !MEqual(csc.ToMatrix(), new(Matrix).Init(Data{{0, 0, 0}, {5, 0, 4}})) {
+		t.Fail()
+	}
+	if csc.At(1, 0) != 5 || csc.At(0, 0) != 0 {
+		t.Fail()
+	}
+}
+
+func TestCSR_MulVec(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(40, 30, 200)
+	v := GenerateRandomVector(30)
+	if !VEqual(sm.ToCSR().MulVec(v), sm.ToMatrix().MulVec(v)) {
+		t.Fail()
+	}
+	if !VEqual(sm.ToCSC().MulVec(v), sm.ToMatrix().MulVec(v)) {
+		t.Fail()
+	}
+	// parallel path
+	big := GenerateRandomSparseMatrix(1000, 1000, 2*spmvMinNNZ)
+	bv := GenerateRandomVector(1000)
+	if !VEqual(big.ToCSR().MulVec(bv), big.MulVec(bv)) {
+		t.Fail()
+	}
+}
+
+func TestCSR_Mul(t *testing.T) {
+	sa := GenerateRandomSparseMatrix(30, 20, 60)
+	sb := GenerateRandomSparseMatrix(20, 10, 40)
+	res := sa.ToCSR().Mul(sb.ToCSR())
+	if r, c := res.Dims(); r != 30 || c != 10 {
+		t.Fail()
+	}
+	if !MEqual(res.ToMatrix(), sa.ToMatrix().Mul(sb.ToMatrix())) {
+		t.Fail()
+	}
+	for i := 0; i < res.Rows; i++ {
+		cols, _ := res.RowNonZeros(i)
+		for k := 1; k < len(cols); k++ {
+			if cols[k-1] >= cols[k] {
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestCSR_Add(t *testing.T) {
+	sa := GenerateRandomSparseMatrix(20, 20, 60)
+	sb := GenerateRandomSparseMatrix(20, 20, 60)
+	if !MEqual(sa.ToCSR().Add(sb.ToCSR()).ToMatrix(), sa.ToMatrix().Add(sb.ToMatrix())) {
+		t.Fail()
+	}
+	c := sa.ToCSR()
+	if c.Add(c.MulNum(-1)).NNZ() != 0 {
+		t.Fail()
+	}
+	if !MEqual(c.MulNum(2).ToMatrix(), sa.ToMatrix().MulNum(2)) {
+		t.Fail()
+	}
+}
+
+func TestCSR_T(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(30, 20, 80)
+	if !MEqual(sm.ToCSR().T().ToMatrix(), sm.ToMatrix().T()) {
+		t.Fail()
+	}
+	if !MEqual(sm.ToCSC().T().ToMatrix(), sm.ToMatrix().T()) {
+		t.Fail()
+	}
+}
+
+func TestCSR_Slice(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(30, 20, 80)
+	m := sm.ToMatrix()
+	rs := sm.ToCSR().RowSlice(5, 12)
+	if !MEqual(rs.ToMatrix(), m.GetSubMatrix(5, 0, 7, 20)) {
+		t.Fail()
+	}
+	cs := sm.ToCSC().ColSlice(3, 9)
+	if !MEqual(cs.ToMatrix(), m.GetSubMatrix(0, 3, 30, 6)) {
+		t.Fail()
+	}
+	if sm.ToCSR().RowSlice(4, 4).NNZ() != 0 {
+		t.Fail()
+	}
+}
+
+func TestCSR_Interface(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(30, 5, 60)
+	if !MEqual(CovMatrixOf(sm.ToCSR(), nil), sm.ToMatrix().CovMatrix()) {
+		t.Fail()
+	}
+	if !MEqual(AsMatrix(sm.ToCSC()), sm.ToMatrix()) {
+		t.Fail()
+	}
+}
+
+func BenchmarkCSR_MulVec(b *testing.B) {
+	for _, n := range []int{100000, 1000000} {
+		b.Run("size-"+strconv.Itoa(n)+"x"+strconv.Itoa(n), func(b *testing.B) {
+			c := GenerateRandomSparseMatrix(n, n, 10*n).ToCSR()
+			v := GenerateRandomVector(n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.MulVec(v)
+			}
+		})
+	}
+}
+
+func BenchmarkCSR_Mul(b *testing.B) {
+	for _, n := range []int{10000, 100000} {
+		b.Run("size-"+strconv.Itoa(n)+"x"+strconv.Itoa(n), func(b *testing.B) {
+			ca := GenerateRandomSparseMatrix(n, n, 10*n).ToCSR()
+			cb := GenerateRandomSparseMatrix(n, n, 10*n).ToCSR()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ca.Mul(cb)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// CovAccumulator is streaming mean and covariance accumulator, rows are added one by one or in batches
+//	without keeping them, so covariance of data larger than memory (e.g. streamed point clouds) can be computed
+//	weighted update of West (1979) is used for single rows, and accumulators built on different goroutines
+//	(or batches) are combined by `Merge` with the parallel update of Chan et al. (1979)
+//	https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance#Parallel_algorithm
+type CovAccumulator struct {
+	count   int
+	weight  float64
+	mean    Vector
+	scatter *SymmetricMatrix // Σ w * (x - mean) * (x - mean).T()
+}
+
+// NewCovAccumulator returns empty accumulator of dim dimensional rows
+func NewCovAccumulator(dim int) *CovAccumulator {
+	return &CovAccumulator{mean: make(Vector, dim), scatter: ZeroSymmetricMatrix(dim)}
+}
+
+// Dim returns dimension of accumulated rows
+func (a *CovAccumulator) Dim() int {
+	return len(a.mean)
+}
+
+// Count returns number of added rows (including zero weighted ones)
+func (a *CovAccumulator) Count() int {
+	return a.count
+}
+
+// Weight returns sum of weights of added rows, equal to `Count` for unweighted rows
+func (a *CovAccumulator) Weight() float64 {
+	return a.weight
+}
+
+// Add adds one row of weight 1
+func (a *CovAccumulator) Add(x *Vector) {
+	a.AddWeighted(x, 1)
+}
+
+// AddWeighted adds one row of weight w >= 0
+func (a *CovAccumulator) AddWeighted(x *Vector, w float64) {
+	if len(*x) != len(a.mean) {
+		panic(lenError("CovAccumulator.Add", len(a.mean), len(*x)))
+	}
+	if w < 0 || math.IsNaN(w) {
+		panic(fmt.Sprintf("CovAccumulator.Add: invalid weight %g", w))
+	}
+	a.count++
+	if w == 0 {
+		return
+	}
+	total := a.weight + w
+	// scatter grows by w * W / (W + w) * d * d.T() with deviation d from the old mean
+	if f := w * a.weight / total; f != 0 {
+		for i, v := range *x {
+			fd := f * (v - a.mean[i])
+			si := a.scatter.Data[i*(i+1)/2 : i*(i+1)/2+i+1]
+			for j, u := range (*x)[:i+1] {
+				si[j] += fd * (u - a.mean[j])
+			}
+		}
+	}
+	for i, v := range *x {
+		a.mean[i] += (v - a.mean[i]) * w / total
+	}
+	a.weight = total
+}
+
+// AddRows adds all rows of t, weights is optional (nil for weight 1 of every row)
+func (a *CovAccumulator) AddRows(t Interface, weights *Vector) {
+	row, _ := t.Dims()
+	if weights != nil && weights.Length() != row {
+		panic(lenError("CovAccumulator.AddRows", row, weights.Length()))
+	}
+	batch := NewCovAccumulator(len(a.mean))
+	for i := 0; i < row; i++ {
+		w := 1.
+		if weights != nil {
+			w = weights.At(i)
+		}
+		batch.AddWeighted(t.Row(i), w)
+	}
+	a.Merge(batch)
+}
+
+// Merge adds all rows accumulated by b into a, b is not changed
+func (a *CovAccumulator) Merge(b *CovAccumulator) {
+	if len(a.mean) != len(b.mean) {
+		panic(lenError("CovAccumulator.Merge", len(a.mean), len(b.mean)))
+	}
+	total := a.weight + b.weight
+	a.count += b.count
+	if b.weight == 0 {
+		return
+	}
+	if a.weight == 0 {
+		copy(a.mean, b.mean)
+		copy(a.scatter.Data, b.scatter.Data)
+		a.weight = total
+		return
+	}
+	f := a.weight * b.weight / total
+	delta := *b.mean.Sub(&a.mean)
+	for i := range a.mean {
+		si := a.scatter.Data[i*(i+1)/2 : i*(i+1)/2+i+1]
+		for j := range si {
+			si[j] += b.scatter.Data[i*(i+1)/2+j] + f*delta[i]*delta[j]
+		}
+		a.mean[i] += delta[i] * b.weight / total
+	}
+	a.weight = total
+}
+
+// Reset empties the accumulator, its storage is reused
+func (a *CovAccumulator) Reset() {
+	a.count, a.weight = 0, 0
+	scaleBy(0, a.mean)
+	scaleBy(0, a.scatter.Data)
+}
+
+// Mean returns weighted mean of added rows, NaN for empty accumulator
+func (a *CovAccumulator) Mean() *Vector {
+	mean := append(Vector{}, a.mean...)
+	if a.weight == 0 {
+		for i := range mean {
+			mean[i] = math.NaN()
+		}
+	}
+	return &mean
+}
+
+// Scatter returns scatter matrix Σ w * (x - mean) * (x - mean).T() in packed symmetric storage (copy)
+func (a *CovAccumulator) Scatter() *SymmetricMatrix {
+	return &SymmetricMatrix{N: a.scatter.N, Data: append([]float64{}, a.scatter.Data...)}
+}
+
+// Covariance returns population covariance matrix, scatter normalized by sum of weights
+//	NaN for empty accumulator
+func (a *CovAccumulator) Covariance() *Matrix {
+	return a.scatter.MulNum(1 / a.weight).ToMatrix()
+}
+
+// SampleCovariance returns unbiased covariance matrix, scatter normalized by sum of weights - 1
+//	weights are treated as frequencies (repeat counts), NaN if sum of weights is not larger than one
+func (a *CovAccumulator) SampleCovariance() *Matrix {
+	if a.weight <= 1 {
+		return a.scatter.MulNum(math.NaN()).ToMatrix()
+	}
+	return a.scatter.MulNum(1 / (a.weight - 1)).ToMatrix()
+}
+
+// Correlation returns Pearson correlation matrix, entries of zero variance columns are NaN
+func (a *CovAccumulator) Correlation() *Matrix {
+	n := len(a.mean)
+	std := make(Vector, n)
+	for i := range std {
+		std[i] = math.Sqrt(a.scatter.At(i, i))
+	}
+	corr := ZeroMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			c := a.scatter.At(i, j) / (std[i] * std[j])
+			if i == j && std[i] != 0 {
+				c = 1
+			}
+			corr.Data[i][j], corr.Data[j][i] = c, c
+		}
+	}
+	return corr
+}
@@ -0,0 +1,117 @@
+package matrix
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestCovAccumulator(t *testing.T) {
+	x := GenerateRandomMatrix(40, 3)
+	acc := NewCovAccumulator(3)
+	for i := range x.Data {
+		acc.Add(x.Row(i))
+	}
+	scatter := x.Sub(x.Mean(0).Tile(0, 40))
+	scatter = scatter.T().Mul(scatter)
+	if acc.Count() != 40 || acc.Weight() != 40 || !VEqual(acc.Mean(), x.Mean(0)) {
+		t.Fail()
+	}
+	if !MEqual(acc.Scatter().ToMatrix(), scatter) || !MEqual(acc.Covariance(), scatter.MulNum(1./40)) {
+		t.Fail()
+	}
+	if !MEqual(acc.SampleCovariance(), scatter.MulNum(1./39)) {
+		t.Fail()
+	}
+	corr := acc.Correlation()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			want := scatter.At(i, j) / math.Sqrt(scatter.At(i, i)*scatter.At(j, j))
+			if !FloatEqual(corr.At(i, j), want) {
+				t.Fatal(i, j)
+			}
+		}
+	}
+
+	// batches and rows give the same result
+	batch := NewCovAccumulator(3)
+	batch.AddRows(x.GetSubMatrix(0, 0, 15, 3), nil)
+	batch.AddRows(x.GetSubMatrix(15, 0, 25, 3).ToDense(), nil)
+	if batch.Count() != 40 || !VEqual(batch.Mean(), acc.Mean()) || !MEqual(batch.Covariance(), acc.Covariance()) {
+		t.Fail()
+	}
+
+	acc.Reset()
+	if acc.Count() != 0 || !math.IsNaN(acc.Mean().At(0)) || !math.IsNaN(acc.Covariance().At(0, 0)) {
+		t.Fail()
+	}
+}
+
+func TestCovAccumulator_Weighted(t *testing.T) {
+	// integer weights are repeat counts
+	x := new(Matrix).Init(Data{{1, 2}, {3, -1}, {0, 5}, {2, 2}})
+	weights := Vector{2, 0, 3, 1}
+	repeated := new(Matrix).Init(Data{{1, 2}, {1, 2}, {0, 5}, {0, 5}, {0, 5}, {2, 2}})
+	acc, want := NewCovAccumulator(2), NewCovAccumulator(2)
+	acc.AddRows(x, &weights)
+	want.AddRows(repeated, nil)
+	if acc.Count() != 4 || acc.Weight() != 6 || !VEqual(acc.Mean(), want.Mean()) {
+		t.Fail()
+	}
+	if !MEqual(acc.Covariance(), want.Covariance()) || !MEqual(acc.SampleCovariance(), want.SampleCovariance()) {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	acc.AddWeighted(&Vector{1, 1}, -1)
+}
+
+func TestCovAccumulator_Merge(t *testing.T) {
+	// UTM like coordinates: large offset, small spread
+	x := GenerateRandomMatrix(4000, 3)
+	shifted := x.AddNum(1e6)
+	parts := make([]*CovAccumulator, 4)
+	var wg sync.WaitGroup
+	for p := range parts {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			parts[p] = NewCovAccumulator(3)
+			for i := p * 1000; i < (p+1)*1000; i++ {
+				parts[p].Add(shifted.Row(i))
+			}
+		}(p)
+	}
+	wg.Wait()
+	acc := NewCovAccumulator(3)
+	for _, p := range parts {
+		acc.Merge(p)
+	}
+	ref := NewCovAccumulator(3)
+	ref.AddRows(x, nil)
+	if acc.Count() != 4000 || !VEqual(acc.Mean(), ref.Mean().AddNum(1e6)) {
+		t.Fail()
+	}
+	if d := acc.Covariance().Sub(ref.Covariance()).NormInf(); d > 1e-9 {
+		t.Fatal(d)
+	}
+	// merging empty accumulators changes nothing
+	acc.Merge(NewCovAccumulator(3))
+	empty := NewCovAccumulator(3)
+	empty.Merge(acc)
+	if !MEqual(empty.Covariance(), acc.Covariance()) || empty.Count() != 4000 {
+		t.Fail()
+	}
+}
+
+func BenchmarkCovAccumulator_Add(b *testing.B) {
+	x := GenerateRandomMatrix(1000, 3)
+	acc := NewCovAccumulator(3)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		acc.Add(x.Row(i % 1000))
+	}
+}
@@ -0,0 +1,288 @@
+package matrix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// textFormat is the name of delimited text format in errors
+const textFormat = "text"
+
+// NaNPolicy tells text reader what to do with missing or NaN fields
+//	empty fields and "NA", "N/A", "null" (case insensitive) are missing, they are read as NaN like "NaN" itself
+type NaNPolicy int
+
+const (
+	NaNKeep    NaNPolicy = iota // keep NaN in result
+	NaNSkipRow                  // drop rows containing NaN
+	NaNFill                     // replace NaN with `TextReadOptions.FillValue`
+	NaNError                    // return error wrapping ErrFormat
+)
+
+// TextReadOptions configures delimited text reading, zero value reads whitespace separated numbers
+type TextReadOptions struct {
+	Delimiter rune      // field delimiter, 0 for any run of whitespace, ',' for CSV, '\t' for TSV
+	Comment   string    // lines starting with it (after leading whitespace) are skipped, "" for none
+	Header    bool      // first non-comment line is a header row
+	Columns   []int     // 0-based columns to read in given order, nil for all
+	NaN       NaNPolicy // handling of missing / NaN fields
+	FillValue float64   // value for NaNFill
+}
+
+// TextReader reads delimited text row by row, it can stream rows in batches so large files are never held twice
+type TextReader struct {
+	opts   TextReadOptions
+	s      *bufio.Scanner
+	line   int
+	header []string
+	cols   int // number of columns of result, -1 before the first data row
+	fields []string
+	done   bool
+}
+
+// NewTextReader returns a text reader over r
+func NewTextReader(r io.Reader, opts TextReadOptions) *TextReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	cols := -1
+	if opts.Columns != nil {
+		cols = len(opts.Columns)
+	}
+	return &TextReader{opts: opts, s: s, cols: cols}
+}
+
+// split splits one line into fields, surrounding spaces and double quotes of fields are removed
+func (tr *TextReader) split(text string) []string {
+	if tr.opts.Delimiter == 0 {
+		return strings.Fields(text)
+	}
+	tr.fields = tr.fields[:0]
+	for {
+		k := strings.IndexRune(text, tr.opts.Delimiter)
+		f := text
+		if k >= 0 {
+			f = text[:k]
+		}
+		f = strings.TrimSpace(f)
+		if len(f) >= 2 && f[0] == '"' && f[len(f)-1] == '"' {
+			f = f[1 : len(f)-1]
+		}
+		tr.fields = append(tr.fields, f)
+		if k < 0 {
+			return tr.fields
+		}
+		text = text[k+len(string(tr.opts.Delimiter)):]
+	}
+}
+
+// nextFields returns fields of the next non-empty, non-comment line, io.EOF if no more
+func (tr *TextReader) nextFields() ([]string, error) {
+	for tr.s.Scan() {
+		tr.line++
+		text := strings.TrimSpace(tr.s.Text())
+		if text == "" || (tr.opts.Comment != "" && strings.HasPrefix(text, tr.opts.Comment)) {
+			continue
+		}
+		return tr.split(text), nil
+	}
+	if err := tr.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// readHeader consumes header row once if there is one
+func (tr *TextReader) readHeader() error {
+	if !tr.opts.Header || tr.header != nil {
+		return nil
+	}
+	fields, err := tr.nextFields()
+	if err == io.EOF {
+		return lineError(textFormat, tr.line, "missing header")
+	} else if err != nil {
+		return err
+	}
+	tr.header = make([]string, 0, len(fields))
+	if tr.opts.Columns == nil {
+		tr.header = append(tr.header, fields...)
+		return nil
+	}
+	for _, c := range tr.opts.Columns {
+		if c < 0 || c >= len(fields) {
+			return lineError(textFormat, tr.line, "column %d out of %d header fields", c, len(fields))
+		}
+		tr.header = append(tr.header, fields[c])
+	}
+	return nil
+}
+
+// Header returns names of (selected) columns, it is nil if `TextReadOptions.Header` is false
+func (tr *TextReader) Header() ([]string, error) {
+	if err := tr.readHeader(); err != nil {
+		return nil, err
+	}
+	return tr.header, nil
+}
+
+// parseField parses one field, missing fields are NaN
+func parseField(f string) (float64, bool) {
+	switch strings.ToLower(f) {
+	case "", "na", "n/a", "null":
+		return math.NaN(), true
+	}
+	v, err := strconv.ParseFloat(f, 64)
+	return v, err == nil
+}
+
+// readRow parses the next data row into dst (appended), returns false at the end of input
+func (tr *TextReader) readRow(dst Vector) (Vector, bool, error) {
+	for {
+		fields, err := tr.nextFields()
+		if err == io.EOF {
+			return dst, false, nil
+		} else if err != nil {
+			return dst, false, err
+		}
+		if tr.cols < 0 {
+			tr.cols = len(fields)
+		}
+		if tr.opts.Columns == nil && len(fields) != tr.cols {
+			return dst, false, lineError(textFormat, tr.line, "expect %d fields, got %d", tr.cols, len(fields))
+		}
+		start := len(dst)
+		skip := false
+		for k := 0; k < tr.cols; k++ {
+			c := k
+			if tr.opts.Columns != nil {
+				if c = tr.opts.Columns[k]; c < 0 || c >= len(fields) {
+					return dst, false, lineError(textFormat, tr.line, "column %d out of %d fields", c, len(fields))
+				}
+			}
+			v, ok := parseField(fields[c])
+			if !ok {
+				return dst, false, lineError(textFormat, tr.line, "column %d: invalid number %q", c, fields[c])
+			}
+			if math.IsNaN(v) {
+				switch tr.opts.NaN {
+				case NaNSkipRow:
+					skip = true
+				case NaNFill:
+					v = tr.opts.FillValue
+				case NaNError:
+					return dst, false, lineError(textFormat, tr.line, "column %d: NaN or missing value %q", c, fields[c])
+				}
+			}
+			dst = append(dst, v)
+		}
+		if !skip {
+			return dst, true, nil
+		}
+		dst = dst[:start]
+	}
+}
+
+// ReadBatch reads at most n rows into a new matrix, io.EOF is returned only when no row is left
+func (tr *TextReader) ReadBatch(n int) (*Matrix, error) {
+	if tr.done {
+		return nil, io.EOF
+	}
+	if err := tr.readHeader(); err != nil {
+		return nil, err
+	}
+	var buf Vector
+	rows := 0
+	for rows < n {
+		var ok bool
+		var err error
+		if buf, ok, err = tr.readRow(buf); err != nil {
+			return nil, err
+		}
+		if !ok {
+			tr.done = true
+			break
+		}
+		rows++
+	}
+	if rows == 0 {
+		return nil, io.EOF
+	}
+	return NewDense(rows, tr.cols, buf).ToMatrix(), nil
+}
+
+// ReadAll reads all remaining rows into a new matrix, it is an empty matrix if there is no row
+func (tr *TextReader) ReadAll() (*Matrix, error) {
+	mat, err := tr.ReadBatch(math.MaxInt32)
+	if err == io.EOF {
+		return new(Matrix).Init(Data{}), nil
+	}
+	return mat, err
+}
+
+// LoadText reads delimited text file into matrix
+func LoadText(path string, opts TextReadOptions) (*Matrix, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return NewTextReader(file, opts).ReadAll()
+}
+
+// TextWriteOptions configures delimited text writing, zero value writes space separated lossless numbers
+type TextWriteOptions struct {
+	Delimiter rune     // field delimiter, 0 for ' '
+	Format    byte     // 'f', 'e' or 'g' as in strconv.FormatFloat, 0 for the shortest representation reading back the same float64
+	Precision int      // precision for Format, ignored if Format is 0
+	Header    []string // optional header row
+}
+
+// WriteText writes matrix as delimited text
+func WriteText(w io.Writer, t *Matrix, opts TextWriteOptions) error {
+	delim := opts.Delimiter
+	if delim == 0 {
+		delim = ' '
+	}
+	format, precision := opts.Format, opts.Precision
+	if format == 0 {
+		format, precision = 'g', -1
+	}
+	bw := bufio.NewWriter(w)
+	if opts.Header != nil {
+		if _, err := fmt.Fprintln(bw, strings.Join(opts.Header, string(delim))); err != nil {
+			return err
+		}
+	}
+	buf := make([]byte, 0, 256)
+	for i := range t.Data {
+		buf = buf[:0]
+		for j, v := range t.Data[i] {
+			if j > 0 {
+				buf = append(buf, string(delim)...)
+			}
+			buf = strconv.AppendFloat(buf, v, format, precision, 64)
+		}
+		buf = append(buf, '\n')
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// SaveText writes matrix into delimited text file
+func SaveText(path string, t *Matrix, opts TextWriteOptions) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = WriteText(file, t, opts); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,146 @@
+package matrix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTextReader(t *testing.T) {
+	csv := `# exported from sensors
+x,y,z,label
+1,2,3,"a"
+4, 5 ,6,b
+
+# a gap
+7,8,9,c
+`
+	tr := NewTextReader(strings.NewReader(csv), TextReadOptions{Delimiter: ',', Comment: "#", Header: true, Columns: []int{2, 0}})
+	header, err := tr.Header()
+	if err != nil || len(header) != 2 || header[0] != "z" || header[1] != "x" {
+		t.Fatal(header, err)
+	}
+	mat, err := tr.ReadAll()
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{3, 1}, {6, 4}, {9, 7}})) {
+		t.Fatal(mat, err)
+	}
+
+	tsv := "1\t2\n3\t4\n"
+	mat, err = NewTextReader(strings.NewReader(tsv), TextReadOptions{Delimiter: '\t'}).ReadAll()
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1, 2}, {3, 4}})) {
+		t.Fatal(mat, err)
+	}
+
+	ws := "  1 2   3 4\n5\t6 7 8\n"
+	mat, err = NewTextReader(strings.NewReader(ws), TextReadOptions{}).ReadAll()
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1, 2, 3, 4}, {5, 6, 7, 8}})) {
+		t.Fatal(mat, err)
+	}
+
+	mat, err = NewTextReader(strings.NewReader(""), TextReadOptions{}).ReadAll()
+	if err != nil || len(mat.Data) != 0 {
+		t.Fail()
+	}
+}
+
+func TestTextReaderNaN(t *testing.T) {
+	csv := "1,2\n,4\nNaN,NA\n5,6\n"
+	read := func(policy NaNPolicy) (*Matrix, error) {
+		return NewTextReader(strings.NewReader(csv), TextReadOptions{Delimiter: ',', NaN: policy, FillValue: -1}).ReadAll()
+	}
+	mat, err := read(NaNKeep)
+	if err != nil || len(mat.Data) != 4 || !math.IsNaN(mat.At(1, 0)) || !math.IsNaN(mat.At(2, 1)) || mat.At(3, 1) != 6 {
+		t.Fail()
+	}
+	mat, err = read(NaNSkipRow)
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1, 2}, {5, 6}})) {
+		t.Fail()
+	}
+	mat, err = read(NaNFill)
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1, 2}, {-1, 4}, {-1, -1}, {5, 6}})) {
+		t.Fail()
+	}
+	if _, err = read(NaNError); !errors.Is(err, ErrFormat) || !strings.Contains(err.Error(), "line 2") {
+		t.Fail()
+	}
+}
+
+func TestTextReaderErrors(t *testing.T) {
+	cases := []struct {
+		input string
+		opts  TextReadOptions
+		line  string
+	}{
+		{"1 2\n3 4 5\n", TextReadOptions{}, "line 2"},
+		{"1 2\n3 x\n", TextReadOptions{}, "line 2"},
+		{"# c\n1 2\n3\n", TextReadOptions{Comment: "#", Columns: []int{1}}, "line 3"},
+		{"a,b\n", TextReadOptions{Delimiter: ',', Header: true, Columns: []int{2}}, "line 1"},
+		{"", TextReadOptions{Header: true}, "line 0"},
+	}
+	for _, c := range cases {
+		_, err := NewTextReader(strings.NewReader(c.input), c.opts).ReadAll()
+		if !errors.Is(err, ErrFormat) || !strings.Contains(err.Error(), c.line) {
+			t.Errorf("%q: %v", c.input, err)
+		}
+	}
+}
+
+func TestTextReader_ReadBatch(t *testing.T) {
+	mat := GenerateRandomMatrix(25, 3)
+	var buf bytes.Buffer
+	if err := WriteText(&buf, mat, TextWriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTextReader(&buf, TextReadOptions{})
+	var rows int
+	for {
+		batch, err := tr.ReadBatch(10)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if r, c := batch.Dims(); c != 3 || r > 10 || (r != 10 && rows+r != 25) {
+			t.Fail()
+		}
+		for i := range batch.Data {
+			if !VEqual(&batch.Data[i], &mat.Data[rows+i]) {
+				t.Fail()
+			}
+		}
+		rows += len(batch.Data)
+	}
+	if rows != 25 {
+		t.Fail()
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	mat := new(Matrix).Init(Data{{math.Pi, -1}, {1e-20, 2.5}})
+	var buf bytes.Buffer
+	if err := WriteText(&buf, mat, TextWriteOptions{Delimiter: ',', Format: 'f', Precision: 3, Header: []string{"a", "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a,b\n3.142,-1.000\n0.000,2.500\n" {
+		t.Fatal(buf.String())
+	}
+	buf.Reset()
+	// lossless by default
+	if err := WriteText(&buf, mat, TextWriteOptions{Delimiter: '\t'}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewTextReader(&buf, TextReadOptions{Delimiter: '\t'}).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range mat.Data {
+		for j := range mat.Data[i] {
+			if res.Data[i][j] != mat.Data[i][j] {
+				t.Fail()
+			}
+		}
+	}
+}
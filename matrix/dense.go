@@ -0,0 +1,276 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dense struct
+//	contiguous row-major storage, element (i, j) locates at Data[i*Stride+j]
+//	sub-matrices, rows and columns got by `Slice`, `RowView`, `ColView` and `Row` are views sharing storage with parent,
+//	so writing on them writes through to the parent
+type Dense struct {
+	Rows, Cols int
+	Stride     int // distance between starts of two adjacent rows in Data, Stride >= Cols
+	Data       []float64
+}
+
+// NewDense generates a new dense matrix with input row-major data and dims
+//	notice: data is used as backend directly without copy, nil data means a zero matrix
+func NewDense(rows, cols int, data []float64) *Dense {
+	if rows < 0 || cols < 0 {
+		panic("negative dimension")
+	}
+	if data == nil {
+		data = make([]float64, rows*cols)
+	}
+	if len(data) != rows*cols {
+		panic(fmt.Sprintf("invalid data length %d for dense matrix (%d x %d)", len(data), rows, cols))
+	}
+	return &Dense{
+		Rows:   rows,
+		Cols:   cols,
+		Stride: cols,
+		Data:   data,
+	}
+}
+
+// ZeroDense generates dense matrix with all elements are zero
+func ZeroDense(rows, cols int) *Dense {
+	return NewDense(rows, cols, nil)
+}
+
+// IdentityDense generates dense diagonal matrix with ones as diagonal elements
+func IdentityDense(n int) *Dense {
+	d := ZeroDense(n, n)
+	for i := 0; i < n; i++ {
+		d.Data[i*d.Stride+i] = 1
+	}
+	return d
+}
+
+// NewDenseFromMatrix copies matrix into a new contiguous dense matrix
+func NewDenseFromMatrix(t *Matrix) *Dense {
+	row, col := t.Dims()
+	d := ZeroDense(row, col)
+	for i := range t.Data {
+		copy(d.Data[i*d.Stride:i*d.Stride+col], t.Data[i])
+	}
+	return d
+}
+
+// ToDense copies matrix into a new contiguous dense matrix
+func (t *Matrix) ToDense() *Dense {
+	return NewDenseFromMatrix(t)
+}
+
+// ToMatrix returns a matrix whose rows are views of the dense storage
+//	notice: no copy, changes on the returned matrix elements write through to the dense matrix,
+//	so all functions accepting *Matrix (e.g. decompositions) work on the dense layout directly
+func (d *Dense) ToMatrix() *Matrix {
+	data := make(Data, d.Rows)
+	for i := range data {
+		data[i] = d.rowSlice(i)
+	}
+	return &Matrix{Data: data}
+}
+
+// rowSlice returns i-th row as a slice of storage, capacity is limited so append on it never overwrites next row
+func (d *Dense) rowSlice(i int) []float64 {
+	s := i * d.Stride
+	return d.Data[s : s+d.Cols : s+d.Cols]
+}
+
+func (d *Dense) checkIndex(i, j int) {
+	if i < 0 || i >= d.Rows || j < 0 || j >= d.Cols {
+		panic("index out of range")
+	}
+}
+
+// Dims returns dense matrix dimensions in row, col
+func (d *Dense) Dims() (row, col int) {
+	return d.Rows, d.Cols
+}
+
+// At returns element value at row i, column j
+func (d *Dense) At(i, j int) float64 {
+	d.checkIndex(i, j)
+	return d.Data[i*d.Stride+j]
+}
+
+// Set sets element with value at row i, column j
+func (d *Dense) Set(i, j int, value float64) {
+	d.checkIndex(i, j)
+	d.Data[i*d.Stride+j] = value
+}
+
+// IsContiguous checks whether elements are stored without gap between rows
+func (d *Dense) IsContiguous() bool {
+	return d.Stride == d.Cols || d.Rows <= 1
+}
+
+// Slice returns a sub-matrix view starting at i, j with rows rows and cols columns
+//	notice: no copy, it shares storage with the original dense matrix
+func (d *Dense) Slice(i, j, rows, cols int) *Dense {
+	if i < 0 || j < 0 || rows < 0 || cols < 0 || i+rows > d.Rows || j+cols > d.Cols {
+		panic("sub-matrix out of range")
+	}
+	if rows == 0 || cols == 0 {
+		return &Dense{Rows: rows, Cols: cols, Stride: d.Stride}
+	}
+	s := i*d.Stride + j
+	return &Dense{
+		Rows:   rows,
+		Cols:   cols,
+		Stride: d.Stride,
+		Data:   d.Data[s : s+(rows-1)*d.Stride+cols],
+	}
+}
+
+// RowView returns m-th row as a 1 x Cols view
+func (d *Dense) RowView(m int) *Dense {
+	return d.Slice(m, 0, 1, d.Cols)
+}
+
+// ColView returns n-th column as a Rows x 1 view
+func (d *Dense) ColView(n int) *Dense {
+	return d.Slice(0, n, d.Rows, 1)
+}
+
+// Row returns m-th row vector, it is a view sharing storage with dense matrix
+func (d *Dense) Row(m int) *Vector {
+	if m < 0 || m >= d.Rows {
+		panic("row index out of range")
+	}
+	v := Vector(d.rowSlice(m))
+	return &v
+}
+
+// Col returns a copy of n-th column vector
+//	notice: columns are strided in storage, use `ColView` for a view
+func (d *Dense) Col(n int) *Vector {
+	if n < 0 || n >= d.Cols {
+		panic("column index out of range")
+	}
+	v := make(Vector, d.Rows)
+	for i := range v {
+		v[i] = d.Data[i*d.Stride+n]
+	}
+	return &v
+}
+
+// Copy returns a contiguous deep copy of dense matrix
+func (d *Dense) Copy() *Dense {
+	nd := ZeroDense(d.Rows, d.Cols)
+	for i := 0; i < d.Rows; i++ {
+		copy(nd.Data[i*nd.Stride:i*nd.Stride+d.Cols], d.rowSlice(i))
+	}
+	return nd
+}
+
+// T returns a new transposed dense matrix
+func (d *Dense) T() *Dense {
+	nd := ZeroDense(d.Cols, d.Rows)
+	for i := 0; i < d.Rows; i++ {
+		for j, v := range d.rowSlice(i) {
+			nd.Data[j*nd.Stride+i] = v
+		}
+	}
+	return nd
+}
+
+// Max returns the first max entry
+func (d *Dense) Max() *Entry {
+	entry := Entry{Value: math.Inf(-1)}
+	for i := 0; i < d.Rows; i++ {
+		for j, v := range d.rowSlice(i) {
+			if v > entry.Value {
+				entry.Value = v
+				entry.Row, entry.Col = i, j
+			}
+		}
+	}
+	return &entry
+}
+
+// Min returns the first min entry
+func (d *Dense) Min() *Entry {
+	entry := Entry{Value: math.Inf(1)}
+	for i := 0; i < d.Rows; i++ {
+		for j, v := range d.rowSlice(i) {
+			if v < entry.Value {
+				entry.Value = v
+				entry.Row, entry.Col = i, j
+			}
+		}
+	}
+	return &entry
+}
+
+// Rank returns rank of dense matrix
+func (d *Dense) Rank() int {
+	return d.ToMatrix().Rank()
+}
+
+// Add sums two dense matrices and returns a new dense matrix
+func (d *Dense) Add(d2 *Dense) *Dense {
+	if d.Rows != d2.Rows || d.Cols != d2.Cols {
+		panic("both matrices should have the same dimension")
+	}
+	nd := ZeroDense(d.Rows, d.Cols)
+	for i := 0; i < d.Rows; i++ {
+		r, r2, nr := d.rowSlice(i), d2.rowSlice(i), nd.rowSlice(i)
+		for j := range nr {
+			nr[j] = r[j] + r2[j]
+		}
+	}
+	return nd
+}
+
+// Sub subtracts two dense matrices and returns a new dense matrix
+func (d *Dense) Sub(d2 *Dense) *Dense {
+	if d.Rows != d2.Rows || d.Cols != d2.Cols {
+		panic("both matrices should have the same dimension")
+	}
+	nd := ZeroDense(d.Rows, d.Cols)
+	for i := 0; i < d.Rows; i++ {
+		r, r2, nr := d.rowSlice(i), d2.rowSlice(i), nd.rowSlice(i)
+		for j := range nr {
+			nr[j] = r[j] - r2[j]
+		}
+	}
+	return nd
+}
+
+// MulNum multiplies all elements with input number and returns a new dense matrix
+func (d *Dense) MulNum(n float64) *Dense {
+	nd := ZeroDense(d.Rows, d.Cols)
+	for i := 0; i < d.Rows; i++ {
+		r, nr := d.rowSlice(i), nd.rowSlice(i)
+		for j := range nr {
+			nr[j] = r[j] * n
+		}
+	}
+	return nd
+}
+
+// Mul does dense matrix multiplication and returns a new dense matrix, it shares the kernel of `Matrix.Mul`
+func (d *Dense) Mul(d2 *Dense) *Dense {
+	if d.Cols != d2.Rows {
+		panic("matrix multiplication need M x N and N x L matrices to get M x L matrix")
+	}
+	nd := ZeroDense(d.Rows, d2.Cols)
+	if d.Rows == 0 || d.Cols == 0 || d2.Cols == 0 {
+		return nd
+	}
+	gemm(d.ToMatrix(), d2.ToMatrix(), nd.ToMatrix())
+	return nd
+}
+
+// String for pretty-print of dense matrix
+func (d *Dense) String() string {
+	if d == nil {
+		return "{nil}"
+	}
+	return d.ToMatrix().String()
+}
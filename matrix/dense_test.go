@@ -0,0 +1,130 @@
+package matrix
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewDense(t *testing.T) {
+	d := NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	if r, c := d.Dims(); r != 2 || c != 3 || d.At(1, 2) != 6 || !d.IsContiguous() {
+		t.Fail()
+	}
+	if !MEqual(IdentityDense(3).ToMatrix(), IdentityMatrix(3)) || !MEqual(ZeroDense(2, 2).ToMatrix(), ZeroMatrix(2, 2)) {
+		t.Fail()
+	}
+}
+
+func TestDense_ToMatrix(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+	d := NewDenseFromMatrix(a)
+	if !MEqual(d.ToMatrix(), a) || !MEqual(a.ToDense().ToMatrix(), a) {
+		t.Fail()
+	}
+	// write through
+	m := d.ToMatrix()
+	m.Set(1, 1, 10)
+	if d.At(1, 1) != 10 || a.At(1, 1) != 5 {
+		t.Fail()
+	}
+	// append on row never overwrites next row
+	_ = append(m.Data[0], 100)
+	if d.At(1, 0) != 4 {
+		t.Fail()
+	}
+}
+
+func TestDense_Slice(t *testing.T) {
+	d := NewDense(3, 4, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+	s := d.Slice(1, 1, 2, 2)
+	if !MEqual(s.ToMatrix(), new(Matrix).Init(Data{{6, 7}, {10, 11}})) || s.IsContiguous() {
+		t.Fail()
+	}
+	s.Set(0, 1, -1)
+	if d.At(1, 2) != -1 {
+		t.Fail()
+	}
+	// view of view
+	ss := s.Slice(1, 0, 1, 2)
+	ss.Set(0, 0, -2)
+	if d.At(2, 1) != -2 {
+		t.Fail()
+	}
+	if !MEqual(s.Copy().ToMatrix(), s.ToMatrix()) || !s.Copy().IsContiguous() {
+		t.Fail()
+	}
+}
+
+func TestDense_RowCol(t *testing.T) {
+	d := NewDense(3, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	if !VEqual(d.Row(1), &Vector{4, 5, 6}) || !VEqual(d.Col(1), &Vector{2, 5, 8}) {
+		t.Fail()
+	}
+	(*d.Row(0))[2] = 30
+	d.ColView(0).Set(2, 0, 70)
+	d.RowView(1).Set(0, 1, 50)
+	if d.At(0, 2) != 30 || d.At(2, 0) != 70 || d.At(1, 1) != 50 {
+		t.Fail()
+	}
+	if !VEqual(d.ColView(2).T().Row(0), d.Col(2)) {
+		t.Fail()
+	}
+}
+
+func TestDense_Arithmetic(t *testing.T) {
+	a := new(Matrix).Init(Data{{10, 20, 10}, {-20, -30, 10}, {30, 50, 0}})
+	b := new(Matrix).Init(Data{{32, 12, 1}, {6, 3, 45}, {9, 2, 1}})
+	da, db := a.ToDense(), b.ToDense()
+	if !MEqual(da.Add(db).ToMatrix(), a.Add(b)) || !MEqual(da.Sub(db).ToMatrix(), a.Sub(b)) ||
+		!MEqual(da.Mul(db).ToMatrix(), a.Mul(b)) || !MEqual(da.MulNum(3).ToMatrix(), a.MulNum(3)) ||
+		!MEqual(da.T().ToMatrix(), a.T()) {
+		t.Fail()
+	}
+	// strided operands
+	big := GenerateRandomMatrix(6, 6).ToDense()
+	s1, s2 := big.Slice(0, 0, 3, 4), big.Slice(2, 1, 4, 3)
+	if !MEqual(s1.Mul(s2).ToMatrix(), s1.Copy().ToMatrix().Mul(s2.Copy().ToMatrix())) {
+		t.Fail()
+	}
+	if da.Max().Value != 50 || da.Min().Value != -30 || da.Rank() != 2 {
+		t.Fail()
+	}
+}
+
+func TestDense_Decompositions(t *testing.T) {
+	d := NewDense(3, 3, []float64{4, 12, -16, 12, 37, -43, -16, -43, 98})
+	L := CholeskyDecomposition(d.ToMatrix())
+	if !MEqual(L, new(Matrix).Init(Data{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}})) {
+		t.Fail()
+	}
+	// decomposition on a strided sub-matrix view
+	big := ZeroDense(5, 5)
+	view := big.Slice(1, 1, 3, 3)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			view.Set(i, j, d.At(i, j))
+		}
+	}
+	if !FloatEqual(view.ToMatrix().Det(), d.ToMatrix().Det()) {
+		t.Fail()
+	}
+	V, D := EigenDecompose(view.ToMatrix())
+	if !MEqual(V.Mul(D).Mul(V.T()), d.ToMatrix()) {
+		t.Fail()
+	}
+}
+
+func BenchmarkDense_Slice(b *testing.B) {
+	for k := 1.0; k <= 3; k++ {
+		n := int(math.Pow(10, k))
+		b.Run("size-"+strconv.Itoa(n), func(b *testing.B) {
+			m := GenerateRandomSquareMatrix(n)
+			d := m.ToDense()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d.Slice(n/4, n/4, n/2, n/2)
+			}
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package matrix
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Binary format
+//	header: magic "GLNA", version byte, kind byte ('V' vector, 'M' matrix, 'S' sparse matrix)
+//	vector: uvarint length, float64 bits in little endian
+//	matrix: uvarint rows, uvarint cols, float64 bits in little endian, row major
+//	sparse matrix: uvarint rows, uvarint cols, uvarint nnz, then for each entry in row major order
+//	uvarint delta of linear index (row * cols + col) from the previous entry and float64 bits in little endian
+const (
+	binaryFormat  = "binary"
+	binaryMagic   = "GLNA"
+	binaryVersion = 1
+
+	binaryKindVector = 'V'
+	binaryKindMatrix = 'M'
+	binaryKindSparse = 'S'
+
+	// binaryMaxEmptyRows bounds row number of matrix without columns, such rows take no bytes in the input
+	binaryMaxEmptyRows = 1 << 20
+)
+
+var (
+	_ encoding.BinaryMarshaler   = Vector(nil)
+	_ encoding.BinaryUnmarshaler = (*Vector)(nil)
+	_ encoding.BinaryMarshaler   = (*Matrix)(nil)
+	_ encoding.BinaryUnmarshaler = (*Matrix)(nil)
+	_ encoding.BinaryMarshaler   = (*SparseMatrix)(nil)
+	_ encoding.BinaryUnmarshaler = (*SparseMatrix)(nil)
+	_ json.Marshaler             = Vector(nil)
+	_ json.Unmarshaler           = (*Vector)(nil)
+	_ json.Marshaler             = (*Matrix)(nil)
+	_ json.Unmarshaler           = (*Matrix)(nil)
+	_ json.Marshaler             = (*SparseMatrix)(nil)
+	_ json.Unmarshaler           = (*SparseMatrix)(nil)
+)
+
+// binaryHeader returns header of binary format with given kind
+func binaryHeader(kind byte, capacity int) []byte {
+	buf := make([]byte, 0, len(binaryMagic)+2+capacity)
+	buf = append(buf, binaryMagic...)
+	return append(buf, binaryVersion, kind)
+}
+
+// binaryDecoder reads binary format and tracks offset for error reporting
+type binaryDecoder struct {
+	data   []byte
+	offset int
+}
+
+// newBinaryDecoder checks header of data
+func newBinaryDecoder(data []byte, kind byte) (*binaryDecoder, error) {
+	if len(data) < len(binaryMagic)+2 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return nil, offsetError(binaryFormat, 0, "invalid magic")
+	}
+	if v := data[len(binaryMagic)]; v != binaryVersion {
+		return nil, offsetError(binaryFormat, int64(len(binaryMagic)), "unsupported version %d", v)
+	}
+	if k := data[len(binaryMagic)+1]; k != kind {
+		return nil, offsetError(binaryFormat, int64(len(binaryMagic)+1), "expect kind %q, got %q", kind, k)
+	}
+	return &binaryDecoder{data: data, offset: len(binaryMagic) + 2}, nil
+}
+
+// uvarint reads a non-negative int, values beyond int range are rejected
+func (d *binaryDecoder) uvarint() (int, error) {
+	v, n := binary.Uvarint(d.data[d.offset:])
+	if n <= 0 || v > math.MaxInt {
+		return 0, offsetError(binaryFormat, int64(d.offset), "invalid integer")
+	}
+	d.offset += n
+	return int(v), nil
+}
+
+// floats reads n float64, it checks remaining size first so corrupted lengths never allocate
+func (d *binaryDecoder) floats(n int) ([]float64, error) {
+	if n < 0 || n > (len(d.data)-d.offset)/8 {
+		return nil, offsetError(binaryFormat, int64(d.offset), "expect %d float64, only %d bytes left", n, len(d.data)-d.offset)
+	}
+	res := make([]float64, n)
+	for k := range res {
+		res[k] = math.Float64frombits(binary.LittleEndian.Uint64(d.data[d.offset:]))
+		d.offset += 8
+	}
+	return res, nil
+}
+
+func (d *binaryDecoder) end() error {
+	if d.offset != len(d.data) {
+		return offsetError(binaryFormat, int64(d.offset), "%d trailing bytes", len(d.data)-d.offset)
+	}
+	return nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+func appendFloat64s(buf []byte, values []float64) []byte {
+	var b [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+		buf = append(buf, b[:]...)
+	}
+	return buf
+}
+
+// MarshalBinary encodes vector in versioned binary format, float64 bits are kept exactly
+//	value receiver, so that both Vector and *Vector are encoded by encoding/gob and encoding/json
+func (v Vector) MarshalBinary() ([]byte, error) {
+	buf := binaryHeader(binaryKindVector, binary.MaxVarintLen64+8*len(v))
+	buf = appendUvarint(buf, uint64(len(v)))
+	return appendFloat64s(buf, v), nil
+}
+
+// UnmarshalBinary decodes vector from binary format, error wraps ErrFormat
+func (v *Vector) UnmarshalBinary(data []byte) error {
+	d, err := newBinaryDecoder(data, binaryKindVector)
+	if err != nil {
+		return err
+	}
+	n, err := d.uvarint()
+	if err != nil {
+		return err
+	}
+	values, err := d.floats(n)
+	if err != nil {
+		return err
+	}
+	if err = d.end(); err != nil {
+		return err
+	}
+	*v = values
+	return nil
+}
+
+// shape is like `Dims` but also works for matrix without rows
+func (t *Matrix) shape() (row, col int) {
+	if len(t.Data) == 0 {
+		return 0, 0
+	}
+	return t.Dims()
+}
+
+// MarshalBinary encodes matrix in versioned binary format, float64 bits are kept exactly
+func (t *Matrix) MarshalBinary() ([]byte, error) {
+	row, col := t.shape()
+	buf := binaryHeader(binaryKindMatrix, 2*binary.MaxVarintLen64+8*row*col)
+	buf = appendUvarint(buf, uint64(row))
+	buf = appendUvarint(buf, uint64(col))
+	for i := range t.Data {
+		if len(t.Data[i]) != col {
+			return nil, fmt.Errorf("Matrix.MarshalBinary: %w (row %d has length %d, expect %d)", ErrDimensionMismatch, i, len(t.Data[i]), col)
+		}
+		buf = appendFloat64s(buf, t.Data[i])
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes matrix from binary format, error wraps ErrFormat
+func (t *Matrix) UnmarshalBinary(data []byte) error {
+	d, err := newBinaryDecoder(data, binaryKindMatrix)
+	if err != nil {
+		return err
+	}
+	row, err := d.uvarint()
+	if err != nil {
+		return err
+	}
+	col, err := d.uvarint()
+	if err != nil {
+		return err
+	}
+	if row < 0 || col < 0 {
+		return offsetError(binaryFormat, int64(d.offset), "invalid shape %d x %d", row, col)
+	}
+	if col == 0 && row > binaryMaxEmptyRows {
+		return offsetError(binaryFormat, int64(d.offset), "%d empty rows exceed limit %d", row, binaryMaxEmptyRows)
+	}
+	if col > 0 && row > (len(data)-d.offset)/8/col {
+		return offsetError(binaryFormat, int64(d.offset), "%d x %d matrix needs more bytes than %d left", row, col, len(data)-d.offset)
+	}
+	values, err := d.floats(row * col)
+	if err != nil {
+		return err
+	}
+	if err = d.end(); err != nil {
+		return err
+	}
+	if col == 0 {
+		// keep row number of empty rows
+		t.Data = make(Data, row)
+		for i := range t.Data {
+			t.Data[i] = Vector{}
+		}
+		return nil
+	}
+	t.Data = NewDense(row, col, values).ToMatrix().Data
+	return nil
+}
+
+// sortedEntries returns entries of sparse matrix in row major order
+func (sm *SparseMatrix) sortedEntries() []Entry {
+	entries := sm.Entries()
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Row != entries[j].Row {
+			return entries[i].Row < entries[j].Row
+		}
+		return entries[i].Col < entries[j].Col
+	})
+	return entries
+}
+
+// MarshalBinary encodes sparse matrix in versioned binary format, float64 bits are kept exactly
+func (sm *SparseMatrix) MarshalBinary() ([]byte, error) {
+	entries := sm.sortedEntries()
+	buf := binaryHeader(binaryKindSparse, 3*binary.MaxVarintLen64+10*len(entries))
+	buf = appendUvarint(buf, uint64(sm.Rows))
+	buf = appendUvarint(buf, uint64(sm.Cols))
+	buf = appendUvarint(buf, uint64(len(entries)))
+	prev := 0
+	var b [8]byte
+	for _, e := range entries {
+		idx := e.Row*sm.Cols + e.Col
+		buf = appendUvarint(buf, uint64(idx-prev))
+		prev = idx
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(e.Value))
+		buf = append(buf, b[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes sparse matrix from binary format, error wraps ErrFormat
+func (sm *SparseMatrix) UnmarshalBinary(data []byte) error {
+	d, err := newBinaryDecoder(data, binaryKindSparse)
+	if err != nil {
+		return err
+	}
+	var dims [3]int
+	for k := range dims {
+		if dims[k], err = d.uvarint(); err != nil {
+			return err
+		}
+	}
+	rows, cols, nnz := dims[0], dims[1], dims[2]
+	if nnz > (len(data)-d.offset)/9 {
+		return offsetError(binaryFormat, int64(d.offset), "%d entries need more bytes than %d left", nnz, len(data)-d.offset)
+	}
+	if cols > 0 && rows > math.MaxInt/cols {
+		return offsetError(binaryFormat, int64(d.offset), "%d x %d sparse matrix overflows linear index", rows, cols)
+	}
+	size := rows * cols
+	nsm := ZeroSparseMatrix(rows, cols)
+	idx := 0
+	for k := 0; k < nnz; k++ {
+		start := d.offset
+		delta, err := d.uvarint()
+		if err != nil {
+			return err
+		}
+		// compare delta with the remaining range so idx never overflows
+		if (k > 0 && delta == 0) || delta >= size-idx {
+			return offsetError(binaryFormat, int64(start), "invalid index delta %d after %d for %d x %d sparse matrix", delta, idx, rows, cols)
+		}
+		idx += delta
+		v, err := d.floats(1)
+		if err != nil {
+			return err
+		}
+		nsm.Set(idx/cols, idx%cols, v[0])
+	}
+	if err = d.end(); err != nil {
+		return err
+	}
+	*sm = *nsm
+	return nil
+}
+
+// jsonFloat is float64 in JSON, non-finite values are strings "NaN", "+Inf" and "-Inf" since JSON numbers can not hold them
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsNaN(v):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(v, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(v, -1):
+		return []byte(`"-Inf"`), nil
+	}
+	return strconv.AppendFloat(nil, v, 'g', -1, 64), nil
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+		switch s {
+		case "NaN", "+Inf", "-Inf", "Inf":
+		default:
+			return fmt.Errorf("json: %w: invalid number %q", ErrFormat, s)
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("json: %w: invalid number %q", ErrFormat, s)
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+// toJSONFloats converts floats for JSON encoding
+func toJSONFloats(v []float64) []jsonFloat {
+	res := make([]jsonFloat, len(v))
+	for k, f := range v {
+		res[k] = jsonFloat(f)
+	}
+	return res
+}
+
+func fromJSONFloats(v []jsonFloat) Vector {
+	res := make(Vector, len(v))
+	for k, f := range v {
+		res[k] = float64(f)
+	}
+	return res
+}
+
+// MarshalJSON encodes vector as JSON array, value receiver like `MarshalBinary`
+func (v Vector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONFloats(v))
+}
+
+// UnmarshalJSON decodes vector from JSON array
+func (v *Vector) UnmarshalJSON(data []byte) error {
+	var fs []jsonFloat
+	if err := json.Unmarshal(data, &fs); err != nil {
+		return err
+	}
+	*v = fromJSONFloats(fs)
+	return nil
+}
+
+// jsonMatrix is JSON layout of matrix: {"shape": [rows, cols], "data": [[...], ...]}
+type jsonMatrix struct {
+	Shape [2]int        `json:"shape"`
+	Data  [][]jsonFloat `json:"data"`
+}
+
+// MarshalJSON encodes matrix as {"shape": [rows, cols], "data": [[...], ...]}
+func (t *Matrix) MarshalJSON() ([]byte, error) {
+	row, col := t.shape()
+	jm := jsonMatrix{Shape: [2]int{row, col}, Data: make([][]jsonFloat, row)}
+	for i := range t.Data {
+		jm.Data[i] = toJSONFloats(t.Data[i])
+	}
+	return json.Marshal(jm)
+}
+
+// UnmarshalJSON decodes matrix from JSON, all rows must match shape
+func (t *Matrix) UnmarshalJSON(data []byte) error {
+	var jm jsonMatrix
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return err
+	}
+	if jm.Shape[0] < 0 || jm.Shape[1] < 0 {
+		return fmt.Errorf("json: %w: invalid shape %v", ErrFormat, jm.Shape)
+	}
+	if len(jm.Data) != jm.Shape[0] {
+		return fmt.Errorf("json: %w: shape has %d rows, data has %d", ErrFormat, jm.Shape[0], len(jm.Data))
+	}
+	// rows are checked before allocation, so the shape is bounded by the decoded data
+	for i, r := range jm.Data {
+		if len(r) != jm.Shape[1] {
+			return fmt.Errorf("json: %w: shape has %d columns, row %d has %d", ErrFormat, jm.Shape[1], i, len(r))
+		}
+	}
+	nt := ZeroMatrix(jm.Shape[0], jm.Shape[1])
+	for i, r := range jm.Data {
+		for j, f := range r {
+			nt.Data[i][j] = float64(f)
+		}
+	}
+	t.Data = nt.Data
+	return nil
+}
+
+// jsonSparseMatrix is JSON layout of sparse matrix in coordinate format like scipy.sparse.coo_matrix
+type jsonSparseMatrix struct {
+	Shape [2]int      `json:"shape"`
+	Row   []int       `json:"row"`
+	Col   []int       `json:"col"`
+	Data  []jsonFloat `json:"data"`
+}
+
+// MarshalJSON encodes sparse matrix as {"shape": [rows, cols], "row": [...], "col": [...], "data": [...]} in row major order
+func (sm *SparseMatrix) MarshalJSON() ([]byte, error) {
+	entries := sm.sortedEntries()
+	js := jsonSparseMatrix{
+		Shape: [2]int{sm.Rows, sm.Cols},
+		Row:   make([]int, len(entries)),
+		Col:   make([]int, len(entries)),
+		Data:  make([]jsonFloat, len(entries)),
+	}
+	for k, e := range entries {
+		js.Row[k], js.Col[k], js.Data[k] = e.Row, e.Col, jsonFloat(e.Value)
+	}
+	return json.Marshal(js)
+}
+
+// UnmarshalJSON decodes sparse matrix from JSON coordinate format, duplicated entries are summed
+func (sm *SparseMatrix) UnmarshalJSON(data []byte) error {
+	var js jsonSparseMatrix
+	if err := json.Unmarshal(data, &js); err != nil {
+		return err
+	}
+	if js.Shape[0] < 0 || js.Shape[1] < 0 || (js.Shape[1] > 0 && js.Shape[0] > math.MaxInt/js.Shape[1]) {
+		return fmt.Errorf("json: %w: invalid shape %v", ErrFormat, js.Shape)
+	}
+	if len(js.Row) != len(js.Data) || len(js.Col) != len(js.Data) {
+		return fmt.Errorf("json: %w: row, col and data have different lengths", ErrFormat)
+	}
+	nsm := ZeroSparseMatrix(js.Shape[0], js.Shape[1])
+	for k, v := range js.Data {
+		i, j := js.Row[k], js.Col[k]
+		if i < 0 || i >= nsm.Rows || j < 0 || j >= nsm.Cols {
+			return fmt.Errorf("json: %w: index (%d, %d) out of %d x %d", ErrFormat, i, j, nsm.Rows, nsm.Cols)
+		}
+		nsm.Set(i, j, nsm.At(i, j)+float64(v))
+	}
+	*sm = *nsm
+	return nil
+}
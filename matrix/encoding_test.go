@@ -0,0 +1,201 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+// bitsEqual checks float64 bits of two vectors, so NaN and -0 are compared exactly
+func bitsEqual(v1, v2 Vector) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+	for i := range v1 {
+		if math.Float64bits(v1[i]) != math.Float64bits(v2[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func specialVector() Vector {
+	return Vector{math.Pi, math.NaN(), math.Inf(1), math.Inf(-1), math.Copysign(0, -1), math.SmallestNonzeroFloat64, math.MaxFloat64}
+}
+
+func TestVectorBinary(t *testing.T) {
+	v := specialVector()
+	data, err := v.MarshalBinary()
+	if err != nil || len(data) != 6+1+8*len(v) {
+		t.Fatal(err)
+	}
+	var res Vector
+	if err = res.UnmarshalBinary(data); err != nil || !bitsEqual(res, v) {
+		t.Fatal(err)
+	}
+	// corrupted input
+	if err = res.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+	if err = res.UnmarshalBinary(append(data, 0)); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+	bad := append([]byte{}, data...)
+	bad[4] = 99
+	if err = res.UnmarshalBinary(bad); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+	var mat Matrix
+	if err = mat.UnmarshalBinary(data); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+}
+
+func TestMatrixBinary(t *testing.T) {
+	mat := GenerateRandomMatrix(5, 7)
+	mat.Data[1] = specialVector()
+	data, err := mat.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res Matrix
+	if err = res.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := range mat.Data {
+		if !bitsEqual(res.Data[i], mat.Data[i]) {
+			t.Fail()
+		}
+	}
+	if err = res.UnmarshalBinary(data[:20]); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+	empty := new(Matrix).Init(Data{{}, {}})
+	data, _ = empty.MarshalBinary()
+	if err = res.UnmarshalBinary(data); err != nil || len(res.Data) != 2 || len(res.Data[0]) != 0 {
+		t.Fail()
+	}
+	// huge row number of zero columns must not allocate
+	huge := appendUvarint(binaryHeader(binaryKindMatrix, 0), math.MaxInt64)
+	if err = res.UnmarshalBinary(append(huge, 0)); !errors.Is(err, ErrFormat) {
+		t.Fatal(err)
+	}
+}
+
+func TestSparseMatrixBinary(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(1000, 1000, 100)
+	data, err := sm.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res SparseMatrix
+	if err = res.UnmarshalBinary(data); err != nil || res.Rows != 1000 || res.Cols != 1000 || len(res.Data) != len(sm.Data) {
+		t.Fatal(err)
+	}
+	for idx, v := range sm.Data {
+		if math.Float64bits(res.Data[idx]) != math.Float64bits(v) {
+			t.Fail()
+		}
+	}
+	// sub sparse matrix view keeps only its own entries
+	sub := sm.GetSubSparseMatrix(10, 20, 100, 200)
+	data, _ = sub.MarshalBinary()
+	if err = res.UnmarshalBinary(data); err != nil || res.Rows != 100 || res.Cols != 200 {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 200; j++ {
+			if res.At(i, j) != sub.At(i, j) {
+				t.Fail()
+			}
+		}
+	}
+	if err = res.UnmarshalBinary(data[:len(data)-3]); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+	// rows * cols and accumulated index overflow int
+	for _, dims := range [][4]uint64{{math.MaxInt64, 4, 1, 0}, {4, 4, 2, 15}, {1 << 31, 1 << 31, 2, math.MaxInt64}} {
+		bad := binaryHeader(binaryKindSparse, 0)
+		for _, v := range dims[:3] {
+			bad = appendUvarint(bad, v)
+		}
+		bad = appendFloat64s(appendUvarint(bad, 1), []float64{1})
+		bad = appendFloat64s(appendUvarint(bad, dims[3]), []float64{2})
+		if err = res.UnmarshalBinary(bad); !errors.Is(err, ErrFormat) {
+			t.Fatal(dims, err)
+		}
+	}
+}
+
+func TestGob(t *testing.T) {
+	type cache struct {
+		Mean      Vector
+		Cov       *Matrix
+		Adjacency *SparseMatrix
+	}
+	in := cache{Mean: specialVector(), Cov: GenerateRandomMatrix(3, 3), Adjacency: GenerateRandomSparseMatrix(10, 10, 20)}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out cache
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !bitsEqual(out.Mean, in.Mean) || !MEqual(out.Cov, in.Cov) || !MEqual(out.Adjacency.ToMatrix(), in.Adjacency.ToMatrix()) {
+		t.Fail()
+	}
+}
+
+func TestJSON(t *testing.T) {
+	v := specialVector()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vRes Vector
+	if err = json.Unmarshal(data, &vRes); err != nil || !bitsEqual(vRes, v) {
+		t.Fatal(string(data), err)
+	}
+
+	mat := new(Matrix).Init(Data{{1, 2.5, math.NaN()}, {-3, 0, 1e300}})
+	data, err = json.Marshal(mat)
+	if err != nil || string(data) != `{"shape":[2,3],"data":[[1,2.5,"NaN"],[-3,0,1e+300]]}` {
+		t.Fatal(string(data), err)
+	}
+	var mRes Matrix
+	if err = json.Unmarshal(data, &mRes); err != nil || !bitsEqual(mRes.Data[0], mat.Data[0]) || !bitsEqual(mRes.Data[1], mat.Data[1]) {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(`{"shape":[2,1],"data":[[1]]}`), &mRes); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+	// shape is checked against data before allocation
+	if err = json.Unmarshal([]byte(`{"shape":[2,4611686018427387904],"data":[[1],[2]]}`), &mRes); !errors.Is(err, ErrFormat) {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(`{"shape":[1,1],"data":[["one"]]}`), &mRes); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+
+	sm := ZeroSparseMatrix(2, 3)
+	sm.Set(1, 2, 4)
+	sm.Set(0, 1, -1)
+	data, err = json.Marshal(sm)
+	if err != nil || string(data) != `{"shape":[2,3],"row":[0,1],"col":[1,2],"data":[-1,4]}` {
+		t.Fatal(string(data), err)
+	}
+	var sRes SparseMatrix
+	if err = json.Unmarshal(data, &sRes); err != nil || !MEqual(sRes.ToMatrix(), sm.ToMatrix()) {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(`{"shape":[4611686018427387904,4],"row":[0],"col":[0],"data":[1]}`), &sRes); !errors.Is(err, ErrFormat) {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(`{"shape":[1,1],"row":[1],"col":[0],"data":[1]}`), &sRes); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+}
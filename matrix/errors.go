@@ -0,0 +1,50 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by `Try*` functions, they are wrapped with operation details and can be checked by `errors.Is`
+var (
+	ErrDimensionMismatch   = errors.New("matrix: dimension mismatch")
+	ErrNotSquare           = errors.New("matrix: not a square matrix")
+	ErrSingular            = errors.New("matrix: singular matrix")
+	ErrNotPositiveDefinite = errors.New("matrix: not positive definite")
+	ErrZeroVector          = errors.New("matrix: zero vector")
+	ErrBreakdown           = errors.New("matrix: iterative method breakdown")
+	ErrFormat              = errors.New("matrix: malformed input")
+	ErrDomain              = errors.New("matrix: argument out of function domain")
+)
+
+// dimsError wraps ErrDimensionMismatch with operation name and dims of both operands
+func dimsError(op string, r1, c1, r2, c2 int) error {
+	return fmt.Errorf("%s: %w (%d x %d vs %d x %d)", op, ErrDimensionMismatch, r1, c1, r2, c2)
+}
+
+// lenError wraps ErrDimensionMismatch with operation name and lengths of both vectors
+func lenError(op string, l1, l2 int) error {
+	return fmt.Errorf("%s: %w (length %d vs %d)", op, ErrDimensionMismatch, l1, l2)
+}
+
+// squareError wraps ErrNotSquare with operation name and dims
+func squareError(op string, r, c int) error {
+	return fmt.Errorf("%s: %w (%d x %d)", op, ErrNotSquare, r, c)
+}
+
+// lineError wraps ErrFormat with file format name and 1-based line number of text input
+func lineError(format string, line int, msg string, args ...interface{}) error {
+	return fmt.Errorf("%s: %w: line %d: %s", format, ErrFormat, line, fmt.Sprintf(msg, args...))
+}
+
+// offsetError wraps ErrFormat with file format name and byte offset of binary input
+func offsetError(format string, offset int64, msg string, args ...interface{}) error {
+	return fmt.Errorf("%s: %w: offset %d: %s", format, ErrFormat, offset, fmt.Sprintf(msg, args...))
+}
+
+// must panics with err if it is not nil, it turns `Try*` functions into the panicking ones
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
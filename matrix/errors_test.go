@@ -0,0 +1,119 @@
+package matrix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTryMatrixOperations(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 2, 3}, {4, 5, 6}})
+	b := new(Matrix).Init(Data{{1, 2}, {3, 4}})
+	if _, err := a.TryAdd(b); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := a.TrySub(b); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := a.TryMul(b); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := a.TryMulVec(&Vector{1, 2}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := a.TryConcatenate(b, 0); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := a.TryInverse(); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, err := a.TryDet(); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, err := a.TryTrace(); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	singular := new(Matrix).Init(Data{{0, 1}, {0, 2}})
+	if _, err := singular.TryInverse(); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if nt, err := b.TryMul(b); err != nil || !MEqual(nt, b.Mul(b)) {
+		t.Fail()
+	}
+	if inv, err := b.TryInverse(); err != nil || !MEqual(inv, b.Inverse()) {
+		t.Fail()
+	}
+	if det, err := b.TryDet(); err != nil || !FloatEqual(det, -2) {
+		t.Fail()
+	}
+}
+
+func TestTryDecompositions(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 2, 3}, {4, 5, 6}})
+	if _, _, err := TryLUPDecompose(a, 2, EPS); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, _, err := TryLUPDecompose(new(Matrix).Init(Data{{0, 0}, {0, 0}}), 2, EPS); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if _, err := TryCholeskyDecomposition(a); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, err := TryCholeskyDecomposition(new(Matrix).Init(Data{{1, 2}, {2, 1}})); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+	spd := new(Matrix).Init(Data{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}})
+	if L, err := TryCholeskyDecomposition(spd); err != nil || !MEqual(L, CholeskyDecomposition(spd)) {
+		t.Fail()
+	}
+	if _, _, err := TryEigenDecompose(a); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, _, _, err := TrySVD(a); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
+func TestTryVectorOperations(t *testing.T) {
+	v, v1 := &Vector{1, 2, 3}, &Vector{1, 2}
+	if _, err := v.TryAdd(v1); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := v.TrySub(v1); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := v.TryDot(v1); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := v1.TryCross(v1); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := (&Vector{0, 0}).TryNormalize(); !errors.Is(err, ErrZeroVector) {
+		t.Fail()
+	}
+	if c, err := v.TryCross(&Vector{4, 5, 6}); err != nil || !VEqual(c, &Vector{-3, 6, -3}) {
+		t.Fail()
+	}
+}
+
+func TestTrySparseMatrixOperations(t *testing.T) {
+	sm1, sm2 := ZeroSparseMatrix(2, 3), ZeroSparseMatrix(2, 2)
+	if _, err := sm1.TryAdd(sm2); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := sm1.TryMul(sm2); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err := sm1.TryMulVec(&Vector{1, 2}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
+func TestPanicWrappers(t *testing.T) {
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, ErrDimensionMismatch) {
+			t.Fail()
+		}
+	}()
+	new(Matrix).Init(Data{{1, 2}}).Add(new(Matrix).Init(Data{{1}}))
+}
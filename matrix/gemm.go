@@ -0,0 +1,129 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+)
+
+// MulConfig tunes dense matrix multiplication used by `Mul`
+//	Workers: max goroutines in parallel mode, <= 0 means runtime.NumCPU()
+//	Threshold: parallel mode is used when M * N * K of the product reaches it
+//	BlockSize: edge length of cache tiles, <= 0 means default (64)
+type MulConfig struct {
+	Workers   int
+	Threshold int
+	BlockSize int
+}
+
+const defaultMulBlockSize = 64
+
+var (
+	mulConfigMu sync.RWMutex
+	mulConfig   = MulConfig{
+		Workers:   runtime.NumCPU(),
+		Threshold: 64 * 64 * 64,
+		BlockSize: defaultMulBlockSize,
+	}
+)
+
+// SetMulConfig sets package-level configuration of matrix multiplication
+func SetMulConfig(c MulConfig) {
+	mulConfigMu.Lock()
+	mulConfig = c
+	mulConfigMu.Unlock()
+}
+
+// GetMulConfig returns current package-level configuration of matrix multiplication
+func GetMulConfig() MulConfig {
+	mulConfigMu.RLock()
+	defer mulConfigMu.RUnlock()
+	return mulConfig
+}
+
+// gemm computes out = a * b with cache tiling
+//	right operand is packed into column panels of `BlockSize` width, so the inner loop runs over contiguous memory,
+//	output is partitioned into (row block, column panel) tiles, each tile is owned by exactly one goroutine,
+//	so there is no shared accumulator between goroutines
+func gemm(a, b, out *Matrix) {
+	m, k := a.Dims()
+	_, n := b.Dims()
+	if m == 0 || n == 0 || k == 0 {
+		return
+	}
+	c := GetMulConfig()
+	bs := c.BlockSize
+	if bs <= 0 {
+		bs = defaultMulBlockSize
+	}
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	panels := packPanels(b, k, n, bs)
+	rowBlocks := (m + bs - 1) / bs
+	tiles := rowBlocks * len(panels)
+
+	if workers == 1 || tiles == 1 || m*n*k < c.Threshold {
+		for t := 0; t < tiles; t++ {
+			gemmTile(a, out, panels, t/len(panels), t%len(panels), m, k, bs)
+		}
+		return
+	}
+
+	workers = MinInt(workers, tiles)
+	jobs := make(chan int, tiles)
+	for t := 0; t < tiles; t++ {
+		jobs <- t
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				gemmTile(a, out, panels, t/len(panels), t%len(panels), m, k, bs)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// packPanels copies b (k x n) into column panels, panel p holds columns [p*bs, p*bs+width) row-wise and contiguous
+func packPanels(b *Matrix, k, n, bs int) [][]float64 {
+	panels := make([][]float64, (n+bs-1)/bs)
+	for p := range panels {
+		j0 := p * bs
+		width := MinInt(bs, n-j0)
+		panel := make([]float64, k*width)
+		for r := 0; r < k; r++ {
+			copy(panel[r*width:(r+1)*width], b.Data[r][j0:j0+width])
+		}
+		panels[p] = panel
+	}
+	return panels
+}
+
+// gemmTile accumulates the output tile of row block rb and column panel p
+func gemmTile(a, out *Matrix, panels [][]float64, rb, p, m, k, bs int) {
+	i0, i1 := rb*bs, MinInt(rb*bs+bs, m)
+	j0 := p * bs
+	panel := panels[p]
+	width := len(panel) / k
+	for k0 := 0; k0 < k; k0 += bs {
+		k1 := MinInt(k0+bs, k)
+		for i := i0; i < i1; i++ {
+			ai := a.Data[i]
+			oi := out.Data[i][j0 : j0+width]
+			for l := k0; l < k1; l++ {
+				ail := ai[l]
+				bl := panel[l*width : (l+1)*width]
+				for j, v := range bl {
+					oi[j] += ail * v
+				}
+			}
+		}
+	}
+}
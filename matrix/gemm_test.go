@@ -0,0 +1,94 @@
+package matrix
+
+import (
+	"strconv"
+	"testing"
+)
+
+// naiveMul is the former sequential implementation of `Mul`, kept as reference for tests and benchmarks
+//	(the former parallel branch shared its accumulator between goroutines and is not reproduced here)
+func naiveMul(t, mat2 *Matrix) *Matrix {
+	row1, _ := t.Dims()
+	row2, col2 := mat2.Dims()
+	out := ZeroMatrix(row1, col2)
+	for i := 0; i < row1; i++ {
+		for j := 0; j < col2; j++ {
+			for k := 0; k < row2; k++ {
+				out.Set(i, j, out.At(i, j)+t.At(i, k)*mat2.At(k, j))
+			}
+		}
+	}
+	return out
+}
+
+func TestSetMulConfig(t *testing.T) {
+	old := GetMulConfig()
+	defer SetMulConfig(old)
+	c := MulConfig{Workers: 3, Threshold: 1, BlockSize: 8}
+	SetMulConfig(c)
+	if GetMulConfig() != c {
+		t.Fail()
+	}
+}
+
+func TestMatrix_MulShapes(t *testing.T) {
+	old := GetMulConfig()
+	defer SetMulConfig(old)
+	shapes := [][3]int{{1, 1, 1}, {3, 5, 2}, {90, 7, 90}, {200, 16, 8}, {8, 300, 8}, {130, 130, 130}, {16, 16, 257}}
+	configs := []MulConfig{
+		{Workers: 1, Threshold: 0, BlockSize: 0},
+		{Workers: 4, Threshold: 1, BlockSize: 16},
+		{Workers: 0, Threshold: 1, BlockSize: 7},
+	}
+	for _, c := range configs {
+		SetMulConfig(c)
+		for _, s := range shapes {
+			a := GenerateRandomMatrix(s[0], s[1])
+			b := GenerateRandomMatrix(s[1], s[2])
+			if !MEqual(a.Mul(b), naiveMul(a, b)) {
+				t.Errorf("mismatch for shape %v with config %+v", s, c)
+			}
+		}
+	}
+}
+
+func benchmarkMulShapes(b *testing.B, mul func(x, y *Matrix) *Matrix) {
+	shapes := []struct {
+		name    string
+		m, k, n int
+	}{
+		{"square", 100, 100, 100},
+		{"square", 300, 300, 300},
+		{"tall-skinny", 4000, 16, 16},
+		{"wide", 32, 4096, 32},
+	}
+	for _, s := range shapes {
+		b.Run(s.name+"-"+strconv.Itoa(s.m)+"x"+strconv.Itoa(s.k)+"x"+strconv.Itoa(s.n), func(b *testing.B) {
+			x := GenerateRandomMatrix(s.m, s.k)
+			y := GenerateRandomMatrix(s.k, s.n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				mul(x, y)
+			}
+		})
+	}
+}
+
+/*
+Intel(R) Xeon(R) Processor, 1 thread
+BenchmarkMulShapes/square-100x100x100                	      20	   1126419 ns/op
+BenchmarkMulShapes/square-300x300x300                	      20	  31512246 ns/op
+BenchmarkMulShapes/tall-skinny-4000x16x16            	      20	   1448875 ns/op
+BenchmarkMulShapes/wide-32x4096x32                   	      20	   4504479 ns/op
+BenchmarkNaiveMulShapes/square-100x100x100           	      20	   3533847 ns/op
+BenchmarkNaiveMulShapes/square-300x300x300           	      20	 117558518 ns/op
+BenchmarkNaiveMulShapes/tall-skinny-4000x16x16       	      20	   3444087 ns/op
+BenchmarkNaiveMulShapes/wide-32x4096x32              	      20	  16240516 ns/op
+*/
+func BenchmarkMulShapes(b *testing.B) {
+	benchmarkMulShapes(b, func(x, y *Matrix) *Matrix { return x.Mul(y) })
+}
+
+func BenchmarkNaiveMulShapes(b *testing.B) {
+	benchmarkMulShapes(b, naiveMul)
+}
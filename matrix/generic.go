@@ -0,0 +1,466 @@
+package matrix
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Scalar is element type of generic vector `VectorOf` and matrix `DenseOf`
+//	float32 halves memory of large data (e.g. point clouds), complex128 is for signal processing,
+//	float64 stays the default: `Vector`, `Matrix` and `Dense` with all decompositions work on it
+type Scalar interface {
+	float32 | float64 | complex128
+}
+
+// VectorOf is generic dense vector
+//	VectorOf[float64] and `Vector` have the same underlying type, so they convert to each other without copy:
+//	VectorOf[float64](v), Vector(g)
+type VectorOf[T Scalar] []T
+
+// DenseOf is generic dense matrix of contiguous row-major storage, element (i, j) locates at Data[i*Cols+j]
+type DenseOf[T Scalar] struct {
+	Rows, Cols int
+	Data       []T
+}
+
+// isComplex reports whether T is complex128
+func isComplex[T Scalar]() bool {
+	var zero T
+	_, ok := any(zero).(complex128)
+	return ok
+}
+
+// absOf returns absolute value (modulus for complex) of x
+func absOf[T Scalar](x T) float64 {
+	switch v := any(x).(type) {
+	case float32:
+		return math.Abs(float64(v))
+	case float64:
+		return math.Abs(v)
+	default:
+		return cmplx.Abs(any(x).(complex128))
+	}
+}
+
+// conjOf returns complex conjugate of x, real x is returned as is
+func conjOf[T Scalar](x T) T {
+	if c, ok := any(x).(complex128); ok {
+		return any(cmplx.Conj(c)).(T)
+	}
+	return x
+}
+
+// convertScalar converts x of type T to type U, imaginary part is dropped when U is real
+func convertScalar[T, U Scalar](x T) U {
+	var c complex128
+	switch v := any(x).(type) {
+	case float32:
+		c = complex(float64(v), 0)
+	case float64:
+		c = complex(v, 0)
+	default:
+		c = any(x).(complex128)
+	}
+	var zero U
+	switch any(zero).(type) {
+	case float32:
+		return any(float32(real(c))).(U)
+	case float64:
+		return any(real(c)).(U)
+	default:
+		return any(c).(U)
+	}
+}
+
+// NewVectorOf converts float64 vector into generic vector of element type T (copy)
+func NewVectorOf[T Scalar](v *Vector) VectorOf[T] {
+	res := make(VectorOf[T], len(*v))
+	for i, x := range *v {
+		res[i] = convertScalar[float64, T](x)
+	}
+	return res
+}
+
+// ConvertVector converts generic vector into element type U (copy), e.g. float32 to float64 or real to complex
+//	imaginary parts are dropped when U is real
+func ConvertVector[T, U Scalar](v VectorOf[T]) VectorOf[U] {
+	res := make(VectorOf[U], len(v))
+	for i, x := range v {
+		res[i] = convertScalar[T, U](x)
+	}
+	return res
+}
+
+// ToVector converts generic vector into float64 `Vector` (copy), imaginary parts are dropped
+func (v VectorOf[T]) ToVector() *Vector {
+	res := make(Vector, len(v))
+	for i, x := range v {
+		res[i] = convertScalar[T, float64](x)
+	}
+	return &res
+}
+
+// Length returns length of vector
+func (v VectorOf[T]) Length() int {
+	return len(v)
+}
+
+// At returns element at i
+func (v VectorOf[T]) At(i int) T {
+	return v[i]
+}
+
+// Set sets element at i
+func (v VectorOf[T]) Set(i int, value T) {
+	v[i] = value
+}
+
+// Copy returns a copy of vector
+func (v VectorOf[T]) Copy() VectorOf[T] {
+	return append(VectorOf[T]{}, v...)
+}
+
+// Add returns v + v2
+func (v VectorOf[T]) Add(v2 VectorOf[T]) VectorOf[T] {
+	if len(v) != len(v2) {
+		panic(lenError("VectorOf.Add", len(v), len(v2)))
+	}
+	res := make(VectorOf[T], len(v))
+	for i, x := range v {
+		res[i] = x + v2[i]
+	}
+	return res
+}
+
+// Sub returns v - v2
+func (v VectorOf[T]) Sub(v2 VectorOf[T]) VectorOf[T] {
+	if len(v) != len(v2) {
+		panic(lenError("VectorOf.Sub", len(v), len(v2)))
+	}
+	res := make(VectorOf[T], len(v))
+	for i, x := range v {
+		res[i] = x - v2[i]
+	}
+	return res
+}
+
+// AddNum returns v + n element-wise
+func (v VectorOf[T]) AddNum(n T) VectorOf[T] {
+	res := make(VectorOf[T], len(v))
+	for i, x := range v {
+		res[i] = x + n
+	}
+	return res
+}
+
+// MulNum returns v * n element-wise
+func (v VectorOf[T]) MulNum(n T) VectorOf[T] {
+	res := make(VectorOf[T], len(v))
+	for i, x := range v {
+		res[i] = x * n
+	}
+	return res
+}
+
+// Dot returns inner product Σ conj(v[i]) * v2[i], for real types it is the usual dot product
+func (v VectorOf[T]) Dot(v2 VectorOf[T]) T {
+	if len(v) != len(v2) {
+		panic(lenError("VectorOf.Dot", len(v), len(v2)))
+	}
+	var sum T
+	if isComplex[T]() {
+		for i, x := range v {
+			sum += conjOf(x) * v2[i]
+		}
+		return sum
+	}
+	for i, x := range v {
+		sum += x * v2[i]
+	}
+	return sum
+}
+
+// Sum returns sum of all elements
+func (v VectorOf[T]) Sum() T {
+	var sum T
+	for _, x := range v {
+		sum += x
+	}
+	return sum
+}
+
+// Norm returns 2-norm of vector, it is accumulated in float64 with scaling, so float32 vectors do not overflow
+func (v VectorOf[T]) Norm() float64 {
+	scale, ssq := 0., 1.
+	for _, x := range v {
+		a := absOf(x)
+		if a == 0 {
+			continue
+		}
+		if scale < a {
+			ssq = 1 + ssq*(scale/a)*(scale/a)
+			scale = a
+		} else {
+			ssq += (a / scale) * (a / scale)
+		}
+	}
+	return scale * math.Sqrt(ssq)
+}
+
+// Norm1 returns 1-norm (sum of absolute values) of vector
+func (v VectorOf[T]) Norm1() float64 {
+	sum := 0.
+	for _, x := range v {
+		sum += absOf(x)
+	}
+	return sum
+}
+
+// NormInf returns ∞-norm (maximum absolute value) of vector
+func (v VectorOf[T]) NormInf() float64 {
+	norm := 0.
+	for _, x := range v {
+		norm = math.Max(norm, absOf(x))
+	}
+	return norm
+}
+
+// NewDenseOf generates a new generic dense matrix with input row-major data and dims
+//	notice: data is used as backend directly without copy, nil data means a zero matrix
+func NewDenseOf[T Scalar](rows, cols int, data []T) *DenseOf[T] {
+	if rows < 0 || cols < 0 {
+		panic("negative dimension")
+	}
+	if data == nil {
+		data = make([]T, rows*cols)
+	}
+	if len(data) != rows*cols {
+		panic(dimsError("NewDenseOf", rows, cols, len(data), 1))
+	}
+	return &DenseOf[T]{Rows: rows, Cols: cols, Data: data}
+}
+
+// IdentityDenseOf generates n x n generic identity matrix
+func IdentityDenseOf[T Scalar](n int) *DenseOf[T] {
+	d := NewDenseOf[T](n, n, nil)
+	for i := 0; i < n; i++ {
+		d.Data[i*n+i] = 1
+	}
+	return d
+}
+
+// NewDenseOfMatrix converts float64 matrix into generic dense matrix of element type T (copy)
+func NewDenseOfMatrix[T Scalar](t *Matrix) *DenseOf[T] {
+	row, col := t.Dims()
+	d := NewDenseOf[T](row, col, nil)
+	for i := range t.Data {
+		for j, x := range t.Data[i] {
+			d.Data[i*col+j] = convertScalar[float64, T](x)
+		}
+	}
+	return d
+}
+
+// ConvertDense converts generic dense matrix into element type U (copy), imaginary parts are dropped when U is real
+func ConvertDense[T, U Scalar](d *DenseOf[T]) *DenseOf[U] {
+	return NewDenseOf(d.Rows, d.Cols, []U(ConvertVector[T, U](d.Data)))
+}
+
+// ToMatrix converts generic dense matrix into float64 `Matrix` (copy), imaginary parts are dropped
+func (d *DenseOf[T]) ToMatrix() *Matrix {
+	t := ZeroMatrix(d.Rows, d.Cols)
+	for i := range t.Data {
+		for j := range t.Data[i] {
+			t.Data[i][j] = convertScalar[T, float64](d.Data[i*d.Cols+j])
+		}
+	}
+	return t
+}
+
+// NewDenseOfComplex converts complex matrix into generic dense matrix of element type T (copy)
+func NewDenseOfComplex[T Scalar](cm *ComplexMatrix) *DenseOf[T] {
+	row, col := cm.Dims()
+	d := NewDenseOf[T](row, col, nil)
+	for i := range cm.Data {
+		for j, x := range cm.Data[i] {
+			d.Data[i*col+j] = convertScalar[complex128, T](x)
+		}
+	}
+	return d
+}
+
+// ToComplexMatrix converts generic dense matrix into `ComplexMatrix` (copy)
+func (d *DenseOf[T]) ToComplexMatrix() *ComplexMatrix {
+	cm := ZeroComplexMatrix(d.Rows, d.Cols)
+	for i := range cm.Data {
+		for j := range cm.Data[i] {
+			cm.Data[i][j] = convertScalar[T, complex128](d.Data[i*d.Cols+j])
+		}
+	}
+	return cm
+}
+
+// Dims returns numbers of rows and columns
+func (d *DenseOf[T]) Dims() (row, col int) {
+	return d.Rows, d.Cols
+}
+
+// At returns element at row i, column j
+func (d *DenseOf[T]) At(i, j int) T {
+	if i < 0 || i >= d.Rows || j < 0 || j >= d.Cols {
+		panic("index out of range")
+	}
+	return d.Data[i*d.Cols+j]
+}
+
+// Set sets element at row i, column j
+func (d *DenseOf[T]) Set(i, j int, value T) {
+	if i < 0 || i >= d.Rows || j < 0 || j >= d.Cols {
+		panic("index out of range")
+	}
+	d.Data[i*d.Cols+j] = value
+}
+
+// Row returns i-th row, it is a view sharing storage with the matrix
+func (d *DenseOf[T]) Row(i int) VectorOf[T] {
+	if i < 0 || i >= d.Rows {
+		panic("row index out of range")
+	}
+	s := i * d.Cols
+	return d.Data[s : s+d.Cols : s+d.Cols]
+}
+
+// Col returns a copy of j-th column
+func (d *DenseOf[T]) Col(j int) VectorOf[T] {
+	if j < 0 || j >= d.Cols {
+		panic("column index out of range")
+	}
+	v := make(VectorOf[T], d.Rows)
+	for i := range v {
+		v[i] = d.Data[i*d.Cols+j]
+	}
+	return v
+}
+
+// Copy returns a deep copy
+func (d *DenseOf[T]) Copy() *DenseOf[T] {
+	return NewDenseOf(d.Rows, d.Cols, append([]T{}, d.Data...))
+}
+
+// T returns transpose (without conjugation)
+func (d *DenseOf[T]) T() *DenseOf[T] {
+	nd := NewDenseOf[T](d.Cols, d.Rows, nil)
+	for i := 0; i < d.Rows; i++ {
+		for j, v := range d.Data[i*d.Cols : (i+1)*d.Cols] {
+			nd.Data[j*d.Rows+i] = v
+		}
+	}
+	return nd
+}
+
+// H returns conjugate transpose, it equals `T` for real types
+func (d *DenseOf[T]) H() *DenseOf[T] {
+	nd := d.T()
+	if isComplex[T]() {
+		for i, v := range nd.Data {
+			nd.Data[i] = conjOf(v)
+		}
+	}
+	return nd
+}
+
+// Add returns d + d2
+func (d *DenseOf[T]) Add(d2 *DenseOf[T]) *DenseOf[T] {
+	if d.Rows != d2.Rows || d.Cols != d2.Cols {
+		panic(dimsError("DenseOf.Add", d.Rows, d.Cols, d2.Rows, d2.Cols))
+	}
+	return NewDenseOf(d.Rows, d.Cols, []T(VectorOf[T](d.Data).Add(d2.Data)))
+}
+
+// Sub returns d - d2
+func (d *DenseOf[T]) Sub(d2 *DenseOf[T]) *DenseOf[T] {
+	if d.Rows != d2.Rows || d.Cols != d2.Cols {
+		panic(dimsError("DenseOf.Sub", d.Rows, d.Cols, d2.Rows, d2.Cols))
+	}
+	return NewDenseOf(d.Rows, d.Cols, []T(VectorOf[T](d.Data).Sub(d2.Data)))
+}
+
+// MulNum returns d * n element-wise
+func (d *DenseOf[T]) MulNum(n T) *DenseOf[T] {
+	return NewDenseOf(d.Rows, d.Cols, []T(VectorOf[T](d.Data).MulNum(n)))
+}
+
+// Mul returns matrix product d * d2
+func (d *DenseOf[T]) Mul(d2 *DenseOf[T]) *DenseOf[T] {
+	if d.Cols != d2.Rows {
+		panic(dimsError("DenseOf.Mul", d.Rows, d.Cols, d2.Rows, d2.Cols))
+	}
+	nd := NewDenseOf[T](d.Rows, d2.Cols, nil)
+	for i := 0; i < d.Rows; i++ {
+		out := nd.Data[i*nd.Cols : (i+1)*nd.Cols]
+		for k, a := range d.Data[i*d.Cols : (i+1)*d.Cols] {
+			if a == 0 {
+				continue
+			}
+			for j, b := range d2.Data[k*d2.Cols : (k+1)*d2.Cols] {
+				out[j] += a * b
+			}
+		}
+	}
+	return nd
+}
+
+// MulVec returns matrix vector product d * v
+func (d *DenseOf[T]) MulVec(v VectorOf[T]) VectorOf[T] {
+	if d.Cols != len(v) {
+		panic(dimsError("DenseOf.MulVec", d.Rows, d.Cols, len(v), 1))
+	}
+	res := make(VectorOf[T], d.Rows)
+	for i := range res {
+		var sum T
+		for j, a := range d.Data[i*d.Cols : (i+1)*d.Cols] {
+			sum += a * v[j]
+		}
+		res[i] = sum
+	}
+	return res
+}
+
+// Trace returns sum of diagonal elements
+func (d *DenseOf[T]) Trace() T {
+	if d.Rows != d.Cols {
+		panic(squareError("DenseOf.Trace", d.Rows, d.Cols))
+	}
+	var sum T
+	for i := 0; i < d.Rows; i++ {
+		sum += d.Data[i*d.Cols+i]
+	}
+	return sum
+}
+
+// Norm returns Frobenius norm
+func (d *DenseOf[T]) Norm() float64 {
+	return VectorOf[T](d.Data).Norm()
+}
+
+// Norm1 returns 1-norm (maximum absolute column sum)
+func (d *DenseOf[T]) Norm1() float64 {
+	sums := make([]float64, d.Cols)
+	for i, v := range d.Data {
+		sums[i%d.Cols] += absOf(v)
+	}
+	norm := 0.
+	for _, s := range sums {
+		norm = math.Max(norm, s)
+	}
+	return norm
+}
+
+// NormInf returns ∞-norm (maximum absolute row sum)
+func (d *DenseOf[T]) NormInf() float64 {
+	norm := 0.
+	for i := 0; i < d.Rows; i++ {
+		norm = math.Max(norm, d.Row(i).Norm1())
+	}
+	return norm
+}
@@ -0,0 +1,96 @@
+package matrix
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestVectorOf(t *testing.T) {
+	v := Vector{3, -4, 0}
+	g := VectorOf[float64](v)
+	if g.Norm() != 5 || g.Norm1() != 7 || g.NormInf() != 4 || g.Sum() != -1 {
+		t.Fail()
+	}
+	// float64 instantiation shares storage with Vector
+	g.Set(2, 1)
+	if sum := Vector(g.Add(g)); v[2] != 1 || !VEqual(&sum, v.Add(&v)) || g.Dot(g) != v.Dot(&v) {
+		t.Fail()
+	}
+
+	f := NewVectorOf[float32](&v)
+	if !FloatEqual(f.Norm(), math.Sqrt(26)) || f.MulNum(2).At(1) != -8 || f.AddNum(1).Sub(f).Sum() != 3 {
+		t.Fail()
+	}
+	// float32 norm is accumulated without overflow
+	if big := (VectorOf[float32]{3e30, 4e30}); math.Abs(big.Norm()-5e30) > 1e24 {
+		t.Fatal(big.Norm())
+	}
+	if !VEqual(ConvertVector[float32, float64](f).ToVector(), &v) || !VEqual(f.ToVector(), &v) {
+		t.Fail()
+	}
+
+	c := VectorOf[complex128]{1 + 1i, 2i}
+	// Dot conjugates the first operand, so v·v is real
+	if c.Dot(c) != 6 || c.Norm() != math.Sqrt(6) || c.NormInf() != 2 {
+		t.Fail()
+	}
+	if r := ConvertVector[complex128, float64](c); r[0] != 1 || r[1] != 0 {
+		t.Fail()
+	}
+}
+
+func TestDenseOf(t *testing.T) {
+	a := GenerateRandomMatrix(4, 3)
+	b := GenerateRandomMatrix(3, 5)
+	da, db := NewDenseOfMatrix[float64](a), NewDenseOfMatrix[float64](b)
+	if !MEqual(da.Mul(db).ToMatrix(), a.Mul(b)) || !MEqual(da.T().ToMatrix(), a.T()) {
+		t.Fail()
+	}
+	if !MEqual(da.Add(da).ToMatrix(), a.Add(a)) || !MEqual(da.Sub(da.MulNum(2)).ToMatrix(), a.MulNum(-1)) {
+		t.Fail()
+	}
+	if !FloatEqual(da.Norm(), a.Norm()) || !FloatEqual(da.Norm1(), a.Norm1()) || !FloatEqual(da.NormInf(), a.NormInf()) {
+		t.Fail()
+	}
+	v := GenerateRandomVector(3)
+	if mv := Vector(da.MulVec(VectorOf[float64](*v))); !VEqual(&mv, a.MulVec(v)) {
+		t.Fail()
+	}
+	// Row is a view, Col is a copy
+	da.Row(1).Set(0, 7)
+	da.Col(2).Set(0, 7)
+	if da.At(1, 0) != 7 || da.At(0, 2) == 7 {
+		t.Fail()
+	}
+
+	// float32 results agree with float64 up to single precision
+	fa, fb := ConvertDense[float64, float32](db.T()), ConvertDense[float64, float32](db)
+	if d := fa.Mul(fb).ToMatrix().Sub(b.T().Mul(b)).NormInf(); d > 1e-5 {
+		t.Fatal(d)
+	}
+	if IdentityDenseOf[float32](3).Trace() != 3 {
+		t.Fail()
+	}
+
+	re := new(Matrix).Init(Data{{1, 2}, {3, 4}})
+	im := new(Matrix).Init(Data{{0, -1}, {1, 0}})
+	cm := NewComplexMatrix(re, im)
+	dc := NewDenseOfComplex[complex128](cm)
+	if !CEqual(dc.Mul(dc).ToComplexMatrix(), cm.Mul(cm)) || !CEqual(dc.H().ToComplexMatrix(), cm.H()) {
+		t.Fail()
+	}
+	if !CEqual(dc.T().ToComplexMatrix(), cm.T()) || !MEqual(dc.ToMatrix(), re) {
+		t.Fail()
+	}
+	if dc.Trace() != 5 || !FloatEqual(dc.NormInf(), cmplx.Abs(3+1i)+4) || !FloatEqual(dc.Norm(), math.Sqrt(32)) {
+		t.Fail()
+	}
+}
+
+func BenchmarkDenseOf_Mul(b *testing.B) {
+	a := ConvertDense[float64, float32](NewDenseOfMatrix[float64](GenerateRandomSquareMatrix(100)))
+	for i := 0; i < b.N; i++ {
+		a.Mul(a)
+	}
+}
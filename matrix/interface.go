@@ -0,0 +1,129 @@
+package matrix
+
+// Interface is the basic matrix interface, `Matrix`, `Dense`, `SparseMatrix`, `CSR`, `CSC` and structured matrices
+// (`DiagonalMatrix`, `TriangularMatrix`, `SymmetricMatrix`, `BandMatrix`) all satisfy it,
+// so functions only reading elements or rows can accept any of them
+type Interface interface {
+	// dimensions
+	Dims() (row, col int)
+
+	// value at index(row i, col j), panic if not access
+	At(i, j int) float64
+
+	// set value at index(row i, col j), panic if not access
+	Set(i, j int, value float64)
+
+	// get row, it may be a view or a copy depending on the underlying storage
+	Row(i int) *Vector
+
+	// get column, it may be a view or a copy depending on the underlying storage
+	Col(j int) *Vector
+}
+
+var (
+	_ Interface = (*Matrix)(nil)
+	_ Interface = (*Dense)(nil)
+	_ Interface = (*SparseMatrix)(nil)
+	_ Interface = (*CSR)(nil)
+	_ Interface = (*CSC)(nil)
+	_ Interface = (*DiagonalMatrix)(nil)
+	_ Interface = (*TriangularMatrix)(nil)
+	_ Interface = (*SymmetricMatrix)(nil)
+	_ Interface = (*BandMatrix)(nil)
+)
+
+// AsMatrix returns input as *Matrix, it is returned directly if it is already a *Matrix, otherwise copied row by row
+func AsMatrix(m Interface) *Matrix {
+	switch t := m.(type) {
+	case *Matrix:
+		return t
+	case *Dense:
+		return t.ToMatrix()
+	case *SparseMatrix:
+		return t.ToMatrix()
+	case *CSR:
+		return t.ToMatrix()
+	case *CSC:
+		return t.ToMatrix()
+	case *DiagonalMatrix:
+		return t.ToMatrix()
+	case *TriangularMatrix:
+		return t.ToMatrix()
+	case *SymmetricMatrix:
+		return t.ToMatrix()
+	case *BandMatrix:
+		return t.ToMatrix()
+	}
+	row, col := m.Dims()
+	nt := ZeroMatrix(row, col)
+	for i := range nt.Data {
+		copy(nt.Data[i], *m.Row(i))
+	}
+	return nt
+}
+
+// MeanOf returns mean vector of all rows (like `Matrix.Mean(0)`) through `Row` only
+func MeanOf(m Interface) *Vector {
+	row, col := m.Dims()
+	mean := make(Vector, col)
+	for i := 0; i < row; i++ {
+		for j, v := range *m.Row(i) {
+			mean[j] += v
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(row)
+	}
+	return &mean
+}
+
+// CovMatrixOf returns covariance matrix (same normalization as `Matrix.CovMatrix`) through `Row` only,
+// so it does not need to densify sparse input
+//	weights: optional (nil for none), each row is multiplied by its weight before calculation
+func CovMatrixOf(m Interface, weights *Vector) *Matrix {
+	row, col := m.Dims()
+	if weights == nil {
+		if t, ok := m.(*Matrix); ok {
+			return t.CovMatrix()
+		}
+	} else if weights.Length() != row {
+		panic(lenError("CovMatrixOf", row, weights.Length()))
+	}
+	getRow := func(i int) Vector {
+		r := *m.Row(i)
+		if weights == nil {
+			return r
+		}
+		return *r.MulNum(weights.At(i))
+	}
+	mean := make(Vector, col)
+	for i := 0; i < row; i++ {
+		for j, v := range getRow(i) {
+			mean[j] += v
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(row)
+	}
+	cov := ZeroMatrix(col, col)
+	x := make(Vector, col)
+	for i := 0; i < row; i++ {
+		for j, v := range getRow(i) {
+			x[j] = v - mean[j]
+		}
+		for j := 0; j < col; j++ {
+			cj := cov.Data[j]
+			for k := j; k < col; k++ {
+				cj[k] += x[j] * x[k]
+			}
+		}
+	}
+	f := 1. / float64(col-1)
+	for j := 0; j < col; j++ {
+		for k := j; k < col; k++ {
+			cov.Data[j][k] *= f
+			cov.Data[k][j] = cov.Data[j][k]
+		}
+	}
+	return cov
+}
@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"testing"
+)
+
+func TestInterface(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 0, 3}, {0, 5, 0}, {7, 0, 9}, {0, 2, 0}})
+	sm := ZeroSparseMatrix(4, 3)
+	for i := range a.Data {
+		for j, v := range a.Data[i] {
+			sm.Set(i, j, v)
+		}
+	}
+	for _, m := range []Interface{a, a.ToDense(), sm} {
+		if r, c := m.Dims(); r != 4 || c != 3 {
+			t.Fail()
+		}
+		if m.At(2, 2) != 9 || !VEqual(m.Row(1), &Vector{0, 5, 0}) || !VEqual(m.Col(0), &Vector{1, 0, 7, 0}) {
+			t.Fail()
+		}
+		if !MEqual(AsMatrix(m), a) || !VEqual(MeanOf(m), a.Mean(0)) {
+			t.Fail()
+		}
+		if !MEqual(CovMatrixOf(m, nil), a.CovMatrix()) {
+			t.Fail()
+		}
+	}
+	if AsMatrix(a) != a {
+		t.Fail()
+	}
+}
+
+func TestCovMatrixOf(t *testing.T) {
+	a := GenerateRandomMatrix(20, 4)
+	w := GenerateRandomVector(20)
+	data := make(Data, 20)
+	for i, v := range a.Data {
+		data[i] = *(v.MulNum(w.At(i)))
+	}
+	if !MEqual(CovMatrixOf(a, w), new(Matrix).Init(data).CovMatrix()) || !MEqual(CovMatrixOf(a.ToDense(), nil), a.CovMatrix()) {
+		t.Fail()
+	}
+}
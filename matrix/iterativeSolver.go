@@ -0,0 +1,486 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// LinearOperator is anything can be multiplied with a vector, `Matrix`, `SparseMatrix`, `CSR` and `CSC` all satisfy it,
+// iterative solvers only need it so the matrix never has to be densified
+type LinearOperator interface {
+	Dims() (row, col int)
+	MulVec(v *Vector) *Vector
+}
+
+var (
+	_ LinearOperator = (*Matrix)(nil)
+	_ LinearOperator = (*SparseMatrix)(nil)
+	_ LinearOperator = (*CSR)(nil)
+	_ LinearOperator = (*CSC)(nil)
+)
+
+// Preconditioner approximates the inverse of system matrix, `Apply` returns z solving M z = r
+type Preconditioner interface {
+	Apply(r *Vector) *Vector
+}
+
+// IterativeSettings controls iterative solvers, zero values mean defaults
+type IterativeSettings struct {
+	Tol     float64        // relative residual tolerance ||b - A x|| / ||b||, default 1e-10
+	MaxIter int            // maximum iterations (matrix-vector products for GMRES), default 10 * n
+	Restart int            // GMRES restart length, default min(30, n)
+	X0      *Vector        // initial guess, default zero vector
+	Precond Preconditioner // optional preconditioner, default none
+}
+
+// IterativeResult is the result of iterative solvers
+type IterativeResult struct {
+	X               *Vector   // solution
+	Iterations      int       // iterations used
+	Residual        float64   // final relative residual
+	ResidualHistory []float64 // relative residual of initial guess and after each iteration
+	Converged       bool      // whether Residual <= Tol
+}
+
+// identityPreconditioner is used when no preconditioner is given
+type identityPreconditioner struct{}
+
+func (identityPreconditioner) Apply(r *Vector) *Vector {
+	z := make(Vector, len(*r))
+	copy(z, *r)
+	return &z
+}
+
+// init checks dims and fills defaults, returns initial guess x, preconditioner and ||b||
+func (s *IterativeSettings) init(op string, a LinearOperator, b *Vector) (IterativeSettings, *Vector, float64, error) {
+	var st IterativeSettings
+	if s != nil {
+		st = *s
+	}
+	row, col := a.Dims()
+	if row != col {
+		return st, nil, 0, squareError(op, row, col)
+	}
+	if b.Length() != row {
+		return st, nil, 0, dimsError(op, row, col, b.Length(), 1)
+	}
+	if st.Tol <= 0 {
+		st.Tol = 1e-10
+	}
+	if st.MaxIter <= 0 {
+		st.MaxIter = 10 * row
+	}
+	if st.Restart <= 0 {
+		st.Restart = MinInt(30, row)
+	}
+	if st.Precond == nil {
+		st.Precond = identityPreconditioner{}
+	}
+	x := make(Vector, row)
+	if st.X0 != nil {
+		if st.X0.Length() != row {
+			return st, nil, 0, lenError(op, row, st.X0.Length())
+		}
+		copy(x, *st.X0)
+	}
+	bNorm := b.Norm()
+	if bNorm == 0 {
+		bNorm = 1
+	}
+	return st, &x, bNorm, nil
+}
+
+// residual returns b - A x
+func residual(a LinearOperator, b, x *Vector) *Vector {
+	r := a.MulVec(x)
+	for i := range *r {
+		(*r)[i] = (*b)[i] - (*r)[i]
+	}
+	return r
+}
+
+// ConjugateGradient solves A x = b for symmetric positive definite A
+//	https://en.wikipedia.org/wiki/Conjugate_gradient_method#The_preconditioned_conjugate_gradient_method
+//	returns error wrapping ErrNotPositiveDefinite if a non-positive curvature p' A p is met
+func ConjugateGradient(a LinearOperator, b *Vector, settings *IterativeSettings) (*IterativeResult, error) {
+	st, x, bNorm, err := settings.init("ConjugateGradient", a, b)
+	if err != nil {
+		return nil, err
+	}
+	r := residual(a, b, x)
+	res := &IterativeResult{X: x, Residual: r.Norm() / bNorm}
+	res.ResidualHistory = append(res.ResidualHistory, res.Residual)
+	if res.Converged = res.Residual <= st.Tol; res.Converged {
+		return res, nil
+	}
+	z := st.Precond.Apply(r)
+	p := make(Vector, len(*z))
+	copy(p, *z)
+	rz := r.Dot(z)
+	for res.Iterations < st.MaxIter {
+		res.Iterations++
+		ap := a.MulVec(&p)
+		pap := p.Dot(ap)
+		if pap <= 0 {
+			return res, fmt.Errorf("ConjugateGradient: %w (p'Ap = %g at iteration %d)", ErrNotPositiveDefinite, pap, res.Iterations)
+		}
+		alpha := rz / pap
+		Axpy(alpha, &p, x)
+		Axpy(-alpha, ap, r)
+		res.Residual = r.Norm() / bNorm
+		res.ResidualHistory = append(res.ResidualHistory, res.Residual)
+		if res.Converged = res.Residual <= st.Tol; res.Converged {
+			break
+		}
+		z = st.Precond.Apply(r)
+		rzNew := r.Dot(z)
+		beta := rzNew / rz
+		rz = rzNew
+		for i := range p {
+			p[i] = (*z)[i] + beta*p[i]
+		}
+	}
+	return res, nil
+}
+
+// BiCGSTAB solves A x = b for general square A with biconjugate gradient stabilized method (right preconditioned)
+//	https://en.wikipedia.org/wiki/Biconjugate_gradient_stabilized_method
+//	returns error wrapping ErrBreakdown if rho or omega vanishes before convergence
+func BiCGSTAB(a LinearOperator, b *Vector, settings *IterativeSettings) (*IterativeResult, error) {
+	st, x, bNorm, err := settings.init("BiCGSTAB", a, b)
+	if err != nil {
+		return nil, err
+	}
+	r := residual(a, b, x)
+	res := &IterativeResult{X: x, Residual: r.Norm() / bNorm}
+	res.ResidualHistory = append(res.ResidualHistory, res.Residual)
+	if res.Converged = res.Residual <= st.Tol; res.Converged {
+		return res, nil
+	}
+	n := len(*r)
+	rHat := make(Vector, n)
+	copy(rHat, *r)
+	rho, alpha, omega := 1., 1., 1.
+	p, v := make(Vector, n), make(Vector, n)
+	for res.Iterations < st.MaxIter {
+		res.Iterations++
+		rhoNew := rHat.Dot(r)
+		if rhoNew == 0 {
+			return res, fmt.Errorf("BiCGSTAB: %w (rho = 0 at iteration %d)", ErrBreakdown, res.Iterations)
+		}
+		beta := (rhoNew / rho) * (alpha / omega)
+		rho = rhoNew
+		for i := range p {
+			p[i] = (*r)[i] + beta*(p[i]-omega*v[i])
+		}
+		pHat := st.Precond.Apply(&p)
+		v = *a.MulVec(pHat)
+		rv := rHat.Dot(&v)
+		if rv == 0 {
+			return res, fmt.Errorf("BiCGSTAB: %w (r'v = 0 at iteration %d)", ErrBreakdown, res.Iterations)
+		}
+		alpha = rho / rv
+		s := r
+		Axpy(-alpha, &v, s)
+		Axpy(alpha, pHat, x)
+		if sNorm := s.Norm() / bNorm; sNorm <= st.Tol {
+			res.Residual, res.Converged = sNorm, true
+			res.ResidualHistory = append(res.ResidualHistory, sNorm)
+			break
+		}
+		sHat := st.Precond.Apply(s)
+		t := a.MulVec(sHat)
+		tt := t.Dot(t)
+		if tt == 0 {
+			return res, fmt.Errorf("BiCGSTAB: %w (t = 0 at iteration %d)", ErrBreakdown, res.Iterations)
+		}
+		omega = t.Dot(s) / tt
+		Axpy(omega, sHat, x)
+		Axpy(-omega, t, s)
+		r = s
+		res.Residual = r.Norm() / bNorm
+		res.ResidualHistory = append(res.ResidualHistory, res.Residual)
+		if res.Converged = res.Residual <= st.Tol; res.Converged {
+			break
+		}
+		if omega == 0 {
+			return res, fmt.Errorf("BiCGSTAB: %w (omega = 0 at iteration %d)", ErrBreakdown, res.Iterations)
+		}
+	}
+	return res, nil
+}
+
+// GMRES solves A x = b for general square A with restarted generalized minimal residual method (right preconditioned)
+//	https://en.wikipedia.org/wiki/Generalized_minimal_residual_method
+//	Arnoldi process uses modified Gram-Schmidt, least squares problem is updated by Givens rotations
+func GMRES(a LinearOperator, b *Vector, settings *IterativeSettings) (*IterativeResult, error) {
+	st, x, bNorm, err := settings.init("GMRES", a, b)
+	if err != nil {
+		return nil, err
+	}
+	m := st.Restart
+	n := len(*x)
+	r := residual(a, b, x)
+	res := &IterativeResult{X: x, Residual: r.Norm() / bNorm}
+	res.ResidualHistory = append(res.ResidualHistory, res.Residual)
+	if res.Converged = res.Residual <= st.Tol; res.Converged {
+		return res, nil
+	}
+	V := make([]Vector, m+1)
+	H := ZeroMatrix(m+1, m).Data
+	cs, sn, g := make([]float64, m), make([]float64, m), make([]float64, m+1)
+	for res.Iterations < st.MaxIter {
+		beta := r.Norm()
+		V[0] = *r.MulNum(1 / beta)
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = beta
+		k := 0
+		for k < m && res.Iterations < st.MaxIter {
+			res.Iterations++
+			w := *a.MulVec(st.Precond.Apply(&V[k]))
+			for i := 0; i <= k; i++ {
+				H[i][k] = w.Dot(&V[i])
+				Axpy(-H[i][k], &V[i], &w)
+			}
+			H[k+1][k] = w.Norm()
+			// apply previous rotations to the new column
+			for i := 0; i < k; i++ {
+				h0, h1 := H[i][k], H[i+1][k]
+				H[i][k] = cs[i]*h0 + sn[i]*h1
+				H[i+1][k] = -sn[i]*h0 + cs[i]*h1
+			}
+			hNorm := math.Hypot(H[k][k], H[k+1][k])
+			if hNorm == 0 {
+				return res, fmt.Errorf("GMRES: %w (singular Hessenberg at iteration %d)", ErrBreakdown, res.Iterations)
+			}
+			cs[k], sn[k] = H[k][k]/hNorm, H[k+1][k]/hNorm
+			happy := H[k+1][k] == 0
+			if !happy {
+				V[k+1] = make(Vector, n)
+				for i, v := range w {
+					V[k+1][i] = v / H[k+1][k]
+				}
+			}
+			H[k][k], H[k+1][k] = hNorm, 0
+			g[k+1] = -sn[k] * g[k]
+			g[k] = cs[k] * g[k]
+			k++
+			res.Residual = math.Abs(g[k]) / bNorm
+			res.ResidualHistory = append(res.ResidualHistory, res.Residual)
+			if res.Residual <= st.Tol || happy {
+				break
+			}
+		}
+		// solve upper triangular H y = g, then x += M^-1 V y
+		y := make([]float64, k)
+		for i := k - 1; i >= 0; i-- {
+			s := g[i]
+			for j := i + 1; j < k; j++ {
+				s -= H[i][j] * y[j]
+			}
+			y[i] = s / H[i][i]
+		}
+		u := make(Vector, n)
+		for i := 0; i < k; i++ {
+			Axpy(y[i], &V[i], &u)
+		}
+		Axpy(1, st.Precond.Apply(&u), x)
+		// the true residual restarts the next cycle and guards against the recurrence drifting away
+		r = residual(a, b, x)
+		res.Residual = r.Norm() / bNorm
+		res.ResidualHistory[len(res.ResidualHistory)-1] = res.Residual
+		if res.Converged = res.Residual <= st.Tol; res.Converged {
+			break
+		}
+	}
+	return res, nil
+}
+
+// JacobiPreconditioner is the diagonal preconditioner M = diag(A)
+type JacobiPreconditioner struct {
+	InvDiag Vector
+}
+
+// NewJacobiPreconditioner returns Jacobi preconditioner of square matrix, error wrapping ErrSingular if any diagonal element is zero
+func NewJacobiPreconditioner(a Interface) (*JacobiPreconditioner, error) {
+	row, col := a.Dims()
+	if row != col {
+		return nil, squareError("NewJacobiPreconditioner", row, col)
+	}
+	inv := make(Vector, row)
+	for i := range inv {
+		d := a.At(i, i)
+		if d == 0 {
+			return nil, fmt.Errorf("NewJacobiPreconditioner: %w (zero diagonal at %d)", ErrSingular, i)
+		}
+		inv[i] = 1 / d
+	}
+	return &JacobiPreconditioner{InvDiag: inv}, nil
+}
+
+// Apply returns diag(A)^-1 r
+func (p *JacobiPreconditioner) Apply(r *Vector) *Vector {
+	z := make(Vector, len(*r))
+	for i, v := range *r {
+		z[i] = v * p.InvDiag[i]
+	}
+	return &z
+}
+
+// IncompleteCholesky is the zero fill-in incomplete Cholesky preconditioner IC(0), M = L L'
+//	L has the same sparsity pattern as lower triangle of A
+type IncompleteCholesky struct {
+	L *CSR
+}
+
+// NewIncompleteCholesky computes IC(0) of symmetric positive definite CSR matrix, only its lower triangle is used
+//	returns error wrapping ErrNotPositiveDefinite if a non-positive pivot is met
+func NewIncompleteCholesky(a *CSR) (*IncompleteCholesky, error) {
+	if a.Rows != a.Cols {
+		return nil, squareError("NewIncompleteCholesky", a.Rows, a.Cols)
+	}
+	n := a.Rows
+	ptr := make([]int, n+1)
+	idx := make([]int, 0, a.NNZ()/2+n)
+	val := make([]float64, 0, a.NNZ()/2+n)
+	for i := 0; i < n; i++ {
+		cols, values := a.RowNonZeros(i)
+		for k, j := range cols {
+			if j <= i {
+				idx = append(idx, j)
+				val = append(val, values[k])
+			}
+		}
+		if len(idx) == ptr[i] || idx[len(idx)-1] != i {
+			return nil, fmt.Errorf("NewIncompleteCholesky: %w (zero diagonal at %d)", ErrNotPositiveDefinite, i)
+		}
+		ptr[i+1] = len(idx)
+	}
+	for i := 0; i < n; i++ {
+		for p := ptr[i]; p < ptr[i+1]; p++ {
+			k := idx[p]
+			// l_ik = (a_ik - sum_{j<k} l_ij * l_kj) / l_kk, sum over common pattern of rows i and k
+			s := val[p]
+			q, qe := ptr[k], ptr[k+1]-1
+			for pp := ptr[i]; pp < p && q < qe; {
+				switch {
+				case idx[pp] < idx[q]:
+					pp++
+				case idx[pp] > idx[q]:
+					q++
+				default:
+					s -= val[pp] * val[q]
+					pp++
+					q++
+				}
+			}
+			if k < i {
+				val[p] = s / val[ptr[k+1]-1]
+				continue
+			}
+			if s <= 0 {
+				return nil, fmt.Errorf("NewIncompleteCholesky: %w (pivot %d is %g)", ErrNotPositiveDefinite, i, s)
+			}
+			val[p] = math.Sqrt(s)
+		}
+	}
+	return &IncompleteCholesky{L: &CSR{Rows: n, Cols: n, RowPtr: ptr, ColIdx: idx, Values: val}}, nil
+}
+
+// Apply returns (L L')^-1 r by forward and backward substitution
+func (p *IncompleteCholesky) Apply(r *Vector) *Vector {
+	L := p.L
+	z := make(Vector, len(*r))
+	copy(z, *r)
+	for i := 0; i < L.Rows; i++ {
+		d := L.RowPtr[i+1] - 1
+		for q := L.RowPtr[i]; q < d; q++ {
+			z[i] -= L.Values[q] * z[L.ColIdx[q]]
+		}
+		z[i] /= L.Values[d]
+	}
+	// L' z = y, column oriented on rows of L
+	for i := L.Rows - 1; i >= 0; i-- {
+		d := L.RowPtr[i+1] - 1
+		z[i] /= L.Values[d]
+		for q := L.RowPtr[i]; q < d; q++ {
+			z[L.ColIdx[q]] -= L.Values[q] * z[i]
+		}
+	}
+	return &z
+}
+
+// ILU0 is the zero fill-in incomplete LU preconditioner ILU(0), M = L U
+//	unit lower L and upper U are stored together in LU with the sparsity pattern of A
+type ILU0 struct {
+	LU   *CSR
+	diag []int // position of diagonal element in each row
+}
+
+// NewILU0 computes ILU(0) of square CSR matrix
+//	returns error wrapping ErrSingular if a diagonal element is missing or a zero pivot is met
+func NewILU0(a *CSR) (*ILU0, error) {
+	if a.Rows != a.Cols {
+		return nil, squareError("NewILU0", a.Rows, a.Cols)
+	}
+	n := a.Rows
+	lu := a.Copy()
+	diag := make([]int, n)
+	for i := 0; i < n; i++ {
+		p, ok := lu.find(i, i)
+		if !ok {
+			return nil, fmt.Errorf("NewILU0: %w (zero diagonal at %d)", ErrSingular, i)
+		}
+		diag[i] = p
+	}
+	pos := make([]int, n) // column -> position in current row, -1 if not in pattern
+	for j := range pos {
+		pos[j] = -1
+	}
+	for i := 0; i < n; i++ {
+		s, e := lu.RowPtr[i], lu.RowPtr[i+1]
+		for p := s; p < e; p++ {
+			pos[lu.ColIdx[p]] = p
+		}
+		for p := s; p < diag[i]; p++ {
+			k := lu.ColIdx[p]
+			if lu.Values[diag[k]] == 0 {
+				return nil, fmt.Errorf("NewILU0: %w (zero pivot at %d)", ErrSingular, k)
+			}
+			lu.Values[p] /= lu.Values[diag[k]]
+			for q := diag[k] + 1; q < lu.RowPtr[k+1]; q++ {
+				if t := pos[lu.ColIdx[q]]; t >= 0 {
+					lu.Values[t] -= lu.Values[p] * lu.Values[q]
+				}
+			}
+		}
+		for p := s; p < e; p++ {
+			pos[lu.ColIdx[p]] = -1
+		}
+		if lu.Values[diag[i]] == 0 {
+			return nil, fmt.Errorf("NewILU0: %w (zero pivot at %d)", ErrSingular, i)
+		}
+	}
+	return &ILU0{LU: lu, diag: diag}, nil
+}
+
+// Apply returns (L U)^-1 r by forward and backward substitution
+func (p *ILU0) Apply(r *Vector) *Vector {
+	LU := p.LU
+	z := make(Vector, len(*r))
+	copy(z, *r)
+	for i := 0; i < LU.Rows; i++ {
+		for q := LU.RowPtr[i]; q < p.diag[i]; q++ {
+			z[i] -= LU.Values[q] * z[LU.ColIdx[q]]
+		}
+	}
+	for i := LU.Rows - 1; i >= 0; i-- {
+		for q := p.diag[i] + 1; q < LU.RowPtr[i+1]; q++ {
+			z[i] -= LU.Values[q] * z[LU.ColIdx[q]]
+		}
+		z[i] /= LU.Values[p.diag[i]]
+	}
+	return &z
+}
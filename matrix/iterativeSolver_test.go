@@ -0,0 +1,200 @@
+package matrix
+
+import (
+	"errors"
+	"testing"
+)
+
+// laplacian2D returns the 5-point finite difference Laplacian on an n x n grid, it is SPD
+func laplacian2D(n int) *SparseMatrix {
+	sm := ZeroSparseMatrix(n*n, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			sm.Set(k, k, 4)
+			if i > 0 {
+				sm.Set(k, k-n, -1)
+			}
+			if i < n-1 {
+				sm.Set(k, k+n, -1)
+			}
+			if j > 0 {
+				sm.Set(k, k-1, -1)
+			}
+			if j < n-1 {
+				sm.Set(k, k+1, -1)
+			}
+		}
+	}
+	return sm
+}
+
+// convectionDiffusion2D returns a non-symmetric Laplacian with first order upwind convection term
+func convectionDiffusion2D(n int, c float64) *SparseMatrix {
+	sm := laplacian2D(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			sm.Set(k, k, 4+c)
+			if j > 0 {
+				sm.Set(k, k-1, -1-c)
+			}
+		}
+	}
+	return sm
+}
+
+func checkSolution(t *testing.T, a LinearOperator, b *Vector, res *IterativeResult, tol float64) {
+	t.Helper()
+	if !res.Converged || res.Residual > tol {
+		t.Errorf("not converged: %d iterations, residual %g", res.Iterations, res.Residual)
+	}
+	if len(res.ResidualHistory) != res.Iterations+1 {
+		t.Errorf("residual history length %d vs iterations %d", len(res.ResidualHistory), res.Iterations)
+	}
+	if r := residual(a, b, res.X).Norm() / b.Norm(); r > 10*tol {
+		t.Errorf("true residual %g", r)
+	}
+}
+
+func TestConjugateGradient(t *testing.T) {
+	A := laplacian2D(20).ToCSR()
+	b := GenerateRandomVector(400)
+	res, err := ConjugateGradient(A, b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, res, 1e-10)
+
+	jacobi, _ := NewJacobiPreconditioner(A)
+	resJ, err := ConjugateGradient(A, b, &IterativeSettings{Precond: jacobi})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, resJ, 1e-10)
+
+	ic, err := NewIncompleteCholesky(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resIC, err := ConjugateGradient(A, b, &IterativeSettings{Precond: ic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, resIC, 1e-10)
+	if resIC.Iterations >= res.Iterations {
+		t.Errorf("IC(0) does not reduce iterations: %d vs %d", resIC.Iterations, res.Iterations)
+	}
+
+	// dense matrix as operator
+	M := new(Matrix).Init(Data{{4, 1}, {1, 3}})
+	resM, err := ConjugateGradient(M, &Vector{1, 2}, nil)
+	if err != nil || !VEqual(resM.X, &Vector{1. / 11, 7. / 11}) {
+		t.Fail()
+	}
+}
+
+func TestConjugateGradientErrors(t *testing.T) {
+	if _, err := ConjugateGradient(ZeroSparseMatrix(2, 3), &Vector{1, 2}, nil); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, err := ConjugateGradient(ZeroSparseMatrix(2, 2), &Vector{1, 2, 3}, nil); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	indefinite := new(Matrix).Init(Data{{1, 0}, {0, -1}})
+	if _, err := ConjugateGradient(indefinite, &Vector{0, 1}, nil); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+	res, err := ConjugateGradient(laplacian2D(20), GenerateRandomVector(400), &IterativeSettings{MaxIter: 3})
+	if err != nil || res.Converged || res.Iterations != 3 {
+		t.Fail()
+	}
+}
+
+func TestBiCGSTAB(t *testing.T) {
+	A := convectionDiffusion2D(20, 2).ToCSR()
+	b := GenerateRandomVector(400)
+	res, err := BiCGSTAB(A, b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, res, 1e-10)
+
+	ilu, err := NewILU0(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resILU, err := BiCGSTAB(A, b, &IterativeSettings{Precond: ilu})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, resILU, 1e-10)
+	if resILU.Iterations >= res.Iterations {
+		t.Errorf("ILU(0) does not reduce iterations: %d vs %d", resILU.Iterations, res.Iterations)
+	}
+}
+
+func TestGMRES(t *testing.T) {
+	A := convectionDiffusion2D(20, 2)
+	b := GenerateRandomVector(400)
+	res, err := GMRES(A, b, &IterativeSettings{Restart: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, res, 1e-10)
+
+	ilu, _ := NewILU0(A.ToCSR())
+	resILU, err := GMRES(A, b, &IterativeSettings{Restart: 20, Precond: ilu})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, A, b, resILU, 1e-10)
+	if resILU.Iterations >= res.Iterations {
+		t.Errorf("ILU(0) does not reduce iterations: %d vs %d", resILU.Iterations, res.Iterations)
+	}
+
+	// full GMRES converges in at most n steps
+	M := new(Matrix).Init(Data{{1, 2, 0}, {0, 1, 3}, {4, 0, 1}})
+	resM, err := GMRES(M, &Vector{1, 2, 3}, nil)
+	if err != nil || resM.Iterations > 3 || !VEqual(M.MulVec(resM.X), &Vector{1, 2, 3}) {
+		t.Fail()
+	}
+}
+
+func TestPreconditioners(t *testing.T) {
+	A := laplacian2D(5).ToCSR()
+	r := GenerateRandomVector(25)
+	// on a tridiagonal matrix there is no fill-in, so IC(0) and ILU(0) are exact
+	tri := ZeroSparseMatrix(10, 10)
+	for i := 0; i < 10; i++ {
+		tri.Set(i, i, 2)
+		if i > 0 {
+			tri.Set(i, i-1, -1)
+			tri.Set(i-1, i, -1)
+		}
+	}
+	x := GenerateRandomVector(10)
+	b := tri.MulVec(x)
+	ic, _ := NewIncompleteCholesky(tri.ToCSR())
+	if !VEqual(ic.Apply(b), x) {
+		t.Fail()
+	}
+	ilu, _ := NewILU0(tri.ToCSR())
+	if !VEqual(ilu.Apply(b), x) {
+		t.Fail()
+	}
+	jacobi, _ := NewJacobiPreconditioner(A)
+	if !VEqual(jacobi.Apply(r), r.MulNum(0.25)) {
+		t.Fail()
+	}
+	if _, err := NewJacobiPreconditioner(ZeroSparseMatrix(2, 2)); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if _, err := NewILU0(ZeroSparseMatrix(2, 2).ToCSR()); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	notPD := NewCSR(2, 2, []Entry{{Value: 1, Row: 0, Col: 0}, {Value: 2, Row: 1, Col: 0}, {Value: 2, Row: 0, Col: 1}, {Value: 1, Row: 1, Col: 1}})
+	if _, err := NewIncompleteCholesky(notPD); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+}
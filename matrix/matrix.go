@@ -1,10 +1,7 @@
 package matrix
 
 import (
-	"context"
 	"fmt"
-	"golang.org/x/sync/semaphore"
-	"log"
 	"math"
 	"runtime"
 )
@@ -27,40 +24,10 @@ type Entry struct {
 	Row, Col int
 }
 
-// _Matrix interface
-type _Matrix interface {
-	// dimensions
-	Dims() (row, col int)
-
-	// value at index(row i, col j), panic if not access
-	At(i, j int) float64
-
-	// set value at index(row i, col j), panic if not access
-	Set(i, j int, value float64)
-
-	// transpose matrix
-	T() _Matrix
-
-	// get row
-	Row(i int) *Vector
-
-	// get column
-	Col(i int) *Vector
-
-	// max entry
-	Max() *Entry
-
-	// min entry
-	Min() *Entry
-
-	// rank
-	Rank() int
-}
-
 // Matrix struct
+//	it satisfies `Interface`
 type Matrix struct {
-	_Matrix      // basic interface
-	Data    Data // row-wise
+	Data Data // row-wise
 }
 
 // Init generates matrix struct from 2D array
@@ -118,11 +85,19 @@ func (t *Matrix) Col(n int) *Vector {
 }
 
 // Copy returns a deep copy of matrix
+//	notice: rows of the copy are stored in one contiguous block
 func Copy(t *Matrix) *Matrix {
+	n := 0
+	for i := range t.Data {
+		n += len(t.Data[i])
+	}
+	buf := make([]float64, n)
 	nt := Matrix{Data: make([]Vector, len(t.Data))}
 	for i := range t.Data {
-		nt.Data[i] = make(Vector, len(t.Data[i]))
+		l := len(t.Data[i])
+		nt.Data[i] = buf[:l:l]
 		copy(nt.Data[i], t.Data[i])
+		buf = buf[l:]
 	}
 	return &nt
 }
@@ -131,34 +106,22 @@ func Copy(t *Matrix) *Matrix {
 // 	golang make slice has zero value in default, so empty matrix == zero matrix
 func Empty(t *Matrix) *Matrix {
 	row, col := t.Dims()
-	nt := Matrix{Data: make([]Vector, row)}
-	for i := range t.Data {
-		nt.Data[i] = make(Vector, col)
-	}
-	return &nt // nt is a zero matrix
+	return ZeroMatrix(row, col) // nt is a zero matrix
 }
 
 // ZeroMatrix generates matrix with all elements are zero
+//	notice: rows are views of one contiguous block, see `Dense`
 func ZeroMatrix(row, col int) *Matrix {
-	nt := Matrix{Data: make([]Vector, row)}
-	for i := range nt.Data {
-		nt.Data[i] = make(Vector, col)
-	}
-	return &nt
+	return ZeroDense(row, col).ToMatrix()
 }
 
 // OneMatrix generates matrix with all elements are one
 func OneMatrix(row, col int) *Matrix {
-	nt := Matrix{Data: make([]Vector, row)}
-	r := make(Vector, col)
-	for i := range r {
-		r[i] = 1
-	}
-	for i := range nt.Data {
-		nt.Data[i] = make(Vector, col)
-		copy(nt.Data[i], r)
+	d := ZeroDense(row, col)
+	for i := range d.Data {
+		d.Data[i] = 1
 	}
-	return &nt
+	return d.ToMatrix()
 }
 
 // IdentityMatrix generates diagonal matrix with ones as diagonal elements, like `eye` in other libs
@@ -263,28 +226,42 @@ func SwapRow(t *Matrix, row1, row2 int) {
 
 // Det returns determinant of N x N matrix based on LU Decomposition
 func (t *Matrix) Det() float64 {
+	det, err := t.TryDet()
+	must(err)
+	return det
+}
+
+// TryDet returns determinant of N x N matrix based on LU Decomposition, ErrNotSquare for non-square matrix
+//	notice: partial pivoting LU without tolerance is used, so singular matrix gives (nearly) 0 instead of a failure
+//	and matrices with small pivots (e.g. scaled by 1e-3) keep their exact determinant
+func (t *Matrix) TryDet() (float64, error) {
 	row, col := t.Dims()
 	if row != col {
-		panic("need N x N matrix for determinant calculation")
-	}
-	nt, P := LUPDecompose(t, row, EPS)
-	if nt == nil {
-		panic("Can Not do LUP Decomposition with tolerance 1e-6")
+		return 0, squareError("Det", row, col)
 	}
-	return LUPDeterminant(nt, P, row)
+	nt, P := decomposeLU(t)
+	return LUPDeterminant(nt, P, row), nil
 }
 
 // Inverse returns inverse matrix of original matrix
 func (t *Matrix) Inverse() *Matrix {
+	nt, err := t.TryInverse()
+	must(err)
+	return nt
+}
+
+// TryInverse returns inverse matrix of original matrix
+//	ErrNotSquare for non-square matrix, ErrSingular if LUP decomposition fails with tolerance EPS
+func (t *Matrix) TryInverse() (*Matrix, error) {
 	row, col := t.Dims()
 	if row != col {
-		panic("only inverse only support square matrix, left/right is not supported")
+		return nil, squareError("Inverse", row, col)
 	}
-	nt, P := LUPDecompose(t, row, EPS)
-	if nt == nil {
-		panic("Can Not do LUP Decomposition with tolerance 1e-6")
+	nt, P, err := TryLUPDecompose(t, row, EPS)
+	if err != nil {
+		return nil, err
 	}
-	return LUPInvert(nt, P, row)
+	return LUPInvert(nt, P, row), nil
 }
 
 // NaiveDet returns determinant of N x N matrix recursively
@@ -394,10 +371,17 @@ func NaiveInverse(t *Matrix) *Matrix {
 
 // Add sums two matrices and returns a new matrix
 func (t *Matrix) Add(mat2 *Matrix) *Matrix {
+	nt, err := t.TryAdd(mat2)
+	must(err)
+	return nt
+}
+
+// TryAdd sums two matrices and returns a new matrix, ErrDimensionMismatch if dims are different
+func (t *Matrix) TryAdd(mat2 *Matrix) (*Matrix, error) {
 	row1, col1 := t.Dims()
 	row2, col2 := mat2.Dims()
 	if [2]int{row1, col1} != [2]int{row2, col2} {
-		panic("both matrices should have the same dimension")
+		return nil, dimsError("Add", row1, col1, row2, col2)
 	}
 	nt := Empty(t)
 	for r, i := range t.Data {
@@ -405,7 +389,7 @@ func (t *Matrix) Add(mat2 *Matrix) *Matrix {
 			nt.Set(r, c, j+mat2.At(r, c))
 		}
 	}
-	return nt
+	return nt, nil
 }
 
 // AddNum adds number to all elements in matrix and returns a new matrix
@@ -422,10 +406,17 @@ func (t *Matrix) AddNum(n interface{}) *Matrix {
 
 // Sub subtract two matrix and returns a new matrix
 func (t *Matrix) Sub(mat2 *Matrix) *Matrix {
+	nt, err := t.TrySub(mat2)
+	must(err)
+	return nt
+}
+
+// TrySub subtract two matrix and returns a new matrix, ErrDimensionMismatch if dims are different
+func (t *Matrix) TrySub(mat2 *Matrix) (*Matrix, error) {
 	row1, col1 := t.Dims()
 	row2, col2 := mat2.Dims()
 	if [2]int{row1, col1} != [2]int{row2, col2} {
-		panic("both matrices should have the same dimension")
+		return nil, dimsError("Sub", row1, col1, row2, col2)
 	}
 	nt := Empty(t)
 	for r, i := range t.Data {
@@ -433,58 +424,65 @@ func (t *Matrix) Sub(mat2 *Matrix) *Matrix {
 			nt.Set(r, c, j-mat2.At(r, c))
 		}
 	}
-	return nt
+	return nt, nil
 }
 
 // Mul does matrix multiplication (dot | inner) and return a new matrix
 // https://en.wikipedia.org/wiki/Matrix_multiplication
+//	notice: it is cache-blocked and runs in parallel for large products, see `MulConfig`
 func (t *Matrix) Mul(mat2 *Matrix) *Matrix {
-	// TODO: need optimize
+	out, err := t.TryMul(mat2)
+	must(err)
+	return out
+}
+
+// TryMul does matrix multiplication and return a new matrix, ErrDimensionMismatch if it is not M x N and N x L
+func (t *Matrix) TryMul(mat2 *Matrix) (*Matrix, error) {
 	row1, col1 := t.Dims()
 	row2, col2 := mat2.Dims()
 	if col1 != row2 {
-		panic("matrix multiplication need M x N and N x L matrices to get M x L matrix")
+		return nil, dimsError("Mul", row1, col1, row2, col2)
 	}
 	out := ZeroMatrix(row1, col2)
-	if row1 <= 80 {
-		for i := 0; i < row1; i++ {
-			for j := 0; j < col2; j++ {
-				for k := 0; k < row2; k++ {
-					out.Set(i, j, out.At(i, j)+t.At(i, k)*mat2.At(k, j))
-				}
-			}
-		}
-	} else {
-		sem := semaphore.NewWeighted(int64(runtime.NumCPU()))
-		ctx := context.TODO()
-		sum := 0.
-		for i := 0; i < row1; i++ {
-			if err := sem.Acquire(ctx, 1); err != nil {
-				log.Printf("Failed to acquire semaphore: %v", err)
-				break
-			}
-			go func(i int) {
-				defer sem.Release(1)
-				for j := 0; j < col2; j++ {
-					sum = 0.
-					for k := 0; k < row2; k++ {
-						sum += out.At(i, j) + t.At(i, k)*mat2.At(k, j)
-					}
-					out.Set(i, j, sum)
-				}
-			}(i)
-		}
-		if err := sem.Acquire(ctx, int64(runtime.NumCPU())); err != nil {
-			log.Printf("Failed to acquire semaphore: %v", err)
-		}
-	}
-	return out
+	gemm(t, mat2, out)
+	return out, nil
 }
 
 // MulVec does multiplication between matrix and vector and returns a new vector
 //	notice: all vectors in this package is row vector
 func (t *Matrix) MulVec(v *Vector) *Vector {
-	return t.Mul(new(Matrix).Init(Data{*v}).T()).T().Row(0)
+	nv, err := t.TryMulVec(v)
+	must(err)
+	return nv
+}
+
+// TryMulVec does multiplication between matrix and vector and returns a new vector,
+// ErrDimensionMismatch if matrix columns is not equal to vector length
+func (t *Matrix) TryMulVec(v *Vector) (*Vector, error) {
+	row, col := t.Dims()
+	if col != v.Length() {
+		return nil, dimsError("MulVec", row, col, v.Length(), 1)
+	}
+	return t.Mul(new(Matrix).Init(Data{*v}).T()).T().Row(0), nil
+}
+
+// MulVecT returns t.T() * v without transposing the matrix, panics if matrix rows is not equal to vector length
+func (t *Matrix) MulVecT(v *Vector) *Vector {
+	row, col := t.Dims()
+	if row != v.Length() {
+		panic(dimsError("MulVecT", col, row, v.Length(), 1))
+	}
+	nv := make(Vector, col)
+	for i, r := range t.Data {
+		x := (*v)[i]
+		if x == 0 {
+			continue
+		}
+		for j, a := range r {
+			nv[j] += a * x
+		}
+	}
+	return &nv
 }
 
 // MulNum does multiplication between matrix and number and returns a new matrix
@@ -556,15 +554,22 @@ func NaivePow(t *Matrix, n int) *Matrix {
 // Trace returns sum of all diagonal values
 // https://en.wikipedia.org/wiki/Trace_(linear_algebra)
 func (t *Matrix) Trace() float64 {
+	res, err := t.TryTrace()
+	must(err)
+	return res
+}
+
+// TryTrace returns sum of all diagonal values, ErrNotSquare for non-square matrix
+func (t *Matrix) TryTrace() (float64, error) {
 	row, col := t.Dims()
 	if row != col {
-		panic("square matrix only")
+		return 0, squareError("Trace", row, col)
 	}
 	res := 0.
 	for i := range t.Data {
 		res += t.Data[i][i]
 	}
-	return res
+	return res, nil
 }
 
 // Norm returns Frobenius norm
@@ -608,92 +613,34 @@ func (t *Matrix) SetSubMatrix(i, j int, mat *Matrix) {
 
 // SumCol sums one column of matrix
 func (t *Matrix) SumCol(col int) float64 {
-	s := 0.
-	for _, e := range *(t.Col(col)) {
-		s += e
+	var k compensated
+	for _, r := range t.Data {
+		k.add(r[col])
 	}
-	return s
+	return k.value()
 }
 
 // SumRow sums one row of matrix
 func (t *Matrix) SumRow(row int) float64 {
-	s := 0.
-	for _, e := range *(t.Row(row)) {
-		s += e
-	}
-	return s
+	return t.Row(row).Sum()
 }
 
-// Sum sums the matrix along certain dimension, 0 -> sum all rows into one vector, 1 -> sum all columns into one vector
+// Sum sums the matrix along certain dimension, 0 -> sum all rows into one vector, 1 -> sum all columns into one vector,
+//	-1 -> sum of all elements, by compensated summation
 func (t *Matrix) Sum(dim int) *Vector {
-	row, col := t.Dims()
-	switch dim {
-	case 0:
-		v := make(Vector, col)
-		for i := range v {
-			v[i] = t.SumCol(i)
-		}
-		return &v
-	case 1:
-		v := make(Vector, row)
-		for i := range v {
-			v[i] = t.SumRow(i)
-		}
-		return &v
-	case -1:
-		d := Ternary(row > col, 1, 0).(int)
-		return &Vector{t.Sum(d).Sum()}
-	default:
-		panic("invalid sum dimension")
-	}
+	sums, _ := t.sumAlong("sum", dim, false)
+	return sums
 }
 
 // Mean returns mean vector of matrix along certain dimension, 0 -> row, 1 -> column
 func (t *Matrix) Mean(dim int) *Vector {
-	row, col := t.Dims()
-	switch dim {
-	case 0:
-		return t.Sum(0).MulNum(1. / float64(row)) // Notice: without float64(col), this will be int, e.g. col=3, 1./col=0
-	case 1:
-		return t.Sum(1).MulNum(1. / float64(col))
-	case -1:
-		return t.Sum(-1).MulNum(1. / float64(row*col))
-	default:
-		panic("invalid mean dimension")
-	}
+	return t.meanAlong("mean", dim, false)
 }
 
-// Variance returns variance vector along input dim
+// Variance returns variance vector along input dim by corrected two-pass algorithm
 //	dim: 0 -> |, 1 -> -, -1 -> all
 func (t *Matrix) Variance(dim int) *Vector {
-	row, col := t.Dims()
-	switch dim {
-	case 0:
-		m := t.Mean(0)
-		v := make(Vector, col)
-		for i := range v {
-			v[i] = t.Sub(m.Tile(0, row)).Col(i).Variance()
-		}
-		return &v
-	case 1:
-		m := t.Mean(1)
-		v := make(Vector, row)
-		for i := range v {
-			v[i] = t.Sub(m.Tile(0, col).T()).Row(i).Variance()
-		}
-		return &v
-	case -1:
-		m := t.Mean(-1).At(0)
-		s := 0.
-		for _, r := range t.Sub(OneMatrix(row, col).MulNum(m)).Data {
-			for _, v := range r {
-				s += v * v
-			}
-		}
-		return &Vector{s / float64(row*col)}
-	default:
-		panic("invalid variance dimension")
-	}
+	return t.varianceAlong("variance", dim, false)
 }
 
 // StandardDeviation returns standardDeviation vector along input dim
@@ -709,8 +656,8 @@ func (t *Matrix) StandardDeviation(dim int) *Vector {
 
 // CovMatrix returns covariance matrix
 func (t *Matrix) CovMatrix() *Matrix {
-	row, col := t.Dims()
-	x := t.Sub(t.Mean(0).Tile(0, row))
+	_, col := t.Dims()
+	x := t.SubElem(t.Mean(0))
 	cov := x.T().Mul(x).MulNum(1. / float64(col-1))
 	return cov
 }
@@ -724,7 +671,7 @@ func CrossCovMatrix(mat1, mat2 *Matrix) *Matrix {
 	if r1 != r2 || c1 != c2 {
 		panic("both matrix should have the same dimensions")
 	}
-	return mat1.Sub(mat1.Mean(0).Tile(0, r1)).T().Mul(mat2.Sub(mat2.Mean(0).Tile(0, r1))).MulNum(1. / float64(c1-1))
+	return mat1.SubElem(mat1.Mean(0)).T().Mul(mat2.SubElem(mat2.Mean(0))).MulNum(1. / float64(c1-1))
 }
 
 // IsSymmetric checks whether matrix is symmetric
@@ -779,25 +726,33 @@ func (t *Matrix) UniqueWithCount() map[float64]int {
 // Concatenate concatenates input matrix into original one along input dim and returns a new matrix
 //	notice: matrices should have the same dim in the concatenation direction or it will panic
 func (t *Matrix) Concatenate(mat *Matrix, dim int) *Matrix {
+	nt, err := t.TryConcatenate(mat, dim)
+	must(err)
+	return nt
+}
+
+// TryConcatenate concatenates input matrix into original one along input dim and returns a new matrix,
+// ErrDimensionMismatch if matrices have different dim in the concatenation direction
+func (t *Matrix) TryConcatenate(mat *Matrix, dim int) (*Matrix, error) {
 	rt, ct := t.Dims()
 	rm, cm := mat.Dims()
 	switch dim {
 	case 0:
 		if ct != cm {
-			panic("both matrix should have same columns")
+			return nil, dimsError("Concatenate", rt, ct, rm, cm)
 		}
 		nt := Copy(t)
 		nt.Data = append(nt.Data, mat.Data...)
-		return nt
+		return nt, nil
 	case 1:
 		if rt != rm {
-			panic("both matrix should have same rows")
+			return nil, dimsError("Concatenate", rt, ct, rm, cm)
 		}
 		nt := Copy(t)
 		for i := range nt.Data {
 			nt.Data[i] = append(nt.Data[i], mat.Data[i]...)
 		}
-		return nt
+		return nt, nil
 	default:
 		panic("concatenate only support dim 0 -> vertical, dim 1 -> horizontal")
 	}
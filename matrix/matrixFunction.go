@@ -0,0 +1,427 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// expmPade holds coefficients b of Padé approximants r_m(A) = (V - U)⁻¹ * (V + U) of e^A, m = 3, 5, 7, 9, 13,
+//	with the largest 1-norm theta of A for which r_m is accurate to double precision without scaling
+//	N. J. Higham, The scaling and squaring method for the matrix exponential revisited, SIAM J. Matrix Anal. Appl. 26(4), 2005
+var expmPade = []struct {
+	theta float64
+	b     []float64
+}{
+	{1.495585217958292e-2, []float64{120, 60, 12, 1}},
+	{2.539398330063230e-1, []float64{30240, 15120, 3360, 420, 30, 1}},
+	{9.504178996162932e-1, []float64{17297280, 8648640, 1995840, 277200, 25200, 1512, 56, 1}},
+	{2.097847961257068e0, []float64{17643225600, 8821612800, 2075673600, 302702400, 30270240, 2162160, 110880, 3960, 90, 1}},
+	{5.371920351148152e0, []float64{64764752532480000, 32382376266240000, 7771770303897600, 1187353796428800,
+		129060195264000, 10559470521600, 670442572800, 33522128640, 1323241920, 40840800, 960960, 16380, 182, 1}},
+}
+
+// Expm returns matrix exponential e^A = Σ A^k / k!
+//	https://en.wikipedia.org/wiki/Matrix_exponential
+//	scaling and squaring with Padé approximant of degree 3, 5, 7, 9 or 13 chosen by 1-norm of A (Higham 2005):
+//	e^A = r_13(A / 2^s)^(2^s)
+func Expm(A *Matrix) *Matrix {
+	res, err := TryExpm(A)
+	must(err)
+	return res
+}
+
+// TryExpm returns matrix exponential like `Expm`, ErrNotSquare for non-square matrix
+func TryExpm(A *Matrix) (*Matrix, error) {
+	m, n := A.Dims()
+	if m != n {
+		return nil, squareError("Expm", m, n)
+	}
+	norm := A.Norm1()
+	I := IdentityMatrix(n)
+	var U, V *Matrix
+	s := 0
+	if pade := expmPade[:4]; norm <= pade[3].theta {
+		for _, p := range pade {
+			if norm > p.theta {
+				continue
+			}
+			// U = A * Σ b[2k+1] * A^(2k), V = Σ b[2k] * A^(2k)
+			A2 := A.Mul(A)
+			U, V = ZeroMatrix(n, n), ZeroMatrix(n, n)
+			pow := I
+			for k := 0; k < len(p.b); k += 2 {
+				if k > 0 {
+					pow = pow.Mul(A2)
+				}
+				V = V.Add(pow.MulNum(p.b[k]))
+				U = U.Add(pow.MulNum(p.b[k+1]))
+			}
+			U = A.Mul(U)
+			break
+		}
+	} else {
+		b := expmPade[4].b
+		s = int(math.Max(0, math.Ceil(math.Log2(norm/expmPade[4].theta))))
+		A = A.MulNum(math.Pow(2, -float64(s)))
+		A2 := A.Mul(A)
+		A4 := A2.Mul(A2)
+		A6 := A4.Mul(A2)
+		U = A6.Mul(A6.MulNum(b[13]).Add(A4.MulNum(b[11])).Add(A2.MulNum(b[9])))
+		U = A.Mul(U.Add(A6.MulNum(b[7])).Add(A4.MulNum(b[5])).Add(A2.MulNum(b[3])).Add(I.MulNum(b[1])))
+		V = A6.Mul(A6.MulNum(b[12]).Add(A4.MulNum(b[10])).Add(A2.MulNum(b[8])))
+		V = V.Add(A6.MulNum(b[6])).Add(A4.MulNum(b[4])).Add(A2.MulNum(b[2])).Add(I.MulNum(b[0]))
+	}
+	R, err := LUFactorize(V.Sub(U)).TrySolveMatrix(V.Add(U))
+	if err != nil {
+		return nil, err
+	}
+	for ; s > 0; s-- {
+		R = R.Mul(R)
+	}
+	return R, nil
+}
+
+// Logm returns principal matrix logarithm X of A, e^X = A and eigen values of X have imaginary parts in (-pi, pi)
+//	https://en.wikipedia.org/wiki/Logarithm_of_a_matrix
+//	symmetric A uses eigen decomposition, otherwise inverse scaling and squaring on complex Schur form A = Q * T * Qᴴ:
+//	log(T) = 2^s * log(T^(1/2^s)), square roots are taken until ||T^(1/2^s) - I|| <= 1/4,
+//	then the logarithm is evaluated by Padé approximant of degree 8 in partial fraction (Gauss-Legendre) form
+func Logm(A *Matrix) *Matrix {
+	res, err := TryLogm(A)
+	must(err)
+	return res
+}
+
+// TryLogm returns principal matrix logarithm like `Logm`
+//	ErrNotSquare, ErrSingular for zero eigen value, or ErrDomain for negative real eigen value (the logarithm is not real)
+func TryLogm(A *Matrix) (*Matrix, error) {
+	m, n := A.Dims()
+	if m != n {
+		return nil, squareError("Logm", m, n)
+	}
+	if A.IsSymmetric() {
+		return symmetricFunction("Logm", A, func(lambda float64) (float64, error) {
+			if lambda < 0 {
+				return 0, fmt.Errorf("Logm: %w (negative eigen value %g)", ErrDomain, lambda)
+			}
+			if lambda == 0 {
+				return 0, fmt.Errorf("Logm: %w (zero eigen value)", ErrSingular)
+			}
+			return math.Log(lambda), nil
+		})
+	}
+	T, Q, err := complexSchur("Logm", A)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSpectrum("Logm", T, true); err != nil {
+		return nil, err
+	}
+	L, err := logTriangular(T)
+	if err != nil {
+		return nil, fmt.Errorf("Logm: %w", err)
+	}
+	return Q.Mul(L).Mul(Q.H()).Real(), nil
+}
+
+// Sqrtm returns principal matrix square root X of A, X * X = A and eigen values of X have non-negative real parts
+//	https://en.wikipedia.org/wiki/Square_root_of_a_matrix
+//	symmetric A uses eigen decomposition, otherwise Björck-Hammarling recurrence on complex Schur form A = Q * T * Qᴴ
+func Sqrtm(A *Matrix) *Matrix {
+	res, err := TrySqrtm(A)
+	must(err)
+	return res
+}
+
+// TrySqrtm returns principal matrix square root like `Sqrtm`
+//	ErrNotSquare, ErrDomain for negative real eigen value (the square root is not real),
+//	or ErrSingular if A is singular and has no square root (e.g. [0 1; 0 0])
+func TrySqrtm(A *Matrix) (*Matrix, error) {
+	m, n := A.Dims()
+	if m != n {
+		return nil, squareError("Sqrtm", m, n)
+	}
+	if A.IsSymmetric() {
+		return symmetricFunction("Sqrtm", A, func(lambda float64) (float64, error) {
+			if lambda < 0 {
+				return 0, fmt.Errorf("Sqrtm: %w (negative eigen value %g)", ErrDomain, lambda)
+			}
+			return math.Sqrt(lambda), nil
+		})
+	}
+	T, Q, err := complexSchur("Sqrtm", A)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSpectrum("Sqrtm", T, false); err != nil {
+		return nil, err
+	}
+	U, err := sqrtTriangular(T)
+	if err != nil {
+		return nil, fmt.Errorf("Sqrtm: %w", err)
+	}
+	return Q.Mul(U).Mul(Q.H()).Real(), nil
+}
+
+// Powm returns real power A^p of matrix
+//	integer p uses binary powering (A⁻¹ for negative p), symmetric A uses eigen decomposition,
+//	half-integer p uses A^floor(p) * sqrtm(A), otherwise A^p = A^floor(p) * e^(f * log(A)), f = p - floor(p)
+func Powm(A *Matrix, p float64) *Matrix {
+	res, err := TryPowm(A, p)
+	must(err)
+	return res
+}
+
+// TryPowm returns real power like `Powm`
+//	ErrNotSquare, ErrDomain for non-integer p with negative real eigen value, ErrSingular for negative power of
+//	singular matrix, or for non-integer, non-half-integer power of singular non-symmetric matrix (logarithm does not exist)
+func TryPowm(A *Matrix, p float64) (*Matrix, error) {
+	m, n := A.Dims()
+	if m != n {
+		return nil, squareError("Powm", m, n)
+	}
+	if p == math.Trunc(p) && math.Abs(p) < 1<<31 {
+		k := int(p)
+		if k < 0 {
+			inv, err := LUFactorize(A).TryInverse()
+			if err != nil {
+				return nil, err
+			}
+			A, k = inv, -k
+		}
+		res := IdentityMatrix(n)
+		for pow := A; k > 0; k >>= 1 {
+			if k&1 == 1 {
+				res = res.Mul(pow)
+			}
+			if k > 1 {
+				pow = pow.Mul(pow)
+			}
+		}
+		return res, nil
+	}
+	if A.IsSymmetric() {
+		return symmetricFunction("Powm", A, func(lambda float64) (float64, error) {
+			if lambda < 0 {
+				return 0, fmt.Errorf("Powm: %w (negative eigen value %g)", ErrDomain, lambda)
+			}
+			if lambda == 0 && p < 0 {
+				return 0, fmt.Errorf("Powm: %w (zero eigen value)", ErrSingular)
+			}
+			return math.Pow(lambda, p), nil
+		})
+	}
+	q := math.Floor(p)
+	var F *Matrix
+	var err error
+	if p-q == 0.5 {
+		// square root exists for singular matrices too, logarithm does not
+		F, err = TrySqrtm(A)
+	} else {
+		var L *Matrix
+		if L, err = TryLogm(A); err == nil {
+			F = Expm(L.MulNum(p - q))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if q == 0 {
+		return F, nil
+	}
+	Aq, err := TryPowm(A, q)
+	if err != nil {
+		return nil, err
+	}
+	return Aq.Mul(F), nil
+}
+
+// symmetricFunction returns symmetric V * f(D) * V' of symmetric A = V * D * V'
+//	eigen values within rounding error of zero are treated as zero
+func symmetricFunction(op string, A *Matrix, f func(lambda float64) (float64, error)) (*Matrix, error) {
+	V, D := EigenDecompose(A)
+	n := len(D.Data)
+	scale := 0.
+	for i := 0; i < n; i++ {
+		scale = math.Max(scale, math.Abs(D.Data[i][i]))
+	}
+	tol := float64(n) * math.Pow(2, -52) * scale
+	F := ZeroMatrix(n, n)
+	for i := 0; i < n; i++ {
+		lambda := D.Data[i][i]
+		if math.Abs(lambda) <= tol {
+			lambda = 0
+		}
+		v, err := f(lambda)
+		if err != nil {
+			return nil, err
+		}
+		for j := range V.Data {
+			F.Data[j][i] = V.Data[j][i] * v
+		}
+	}
+	// X = F * V', only upper triangle is computed so that X is exactly symmetric
+	X := ZeroMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s := 0.
+			for k := 0; k < n; k++ {
+				s += F.Data[i][k] * V.Data[j][k]
+			}
+			X.Data[i][j], X.Data[j][i] = s, s
+		}
+	}
+	return X, nil
+}
+
+// complexSchur returns complex Schur decomposition A = Q * T * Qᴴ, T is upper triangular and Q is unitary
+//	it runs QZ iteration of pencil (A, I), which keeps the second matrix identity
+func complexSchur(op string, A *Matrix) (T, Q *ComplexMatrix, err error) {
+	n, _ := A.Dims()
+	T, B := NewComplexMatrix(A, nil), ComplexIdentity(n)
+	Q, Z := ComplexIdentity(n), ComplexIdentity(n)
+	hessenbergTriangular(T.Data, B.Data, Q.Data, Z.Data)
+	if err = qzIterate(T.Data, B.Data, Q.Data, Z.Data); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w (Schur iteration does not converge)", op, ErrBreakdown)
+	}
+	return T, Q, nil
+}
+
+// checkSpectrum checks that no eigen value (diagonal of Schur form T) of real matrix is on the closed negative real axis,
+//	zero eigen value is accepted unless singular is true
+func checkSpectrum(op string, T *ComplexMatrix, singular bool) error {
+	tol := math.Sqrt(math.Pow(2, -52))
+	for k := range T.Data {
+		lambda := T.Data[k][k]
+		if lambda == 0 {
+			if singular {
+				return fmt.Errorf("%s: %w (zero eigen value)", op, ErrSingular)
+			}
+			continue
+		}
+		if real(lambda) < 0 && math.Abs(imag(lambda)) <= tol*cmplx.Abs(lambda) {
+			return fmt.Errorf("%s: %w (negative real eigen value %g)", op, ErrDomain, real(lambda))
+		}
+	}
+	return nil
+}
+
+// sqrtTriangular returns principal square root U of upper triangular T by Björck-Hammarling recurrence
+//	U[i][i] = sqrt(T[i][i]), U[i][j] = (T[i][j] - Σ U[i][k] * U[k][j]) / (U[i][i] + U[j][j]), i < k < j
+func sqrtTriangular(T *ComplexMatrix) (*ComplexMatrix, error) {
+	n, _ := T.Dims()
+	U := ZeroComplexMatrix(n, n)
+	for j := 0; j < n; j++ {
+		U.Data[j][j] = cmplx.Sqrt(T.Data[j][j])
+		for i := j - 1; i >= 0; i-- {
+			s := T.Data[i][j]
+			for k := i + 1; k < j; k++ {
+				s -= U.Data[i][k] * U.Data[k][j]
+			}
+			d := U.Data[i][i] + U.Data[j][j]
+			if d == 0 {
+				if s != 0 {
+					return nil, fmt.Errorf("%w (no square root of singular matrix)", ErrSingular)
+				}
+				continue
+			}
+			U.Data[i][j] = s / d
+		}
+	}
+	return U, nil
+}
+
+// logTriangular returns principal logarithm of upper triangular T by inverse scaling and squaring
+//	log(T) = 2^s * log(I + X), X = T^(1/2^s) - I, log(I + X) = Σ w[k] * X * (I + t[k] * X)⁻¹ is Padé approximant
+//	of degree 8 by Gauss-Legendre quadrature of ∫ X * (I + t * X)⁻¹ dt on [0, 1]
+func logTriangular(T *ComplexMatrix) (*ComplexMatrix, error) {
+	n, _ := T.Dims()
+	R := T
+	s := 0
+	for ; ; s++ {
+		norm := 0.
+		for j := 0; j < n; j++ {
+			col := 0.
+			for i := 0; i <= j; i++ {
+				x := R.Data[i][j]
+				if i == j {
+					x--
+				}
+				col += cmplx.Abs(x)
+			}
+			norm = math.Max(norm, col)
+		}
+		if norm <= 0.25 {
+			break
+		}
+		if s == 64 {
+			return nil, fmt.Errorf("%w (square roots do not converge to identity)", ErrBreakdown)
+		}
+		var err error
+		if R, err = sqrtTriangular(R); err != nil {
+			return nil, err
+		}
+	}
+	X := R.Copy()
+	for i := 0; i < n; i++ {
+		X.Data[i][i]--
+	}
+	nodes, weights := gaussLegendre(8)
+	L := ZeroComplexMatrix(n, n)
+	M := ZeroComplexMatrix(n, n)
+	for k, t := range nodes {
+		// M = I + t * X, solve M * Y = X column by column (M and X commute)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				M.Data[i][j] = complex(t, 0) * X.Data[i][j]
+			}
+			M.Data[i][i]++
+		}
+		w := complex(weights[k], 0)
+		for j := 0; j < n; j++ {
+			y := make([]complex128, j+1)
+			for i := j; i >= 0; i-- {
+				v := X.Data[i][j]
+				for l := i + 1; l <= j; l++ {
+					v -= M.Data[i][l] * y[l]
+				}
+				y[i] = v / M.Data[i][i]
+				L.Data[i][j] += w * y[i]
+			}
+		}
+	}
+	scale := complex(math.Pow(2, float64(s)), 0)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			L.Data[i][j] *= scale
+		}
+		L.Data[i][i] = cmplx.Log(T.Data[i][i])
+	}
+	return L, nil
+}
+
+// gaussLegendre returns m nodes and weights of Gauss-Legendre quadrature on [0, 1]
+func gaussLegendre(m int) (nodes, weights []float64) {
+	nodes, weights = make([]float64, m), make([]float64, m)
+	for i := 0; i < m; i++ {
+		// Newton iteration on Legendre polynomial P_m from Chebyshev-like initial guess
+		x := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(m) + 0.5))
+		var dp float64
+		for iter := 0; iter < 100; iter++ {
+			p0, p1 := 1., x
+			for k := 2; k <= m; k++ {
+				p0, p1 = p1, ((2*float64(k)-1)*x*p1-(float64(k)-1)*p0)/float64(k)
+			}
+			dp = float64(m) * (x*p1 - p0) / (x*x - 1)
+			dx := p1 / dp
+			x -= dx
+			if math.Abs(dx) <= 1e-16 {
+				break
+			}
+		}
+		nodes[i] = (1 - x) / 2
+		weights[i] = 1 / ((1 - x*x) * dp * dp)
+	}
+	return
+}
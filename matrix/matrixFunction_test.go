@@ -0,0 +1,156 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// rotation2D returns counter-clockwise 2D rotation matrix of angle in radians
+func rotation2D(angle float64) *Matrix {
+	return new(Matrix).Init(Data{{math.Cos(angle), -math.Sin(angle)}, {math.Sin(angle), math.Cos(angle)}})
+}
+
+func TestExpm(t *testing.T) {
+	// rotation generator
+	for _, angle := range []float64{0, 1e-3, 0.7, 3, 20} {
+		G := new(Matrix).Init(Data{{0, -angle}, {angle, 0}})
+		if !MEqual(Expm(G), rotation2D(angle)) {
+			t.Fatal(angle, Expm(G))
+		}
+	}
+	// nilpotent and diagonal
+	if !MEqual(Expm(new(Matrix).Init(Data{{0, 1, 0}, {0, 0, 1}, {0, 0, 0}})), new(Matrix).Init(Data{{1, 1, 0.5}, {0, 1, 1}, {0, 0, 1}})) {
+		t.Fail()
+	}
+	if !MEqual(Expm(new(Matrix).Init(Data{{10, 0}, {0, -5}})), new(Matrix).Init(Data{{math.Exp(10), 0}, {0, math.Exp(-5)}})) {
+		t.Fail()
+	}
+	// e^A * e^-A = I for every degree of Padé approximant, e^K is orthogonal for skew-symmetric K
+	for _, scale := range []float64{1e-3, 0.05, 0.3, 1, 10} {
+		A := GenerateRandomSquareMatrix(6).MulNum(scale)
+		if scale <= 1 && !MEqual(Expm(A).Mul(Expm(A.MulNum(-1))), IdentityMatrix(6)) {
+			t.Fatal(scale)
+		}
+		E := Expm(A.Sub(A.T()))
+		if !MEqual(E.T().Mul(E), IdentityMatrix(6)) {
+			t.Fatal(scale)
+		}
+	}
+	if _, err := TryExpm(ZeroMatrix(2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+}
+
+func TestLogm(t *testing.T) {
+	for _, angle := range []float64{0.3, 2, 3.1} {
+		if !MEqual(Logm(rotation2D(angle)), new(Matrix).Init(Data{{0, -angle}, {angle, 0}})) {
+			t.Fatal(angle, Logm(rotation2D(angle)))
+		}
+	}
+	// Jordan block: log([λ 1; 0 λ]) = [log(λ) 1/λ; 0 log(λ)]
+	if !MEqual(Logm(new(Matrix).Init(Data{{3, 1}, {0, 3}})), new(Matrix).Init(Data{{math.Log(3), 1. / 3}, {0, math.Log(3)}})) {
+		t.Fail()
+	}
+	A := GenerateRandomSquareMatrix(5)
+	if !MEqual(Logm(Expm(A)), A) {
+		t.Fail()
+	}
+	S := A.Mul(A.T()).Add(IdentityMatrix(5))
+	if !MEqual(Expm(Logm(S)), S) {
+		t.Fail()
+	}
+	if _, err := TryLogm(new(Matrix).Init(Data{{-1, 1}, {0, 2}})); !errors.Is(err, ErrDomain) {
+		t.Fail()
+	}
+	if _, err := TryLogm(new(Matrix).Init(Data{{0, 1}, {0, 2}})); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+}
+
+func TestSqrtm(t *testing.T) {
+	if !MEqual(Sqrtm(rotation2D(2)), rotation2D(1)) {
+		t.Fail()
+	}
+	// symmetric fast path and general Schur path
+	A := GenerateRandomSquareMatrix(6)
+	S := A.Mul(A.T())
+	if X := Sqrtm(S); !X.IsSymmetric() || !MEqual(X.Mul(X), S) {
+		t.Fail()
+	}
+	A = A.Add(IdentityMatrix(6).MulNum(6))
+	if X := Sqrtm(A); !MEqual(X.Mul(X), A) {
+		t.Fail()
+	}
+	if !MEqual(Sqrtm(new(Matrix).Init(Data{{4, 1}, {0, 4}})), new(Matrix).Init(Data{{2, 0.25}, {0, 2}})) {
+		t.Fail()
+	}
+	if !MEqual(Sqrtm(new(Matrix).Init(Data{{0, 0}, {1, 0}}).Mul(ZeroMatrix(2, 2))), ZeroMatrix(2, 2)) {
+		t.Fail()
+	}
+	if _, err := TrySqrtm(new(Matrix).Init(Data{{0, 1}, {0, 0}})); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if _, err := TrySqrtm(new(Matrix).Init(Data{{1, 0}, {0, -4}})); !errors.Is(err, ErrDomain) {
+		t.Fail()
+	}
+	if _, err := TrySqrtm(new(Matrix).Init(Data{{1, 3}, {0, -4}})); !errors.Is(err, ErrDomain) {
+		t.Fail()
+	}
+}
+
+func TestPowm(t *testing.T) {
+	A := GenerateRandomSquareMatrix(4).Add(IdentityMatrix(4).MulNum(4))
+	if !MEqual(Powm(A, 3), A.Mul(A).Mul(A)) || !MEqual(Powm(A, 0), IdentityMatrix(4)) || !MEqual(Powm(A, -2), A.Inverse().Mul(A.Inverse())) {
+		t.Fail()
+	}
+	if !MEqual(Powm(A, 0.5), Sqrtm(A)) || !MEqual(Powm(A, 2.5), A.Mul(A).Mul(Sqrtm(A))) {
+		t.Fail()
+	}
+	if !MEqual(Powm(rotation2D(1.5), 1./3), rotation2D(0.5)) || !MEqual(Powm(rotation2D(1.5), -0.4), rotation2D(-0.6)) {
+		t.Fail()
+	}
+	S := A.Mul(A.T())
+	if X := Powm(S, 1./3); !MEqual(X.Mul(X).Mul(X), S) {
+		t.Fail()
+	}
+	if _, err := TryPowm(new(Matrix).Init(Data{{1, 0}, {0, 0}}), -1); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	if _, err := TryPowm(new(Matrix).Init(Data{{1, 0}, {0, -1}}), 0.5); !errors.Is(err, ErrDomain) {
+		t.Fail()
+	}
+	// singular non-symmetric matrix: half-integer powers exist, other fractional powers need the logarithm
+	P := new(Matrix).Init(Data{{1, 1}, {0, 0}})
+	if X, err := TryPowm(P, 0.5); err != nil || !MEqual(X, Sqrtm(P)) || !MEqual(X.Mul(X), P) {
+		t.Fatal(err)
+	}
+	if X, err := TryPowm(P, 1.5); err != nil || !MEqual(X, P.Mul(Sqrtm(P))) {
+		t.Fatal(err)
+	}
+	if _, err := TryPowm(P, 1./3); !errors.Is(err, ErrSingular) {
+		t.Fatal(err)
+	}
+}
+
+func TestGaussLegendre(t *testing.T) {
+	// 8 point rule is exact for polynomials up to degree 15
+	nodes, weights := gaussLegendre(8)
+	for d := 0; d <= 15; d++ {
+		s := 0.
+		for k, x := range nodes {
+			s += weights[k] * math.Pow(x, float64(d))
+		}
+		if math.Abs(s-1/float64(d+1)) > 1e-14 {
+			t.Fatal(d, s)
+		}
+	}
+}
+
+func BenchmarkExpm(b *testing.B) {
+	A := GenerateRandomSquareMatrix(50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Expm(A)
+	}
+}
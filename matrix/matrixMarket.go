@@ -0,0 +1,276 @@
+package matrix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// mmFormat is the name of Matrix Market exchange format in errors
+//	https://math.nist.gov/MatrixMarket/formats.html
+//	supported: `coordinate` and `array` formats, `real` / `double` / `integer` / `pattern` fields,
+//	`general` / `symmetric` / `skew-symmetric` symmetries (`hermitian` is the same as `symmetric` for real fields)
+const mmFormat = "MatrixMarket"
+
+// mmMaxDenseSize bounds element number of dense matrix read by `ReadMatrixMarket`, it is allocated from the size line
+//	before any element is read, so a corrupted size must not exhaust memory
+const mmMaxDenseSize = 1 << 28
+
+// mmReader reads Matrix Market text line by line and tracks line number for error reporting
+type mmReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// next returns the next non-empty, non-comment line split into fields, io.EOF if no more
+func (r *mmReader) next() ([]string, error) {
+	for r.s.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.s.Text())
+		if text == "" || text[0] == '%' {
+			continue
+		}
+		return strings.Fields(text), nil
+	}
+	if err := r.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// readMatrixMarket parses Matrix Market text and calls add for every stored element, symmetric parts included
+//	indexes passed to add are 0-based
+//	init may reject the size with an error, it is reported at the size line
+func readMatrixMarket(rd io.Reader, init func(rows, cols int) error, add func(i, j int, v float64)) error {
+	r := &mmReader{s: bufio.NewScanner(rd)}
+	r.s.Buffer(make([]byte, 64*1024), 1024*1024)
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return err
+		}
+		return lineError(mmFormat, 1, "empty input")
+	}
+	r.line++
+	banner := strings.Fields(strings.ToLower(r.s.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return lineError(mmFormat, r.line, "invalid banner %q", r.s.Text())
+	}
+	format, field, symmetry := banner[2], banner[3], banner[4]
+	if format != "coordinate" && format != "array" {
+		return lineError(mmFormat, r.line, "unsupported format %q", format)
+	}
+	switch field {
+	case "real", "double", "integer":
+	case "pattern":
+		if format == "array" {
+			return lineError(mmFormat, r.line, "pattern field requires coordinate format")
+		}
+	default:
+		return lineError(mmFormat, r.line, "unsupported field %q", field)
+	}
+	switch symmetry {
+	case "general", "symmetric", "skew-symmetric", "hermitian":
+	default:
+		return lineError(mmFormat, r.line, "unsupported symmetry %q", symmetry)
+	}
+
+	fields, err := r.next()
+	if err == io.EOF {
+		return lineError(mmFormat, r.line, "missing size line")
+	} else if err != nil {
+		return err
+	}
+	sizeNum := 3
+	if format == "array" {
+		sizeNum = 2
+	}
+	if len(fields) != sizeNum {
+		return lineError(mmFormat, r.line, "size line needs %d integers, got %d fields", sizeNum, len(fields))
+	}
+	size := make([]int, sizeNum)
+	for k, f := range fields {
+		if size[k], err = strconv.Atoi(f); err != nil || size[k] < 0 {
+			return lineError(mmFormat, r.line, "invalid size %q", f)
+		}
+	}
+	rows, cols := size[0], size[1]
+	if symmetry != "general" && rows != cols {
+		return lineError(mmFormat, r.line, "%s matrix must be square, got %d x %d", symmetry, rows, cols)
+	}
+	if cols > 0 && rows > math.MaxInt/cols {
+		return lineError(mmFormat, r.line, "%d x %d matrix is too large", rows, cols)
+	}
+	if format == "coordinate" && size[2] > rows*cols {
+		return lineError(mmFormat, r.line, "%d entries exceed %d x %d matrix", size[2], rows, cols)
+	}
+	if err := init(rows, cols); err != nil {
+		return lineError(mmFormat, r.line, "%v", err)
+	}
+
+	parseValue := func(f string) (float64, error) {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return 0, lineError(mmFormat, r.line, "invalid value %q", f)
+		}
+		return v, nil
+	}
+	mirror := func(i, j int, v float64) {
+		add(i, j, v)
+		if i != j {
+			switch symmetry {
+			case "symmetric", "hermitian":
+				add(j, i, v)
+			case "skew-symmetric":
+				add(j, i, -v)
+			}
+		}
+	}
+
+	if format == "array" {
+		// column major, only lower triangle for symmetric matrices
+		for j := 0; j < cols; j++ {
+			i0 := 0
+			switch symmetry {
+			case "symmetric", "hermitian":
+				i0 = j
+			case "skew-symmetric":
+				i0 = j + 1
+			}
+			for i := i0; i < rows; i++ {
+				fields, err := r.next()
+				if err == io.EOF {
+					return lineError(mmFormat, r.line, "unexpected end of input, missing element (%d, %d)", i+1, j+1)
+				} else if err != nil {
+					return err
+				}
+				if len(fields) != 1 {
+					return lineError(mmFormat, r.line, "array element needs 1 field, got %d", len(fields))
+				}
+				v, err := parseValue(fields[0])
+				if err != nil {
+					return err
+				}
+				mirror(i, j, v)
+			}
+		}
+	} else {
+		nnz := size[2]
+		valueNum := 1
+		if field == "pattern" {
+			valueNum = 0
+		}
+		for k := 0; k < nnz; k++ {
+			fields, err := r.next()
+			if err == io.EOF {
+				return lineError(mmFormat, r.line, "unexpected end of input, got %d of %d entries", k, nnz)
+			} else if err != nil {
+				return err
+			}
+			if len(fields) != 2+valueNum {
+				return lineError(mmFormat, r.line, "entry needs %d fields, got %d", 2+valueNum, len(fields))
+			}
+			i, errI := strconv.Atoi(fields[0])
+			j, errJ := strconv.Atoi(fields[1])
+			if errI != nil || errJ != nil || i < 1 || i > rows || j < 1 || j > cols {
+				return lineError(mmFormat, r.line, "invalid index (%s, %s) for %d x %d matrix", fields[0], fields[1], rows, cols)
+			}
+			v := 1.
+			if valueNum == 1 {
+				if v, err = parseValue(fields[2]); err != nil {
+					return err
+				}
+			}
+			mirror(i-1, j-1, v)
+		}
+	}
+	if _, err := r.next(); err == nil {
+		return lineError(mmFormat, r.line, "unexpected data after the last element")
+	} else if err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// ReadMatrixMarket reads Matrix Market `array` or `coordinate` data into matrix (dense)
+func ReadMatrixMarket(r io.Reader) (*Matrix, error) {
+	var t *Matrix
+	err := readMatrixMarket(r,
+		func(rows, cols int) error {
+			if rows*cols > mmMaxDenseSize {
+				return fmt.Errorf("%d x %d dense matrix exceeds %d elements", rows, cols, mmMaxDenseSize)
+			}
+			t = ZeroMatrix(rows, cols)
+			return nil
+		},
+		func(i, j int, v float64) { t.Data[i][j] += v })
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ReadSparseMatrixMarket reads Matrix Market `coordinate` or `array` data into sparse matrix, duplicated entries are summed
+func ReadSparseMatrixMarket(r io.Reader) (*SparseMatrix, error) {
+	var sm *SparseMatrix
+	err := readMatrixMarket(r,
+		func(rows, cols int) error {
+			sm = ZeroSparseMatrix(rows, cols)
+			return nil
+		},
+		func(i, j int, v float64) { sm.Set(i, j, sm.At(i, j)+v) })
+	if err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// appendMMFloat formats float in the shortest representation that parses back to the same float64
+func appendMMFloat(buf []byte, v float64) []byte {
+	return strconv.AppendFloat(buf, v, 'g', -1, 64)
+}
+
+// WriteMatrixMarket writes matrix (dense) in Matrix Market `array real general` format, values are lossless
+func WriteMatrixMarket(w io.Writer, t *Matrix) error {
+	bw := bufio.NewWriter(w)
+	row, col := t.Dims()
+	if _, err := fmt.Fprintf(bw, "%%%%MatrixMarket matrix array real general\n%d %d\n", row, col); err != nil {
+		return err
+	}
+	buf := make([]byte, 0, 32)
+	for j := 0; j < col; j++ {
+		for i := 0; i < row; i++ {
+			buf = append(appendMMFloat(buf[:0], t.Data[i][j]), '\n')
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteSparseMatrixMarket writes sparse matrix in Matrix Market `coordinate real general` format, entries are row major
+func WriteSparseMatrixMarket(w io.Writer, sm *SparseMatrix) error {
+	bw := bufio.NewWriter(w)
+	c := sm.ToCSR()
+	if _, err := fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real general\n%d %d %d\n", c.Rows, c.Cols, c.NNZ()); err != nil {
+		return err
+	}
+	buf := make([]byte, 0, 64)
+	for i := 0; i < c.Rows; i++ {
+		cols, values := c.RowNonZeros(i)
+		for k, j := range cols {
+			buf = strconv.AppendInt(buf[:0], int64(i+1), 10)
+			buf = append(buf, ' ')
+			buf = strconv.AppendInt(buf, int64(j+1), 10)
+			buf = append(buf, ' ')
+			buf = append(appendMMFloat(buf, values[k]), '\n')
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
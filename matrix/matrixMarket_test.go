@@ -0,0 +1,145 @@
+package matrix
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	coordinate := `%%MatrixMarket matrix coordinate real general
+% a comment
+3 4 4
+
+1 1 1.5
+2 3 -2e3
+3 4 7
+1 1 0.5
+`
+	mat, err := ReadMatrixMarket(strings.NewReader(coordinate))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{2, 0, 0, 0}, {0, 0, -2000, 0}, {0, 0, 0, 7}})) {
+		t.Fail()
+	}
+	sm, err := ReadSparseMatrixMarket(strings.NewReader(coordinate))
+	if err != nil || len(sm.Data) != 3 || !MEqual(sm.ToMatrix(), mat) {
+		t.Fail()
+	}
+
+	array := `%%MatrixMarket matrix array real general
+2 3
+1
+4
+2
+5
+3
+6
+`
+	mat, err = ReadMatrixMarket(strings.NewReader(array))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1, 2, 3}, {4, 5, 6}})) {
+		t.Fail()
+	}
+
+	symmetric := `%%MatrixMarket matrix array real symmetric
+3 3
+1
+2
+3
+4
+5
+6
+`
+	mat, err = ReadMatrixMarket(strings.NewReader(symmetric))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1, 2, 3}, {2, 4, 5}, {3, 5, 6}})) {
+		t.Fail()
+	}
+
+	skew := `%%MatrixMarket matrix coordinate integer skew-symmetric
+2 2 1
+2 1 3
+`
+	mat, err = ReadMatrixMarket(strings.NewReader(skew))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{0, -3}, {3, 0}})) {
+		t.Fail()
+	}
+
+	pattern := `%%MatrixMarket matrix coordinate pattern symmetric
+2 2 2
+1 1
+2 1
+`
+	sm, err = ReadSparseMatrixMarket(strings.NewReader(pattern))
+	if err != nil || !MEqual(sm.ToMatrix(), new(Matrix).Init(Data{{1, 1}, {1, 0}})) {
+		t.Fail()
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	cases := []struct {
+		input string
+		line  string
+	}{
+		{"", "line 1"},
+		{"%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n", "line 1"},
+		{"%%MatrixMarket matrix array pattern general\n1 1\n", "line 1"},
+		{"%%MatrixMarket matrix coordinate real general\n% comment\n2 2\n", "line 3"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n3 1 1\n", "line 4"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n1 2 x\n", "line 4"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n", "line 3"},
+		{"%%MatrixMarket matrix array real general\n1 1\n1\n2\n", "line 4"},
+		{"%%MatrixMarket matrix array real symmetric\n1 2\n", "line 2"},
+		// sizes are checked before allocation
+		{"%%MatrixMarket matrix coordinate real general\n4000000000 4000000000 0\n", "line 2"},
+		{"%%MatrixMarket matrix coordinate real general\n% comment\n100000 100000 0\n", "line 3"},
+		{"%%MatrixMarket matrix coordinate real general\n1 2 3\n1 1 1\n1 2 1\n1 1 1\n", "line 2"},
+	}
+	for _, c := range cases {
+		_, err := ReadMatrixMarket(strings.NewReader(c.input))
+		if !errors.Is(err, ErrFormat) || !strings.Contains(err.Error(), c.line) {
+			t.Errorf("%q: %v", c.input, err)
+		}
+	}
+	// large sparse matrix with few entries is fine
+	sm, err := ReadSparseMatrixMarket(strings.NewReader("%%MatrixMarket matrix coordinate real general\n1000000 1000000 1\n3 4 5\n"))
+	if err != nil || sm.Rows != 1000000 || sm.At(2, 3) != 5 {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteMatrixMarket(t *testing.T) {
+	mat := GenerateRandomMatrix(5, 4)
+	mat.Data[0][0] = math.Pi
+	mat.Data[1][1] = 1e-300
+	var buf bytes.Buffer
+	if err := WriteMatrixMarket(&buf, mat); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ReadMatrixMarket(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// lossless
+	for i := range mat.Data {
+		for j := range mat.Data[i] {
+			if res.Data[i][j] != mat.Data[i][j] {
+				t.Fail()
+			}
+		}
+	}
+
+	sm := GenerateRandomSparseMatrix(30, 20, 40)
+	buf.Reset()
+	if err := WriteSparseMatrixMarket(&buf, sm); err != nil {
+		t.Fatal(err)
+	}
+	res2, err := ReadSparseMatrixMarket(&buf)
+	if err != nil || len(res2.Data) != len(sm.Data) {
+		t.Fatal(err)
+	}
+	for idx, v := range sm.Data {
+		if res2.Data[idx] != v {
+			t.Fail()
+		}
+	}
+}
@@ -183,6 +183,13 @@ func TestMatrix_Det(t *testing.T) {
 	if matB.Det() != 1989 {
 		t.Fail()
 	}
+	// small pivots are not treated as singular
+	if d := IdentityMatrix(3).MulNum(1e-3).Det(); math.Abs(d-1e-9) > 1e-20 {
+		t.Fatal(d)
+	}
+	if d := new(Matrix).Init(Data{{1, 2}, {2, 4}}).Det(); d != 0 {
+		t.Fatal(d)
+	}
 }
 
 func TestMatrix_Inverse(t *testing.T) {
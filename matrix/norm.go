@@ -0,0 +1,180 @@
+package matrix
+
+import (
+	"math"
+)
+
+// Norm1 returns 1-norm (maximum absolute column sum) of matrix
+//	https://en.wikipedia.org/wiki/Matrix_norm#Matrix_norms_induced_by_vector_norms
+func (t *Matrix) Norm1() float64 {
+	_, n := t.Dims()
+	sums := make([]float64, n)
+	for _, row := range t.Data {
+		for j, v := range row {
+			sums[j] += math.Abs(v)
+		}
+	}
+	norm := 0.
+	for _, s := range sums {
+		norm = math.Max(norm, s)
+	}
+	return norm
+}
+
+// NormInf returns ∞-norm (maximum absolute row sum) of matrix
+func (t *Matrix) NormInf() float64 {
+	norm := 0.
+	for _, row := range t.Data {
+		norm = math.Max(norm, row.AbsSum())
+	}
+	return norm
+}
+
+// Norm2 returns 2-norm (spectral norm, the largest singular value) of matrix
+func (t *Matrix) Norm2() float64 {
+	return SingularValues(t).At(0)
+}
+
+// NormNuclear returns nuclear norm (trace norm, sum of singular values) of matrix
+//	https://en.wikipedia.org/wiki/Matrix_norm#Schatten_norms
+func (t *Matrix) NormNuclear() float64 {
+	return SingularValues(t).Sum()
+}
+
+// SingularValues returns min(m, n) singular values of m x n matrix in descending order
+//	it works on the transpose for m < n, as `SVD` needs rows larger or equal to columns
+func SingularValues(t *Matrix) *Vector {
+	m, n := t.Dims()
+	if m < n {
+		t = t.T()
+	}
+	_, S, _ := SVD(t)
+	return S.GetDiagonalElements()
+}
+
+// Cond returns 2-norm condition number σ_max / σ_min by singular values, +Inf for rank deficient matrix
+//	for non-square matrix it is the condition number of least squares problem with min(m, n) singular values
+//	rule of thumb: about log10(Cond()) digits of results of `Inverse` or `LUPSolve` are lost
+func (t *Matrix) Cond() float64 {
+	s := SingularValues(t)
+	if smallest := s.At(s.Length() - 1); smallest > 0 {
+		return s.At(0) / smallest
+	}
+	return math.Inf(1)
+}
+
+// NumericalRank returns number of singular values larger than tol * σ_max
+//	tol <= 0 means max(m, n) * 2^-52, it is more reliable than `Rank` (elimination with absolute `EPS`) and `LUPRank`
+func (t *Matrix) NumericalRank(tol float64) int {
+	m, n := t.Dims()
+	return svdRank(SingularValues(t), m, n, tol)
+}
+
+// svdRank returns number of descending singular values s of m x n matrix larger than tol * s[0], see `NumericalRank`
+func svdRank(s *Vector, m, n int, tol float64) int {
+	if tol <= 0 {
+		tol = float64(MaxInt(m, n)) * math.Pow(2, -52)
+	}
+	rank := 0
+	for _, v := range *s {
+		if v > tol*s.At(0) {
+			rank++
+		}
+	}
+	return rank
+}
+
+// Cond1Est returns estimation of 1-norm condition number ||A||_1 * ||A⁻¹||_1 of square matrix
+//	it costs O(n^3) of LUP decomposition with partial pivoting and O(n^2) for the estimation, +Inf for singular matrix,
+//	see `LUPCondEst`, use `LU.Cond1Est` to check an existing factorization
+func (t *Matrix) Cond1Est() float64 {
+	row, col := t.Dims()
+	if row != col {
+		panic(squareError("Cond1Est", row, col))
+	}
+	return LUFactorize(t).Cond1Est()
+}
+
+// LUPCondEst returns 1-norm condition number estimation by LUP decomposition of matrix
+//	N. J. Higham, FORTRAN codes for estimating the one-norm of a real or complex matrix, ACM TOMS 14(4), 1988 (Hager's method)
+/* INPUT: A,P filled in LUPDecompose; N - dimension; norm1 - 1-norm of the original matrix
+ * OUTPUT: lower bound of ||A||_1 * ||A⁻¹||_1 (+Inf for zero pivot), it is usually within factor 3 of the exact value,
+ *        ||A⁻¹||_1 is estimated by a few solutions of A * x = b and A' * x = b instead of forming the inverse
+ */
+func LUPCondEst(t *Matrix, P *[]int, N int, norm1 float64) float64 {
+	for i := 0; i < N; i++ {
+		if t.At(i, i) == 0 {
+			return math.Inf(1)
+		}
+	}
+	x := make(Vector, N)
+	for i := range x {
+		x[i] = 1 / float64(N)
+	}
+	est := 0.
+	var sign Vector
+	for k := 0; k < 5; k++ {
+		y := LUPSolve(t, P, N, &x)
+		yNorm := y.AbsSum()
+		if k > 0 && yNorm <= est {
+			break
+		}
+		est = yNorm
+		xi := make(Vector, N)
+		for i, v := range *y {
+			xi[i] = 1
+			if v < 0 {
+				xi[i] = -1
+			}
+		}
+		if sign != nil && VEqual(&xi, &sign) {
+			break
+		}
+		sign = xi
+		z := lupSolveT(t, P, N, &xi)
+		j := 0
+		for i, v := range *z {
+			if math.Abs(v) > math.Abs((*z)[j]) {
+				j = i
+			}
+		}
+		if k > 0 && math.Abs((*z)[j]) <= z.Dot(&x) {
+			break
+		}
+		x = make(Vector, N)
+		x[j] = 1
+	}
+	// extra estimate by alternating vector guards against special matrices where the iteration fails
+	for i := range x {
+		x[i] = 1 + float64(i)/math.Max(1, float64(N-1))
+		if i%2 == 1 {
+			x[i] = -x[i]
+		}
+	}
+	est = math.Max(est, 2*LUPSolve(t, P, N, &x).AbsSum()/float64(3*N))
+	return est * norm1
+}
+
+// lupSolveT solves A' * x = b by LUP decomposition P * A = L * U, so A' = U' * L' * P
+func lupSolveT(t *Matrix, P *[]int, N int, b *Vector) *Vector {
+	w := make(Vector, N)
+	// U' * z = b, forward substitution
+	for i := 0; i < N; i++ {
+		w[i] = b.At(i)
+		for k := 0; k < i; k++ {
+			w[i] -= t.At(k, i) * w[k]
+		}
+		w[i] /= t.At(i, i)
+	}
+	// L' * w = z, backward substitution with unit diagonal
+	for i := N - 1; i >= 0; i-- {
+		for k := i + 1; k < N; k++ {
+			w[i] -= t.At(k, i) * w[k]
+		}
+	}
+	x := make(Vector, N)
+	for i := 0; i < N; i++ {
+		x[(*P)[i]] = w[i]
+	}
+	return &x
+}
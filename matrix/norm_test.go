@@ -0,0 +1,82 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNorms(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, -7}, {-2, -3}})
+	if a.Norm1() != 10 || a.NormInf() != 8 {
+		t.Fail()
+	}
+	// singular values 3 * sqrt(5), sqrt(5)
+	a = new(Matrix).Init(Data{{3, 0}, {4, 5}})
+	if !FloatEqual(a.Norm2(), 3*math.Sqrt(5)) || !FloatEqual(a.NormNuclear(), 4*math.Sqrt(5)) || !FloatEqual(a.Cond(), 3) {
+		t.Fail()
+	}
+	if !VEqual(SingularValues(a), &Vector{3 * math.Sqrt(5), math.Sqrt(5)}) {
+		t.Fail()
+	}
+	// wide matrix and bounds between norms
+	a = GenerateRandomMatrix(4, 9)
+	if !FloatEqual(a.Norm2(), a.T().Norm2()) || a.Norm2() > a.Norm()+1e-12 || a.Norm() > 2*a.Norm2()+1e-12 {
+		t.Fail()
+	}
+	if a.Norm2() > math.Sqrt(a.Norm1()*a.NormInf())+1e-12 || !FloatEqual(a.Norm1(), a.T().NormInf()) {
+		t.Fail()
+	}
+	if !math.IsInf(new(Matrix).Init(Data{{1, 2}, {2, 4}}).Cond(), 1) {
+		t.Fail()
+	}
+}
+
+func TestNumericalRank(t *testing.T) {
+	a := new(Matrix).Init(Data{{1, 2, 3, 4}, {2, 4, 6, 8.000001}, {1, 0, 1, 0}})
+	if a.NumericalRank(0) != 3 || a.NumericalRank(1e-6) != 2 || a.T().NumericalRank(1e-6) != 2 {
+		t.Fail()
+	}
+	if ZeroMatrix(3, 3).NumericalRank(0) != 0 || IdentityMatrix(5).NumericalRank(0) != 5 {
+		t.Fail()
+	}
+}
+
+func TestCond1Est(t *testing.T) {
+	for k := 0; k < 10; k++ {
+		// the estimate is a lower bound, usually but not always within a factor 3 of the exact value
+		a := GenerateRandomSquareMatrix(8)
+		exact := a.Norm1() * a.Inverse().Norm1()
+		if est := a.Cond1Est(); est > exact*(1+1e-10) || est < exact/10 {
+			t.Fatal(est, exact)
+		}
+	}
+	// Hilbert matrix is ill-conditioned
+	h := ZeroMatrix(6, 6)
+	for i := range h.Data {
+		for j := range h.Data[i] {
+			h.Data[i][j] = 1 / float64(i+j+1)
+		}
+	}
+	if est := h.Cond1Est(); est < 2.9e7/3 || est > 2.91e7 {
+		t.Fatal(est)
+	}
+	// zero leading pivots need row exchanges
+	p := new(Matrix).Init(Data{{10, 0, 0}, {0, 0, 1}, {0, 1, 0}})
+	if !FloatEqual(p.Cond1Est(), 10) || !math.IsInf(new(Matrix).Init(Data{{1, 2}, {2, 4}}).Cond1Est(), 1) {
+		t.Fail()
+	}
+	a := new(Matrix).Init(Data{{4, 1, 0}, {1, 3, 1}, {0, 1, 2}})
+	nt, P := LUPDecompose(a, 3, EPS)
+	if !FloatEqual(LUPCondEst(nt, P, 3, a.Norm1()), a.Norm1()*a.Inverse().Norm1()) {
+		t.Fail()
+	}
+}
+
+func TestLUPSolveT(t *testing.T) {
+	a := GenerateRandomSquareMatrix(6)
+	b := GenerateRandomVector(6)
+	nt, P := decomposeLU(a)
+	if !VEqual(a.T().MulVec(lupSolveT(nt, P, 6, b)), b) || !VEqual(a.MulVec(LUPSolve(nt, P, 6, b)), b) {
+		t.Fail()
+	}
+}
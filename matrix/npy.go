@@ -0,0 +1,471 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// npyFormat is the name of NumPy binary format in errors
+//	https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+//	numeric dtypes (f4, f8, i1-i8, u1-u8 in either byte order) are read as float64, only '<f8' is written
+const npyFormat = "npy"
+
+var (
+	npyMagic         = []byte("\x93NUMPY")
+	npyDescrRe       = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+	npyFortranRe     = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShapeRe       = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+	npyHeaderAlign   = 64
+	npyMaxHeaderSize = 1 << 20
+)
+
+// npyArray is a decoded npy array, numeric data is converted to float64, byte string data is kept in raw
+type npyArray struct {
+	shape   []int
+	fortran bool
+	data    []float64
+	raw     []byte
+}
+
+// readNpyArray reads one npy array from r
+func readNpyArray(r io.Reader) (*npyArray, error) {
+	var pre [10]byte
+	if n, err := io.ReadFull(r, pre[:8]); err != nil {
+		return nil, offsetError(npyFormat, int64(n), "truncated magic string: %v", err)
+	}
+	if !bytes.Equal(pre[:6], npyMagic) {
+		return nil, offsetError(npyFormat, 0, "invalid magic string %q", pre[:6])
+	}
+	offset := int64(8)
+	var headerLen int
+	switch pre[6] {
+	case 1:
+		if _, err := io.ReadFull(r, pre[8:10]); err != nil {
+			return nil, offsetError(npyFormat, offset, "truncated header length: %v", err)
+		}
+		headerLen = int(binary.LittleEndian.Uint16(pre[8:10]))
+		offset += 2
+	case 2, 3:
+		var l [4]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return nil, offsetError(npyFormat, offset, "truncated header length: %v", err)
+		}
+		headerLen = int(binary.LittleEndian.Uint32(l[:]))
+		offset += 4
+	default:
+		return nil, offsetError(npyFormat, 6, "unsupported version %d.%d", pre[6], pre[7])
+	}
+	if headerLen > npyMaxHeaderSize {
+		return nil, offsetError(npyFormat, offset, "header length %d too large", headerLen)
+	}
+	header := make([]byte, headerLen)
+	if n, err := io.ReadFull(r, header); err != nil {
+		return nil, offsetError(npyFormat, offset+int64(n), "truncated header: %v", err)
+	}
+	h := string(header)
+	descr := npyDescrRe.FindStringSubmatch(h)
+	fortran := npyFortranRe.FindStringSubmatch(h)
+	shape := npyShapeRe.FindStringSubmatch(h)
+	if descr == nil || fortran == nil || shape == nil {
+		return nil, offsetError(npyFormat, offset, "invalid header %q", strings.TrimSpace(h))
+	}
+	a := &npyArray{fortran: fortran[1] == "True"}
+	size := 1 // number of elements, checked for overflow while multiplying
+	for _, s := range strings.Split(shape[1], ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		d, err := strconv.Atoi(s)
+		if err != nil || d < 0 || (d > 0 && size > math.MaxInt/d) {
+			return nil, offsetError(npyFormat, offset, "invalid shape (%s)", shape[1])
+		}
+		size *= d
+		a.shape = append(a.shape, d)
+	}
+	offset += int64(headerLen)
+
+	dt := descr[1]
+	if len(dt) < 3 {
+		return nil, offsetError(npyFormat, offset-int64(headerLen), "unsupported dtype %q", dt)
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if dt[0] == '>' {
+		order = binary.BigEndian
+	}
+	kind := dt[1]
+	itemSize, err := strconv.Atoi(dt[2:])
+	if err != nil || itemSize <= 0 || strings.IndexByte("<>|=", dt[0]) < 0 {
+		return nil, offsetError(npyFormat, offset-int64(headerLen), "unsupported dtype %q", dt)
+	}
+	if size > math.MaxInt/itemSize {
+		return nil, offsetError(npyFormat, offset-int64(headerLen), "shape (%s) of %q is too large", shape[1], dt)
+	}
+	// the buffer grows with data actually read, so a truncated file with huge shape fails without allocating it
+	var buf bytes.Buffer
+	if n, err := io.CopyN(&buf, r, int64(size*itemSize)); err != nil {
+		return nil, offsetError(npyFormat, offset+n, "truncated data, expect %d bytes: %v", size*itemSize, err)
+	}
+	payload := buf.Bytes()
+	if kind == 'S' {
+		a.raw = payload
+		return a, nil
+	}
+	var decode func(b []byte) float64
+	switch {
+	case kind == 'f' && itemSize == 8:
+		decode = func(b []byte) float64 { return math.Float64frombits(order.Uint64(b)) }
+	case kind == 'f' && itemSize == 4:
+		decode = func(b []byte) float64 { return float64(math.Float32frombits(order.Uint32(b))) }
+	case kind == 'i' && itemSize == 1:
+		decode = func(b []byte) float64 { return float64(int8(b[0])) }
+	case kind == 'i' && itemSize == 2:
+		decode = func(b []byte) float64 { return float64(int16(order.Uint16(b))) }
+	case kind == 'i' && itemSize == 4:
+		decode = func(b []byte) float64 { return float64(int32(order.Uint32(b))) }
+	case kind == 'i' && itemSize == 8:
+		decode = func(b []byte) float64 { return float64(int64(order.Uint64(b))) }
+	case kind == 'u' && itemSize == 1:
+		decode = func(b []byte) float64 { return float64(b[0]) }
+	case kind == 'u' && itemSize == 2:
+		decode = func(b []byte) float64 { return float64(order.Uint16(b)) }
+	case kind == 'u' && itemSize == 4:
+		decode = func(b []byte) float64 { return float64(order.Uint32(b)) }
+	case kind == 'u' && itemSize == 8:
+		decode = func(b []byte) float64 { return float64(order.Uint64(b)) }
+	default:
+		return nil, offsetError(npyFormat, offset-int64(headerLen), "unsupported dtype %q", dt)
+	}
+	a.data = make([]float64, size)
+	for k := range a.data {
+		a.data[k] = decode(payload[k*itemSize:])
+	}
+	return a, nil
+}
+
+// toMatrix converts 0-, 1- (as a row) or 2-dimensional numeric array to matrix
+func (a *npyArray) toMatrix() (*Matrix, error) {
+	if a.data == nil && a.raw != nil {
+		return nil, fmt.Errorf("%s: %w: byte string array is not numeric", npyFormat, ErrFormat)
+	}
+	var rows, cols int
+	switch len(a.shape) {
+	case 0:
+		rows, cols = 1, 1
+	case 1:
+		rows, cols = 1, a.shape[0]
+	case 2:
+		rows, cols = a.shape[0], a.shape[1]
+	default:
+		return nil, fmt.Errorf("%s: %w: %d-dimensional array can not be a matrix", npyFormat, ErrFormat, len(a.shape))
+	}
+	t := ZeroMatrix(rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if a.fortran {
+				t.Data[i][j] = a.data[j*rows+i]
+			} else {
+				t.Data[i][j] = a.data[i*cols+j]
+			}
+		}
+	}
+	return t, nil
+}
+
+// writeNpyArray writes npy version 1.0 (2.0 if header is too long) with given descr, shape and payload
+func writeNpyArray(w io.Writer, descr string, fortran bool, shape []int, payload []byte) error {
+	dims := make([]string, len(shape))
+	for k, d := range shape {
+		dims[k] = strconv.Itoa(d)
+	}
+	shapeStr := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeStr += ","
+	}
+	order := "False"
+	if fortran {
+		order = "True"
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': (%s), }", descr, order, shapeStr)
+	pre := []byte{1, 0}
+	lenSize := 2
+	if len(header)+len(npyMagic)+2+lenSize+1 > math.MaxUint16 {
+		pre, lenSize = []byte{2, 0}, 4
+	}
+	// pad with spaces and end with newline so that data starts at a multiple of 64 bytes
+	total := len(npyMagic) + 2 + lenSize + len(header) + 1
+	header += strings.Repeat(" ", (npyHeaderAlign-total%npyHeaderAlign)%npyHeaderAlign) + "\n"
+	buf := append(append([]byte{}, npyMagic...), pre...)
+	if lenSize == 2 {
+		buf = append(buf, 0, 0)
+		binary.LittleEndian.PutUint16(buf[len(buf)-2:], uint16(len(header)))
+	} else {
+		buf = append(buf, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(buf[len(buf)-4:], uint32(len(header)))
+	}
+	buf = append(buf, header...)
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// float64Payload encodes float64 values in little endian
+func float64Payload(values []float64) []byte {
+	payload := make([]byte, 8*len(values))
+	for k, v := range values {
+		binary.LittleEndian.PutUint64(payload[8*k:], math.Float64bits(v))
+	}
+	return payload
+}
+
+// ReadNpy reads NumPy .npy data into matrix, C and Fortran order are both supported,
+// 1-dimensional array becomes a single row matrix
+func ReadNpy(r io.Reader) (*Matrix, error) {
+	a, err := readNpyArray(r)
+	if err != nil {
+		return nil, err
+	}
+	return a.toMatrix()
+}
+
+// WriteNpy writes matrix as NumPy .npy 2-dimensional '<f8' array in C (row major) or Fortran (column major) order
+func WriteNpy(w io.Writer, t *Matrix, fortranOrder bool) error {
+	row, col := t.Dims()
+	values := make([]float64, 0, row*col)
+	if fortranOrder {
+		for j := 0; j < col; j++ {
+			for i := 0; i < row; i++ {
+				values = append(values, t.Data[i][j])
+			}
+		}
+	} else {
+		for i := range t.Data {
+			values = append(values, t.Data[i]...)
+		}
+	}
+	return writeNpyArray(w, "<f8", fortranOrder, []int{row, col}, float64Payload(values))
+}
+
+// readNpzArrays reads all arrays inside a NumPy .npz (zip) archive, keyed by name without ".npy" suffix
+func readNpzArrays(r io.ReaderAt, size int64) (map[string]*npyArray, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("npz: %w: %v", ErrFormat, err)
+	}
+	arrays := make(map[string]*npyArray, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("npz: %w: %s: %v", ErrFormat, f.Name, err)
+		}
+		a, err := readNpyArray(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("npz: %s: %w", f.Name, err)
+		}
+		arrays[strings.TrimSuffix(f.Name, ".npy")] = a
+	}
+	return arrays, nil
+}
+
+// ReadNpz reads all arrays of a NumPy .npz archive (`numpy.savez` or `numpy.savez_compressed`) into matrices
+func ReadNpz(r io.ReaderAt, size int64) (map[string]*Matrix, error) {
+	arrays, err := readNpzArrays(r, size)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*Matrix, len(arrays))
+	for name, a := range arrays {
+		if res[name], err = a.toMatrix(); err != nil {
+			return nil, fmt.Errorf("npz: %s: %w", name, err)
+		}
+	}
+	return res, nil
+}
+
+// npzWriter writes named npy entries into a zip archive
+type npzWriter struct {
+	zw *zip.Writer
+}
+
+func (nw npzWriter) write(name, descr string, shape []int, payload []byte) error {
+	f, err := nw.zw.Create(name + ".npy")
+	if err != nil {
+		return err
+	}
+	return writeNpyArray(f, descr, false, shape, payload)
+}
+
+// WriteNpz writes matrices as a NumPy .npz archive (uncompressed, like `numpy.savez`), keys are array names
+func WriteNpz(w io.Writer, arrays map[string]*Matrix) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err = WriteNpy(f, arrays[name], false); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// ReadSparseNpz reads a SciPy sparse matrix archive (`scipy.sparse.save_npz`) in csr, csc or coo format into sparse matrix
+func ReadSparseNpz(r io.ReaderAt, size int64) (*SparseMatrix, error) {
+	arrays, err := readNpzArrays(r, size)
+	if err != nil {
+		return nil, err
+	}
+	get := func(name string) ([]float64, error) {
+		a, ok := arrays[name]
+		if !ok || a.data == nil {
+			return nil, fmt.Errorf("npz: %w: missing numeric array %q", ErrFormat, name)
+		}
+		return a.data, nil
+	}
+	format, ok := arrays["format"]
+	if !ok || format.raw == nil {
+		return nil, fmt.Errorf("npz: %w: missing byte string array \"format\"", ErrFormat)
+	}
+	shape, err := get("shape")
+	if err != nil {
+		return nil, err
+	}
+	data, err := get("data")
+	if err != nil {
+		return nil, err
+	}
+	if len(shape) != 2 {
+		return nil, fmt.Errorf("npz: %w: shape has %d dimensions", ErrFormat, len(shape))
+	}
+	rows, okRows := npzIndex(shape[0])
+	cols, okCols := npzIndex(shape[1])
+	if !okRows || !okCols {
+		return nil, fmt.Errorf("npz: %w: invalid shape %v", ErrFormat, shape)
+	}
+	sm := ZeroSparseMatrix(rows, cols)
+	add := func(i, j int, v float64) error {
+		if i < 0 || i >= rows || j < 0 || j >= cols {
+			return fmt.Errorf("npz: %w: index (%d, %d) out of %d x %d", ErrFormat, i, j, rows, cols)
+		}
+		sm.Set(i, j, sm.At(i, j)+v)
+		return nil
+	}
+	switch f := string(bytes.TrimRight(format.raw, "\x00")); f {
+	case "csr", "csc":
+		indices, err := get("indices")
+		if err != nil {
+			return nil, err
+		}
+		indptr, err := get("indptr")
+		if err != nil {
+			return nil, err
+		}
+		major := rows
+		if f == "csc" {
+			major = cols
+		}
+		if len(indptr) != major+1 || len(indices) != len(data) || indptr[0] != 0 {
+			return nil, fmt.Errorf("npz: %w: inconsistent %s arrays", ErrFormat, f)
+		}
+		// every slice indices[indptr[i]:indptr[i+1]] must be in range before any of them is read
+		for i := 0; i < major; i++ {
+			if p, ok := npzIndex(indptr[i+1]); !ok || p > len(data) || indptr[i+1] < indptr[i] {
+				return nil, fmt.Errorf("npz: %w: invalid %s indptr[%d] %v", ErrFormat, f, i+1, indptr[i+1])
+			}
+		}
+		for p, x := range indices {
+			if _, ok := npzIndex(x); !ok {
+				return nil, fmt.Errorf("npz: %w: invalid %s indices[%d] %v", ErrFormat, f, p, x)
+			}
+		}
+		for i := 0; i < major; i++ {
+			for p := int(indptr[i]); p < int(indptr[i+1]); p++ {
+				r, c := i, int(indices[p])
+				if f == "csc" {
+					r, c = c, i
+				}
+				if err := add(r, c, data[p]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case "coo":
+		row, err := get("row")
+		if err != nil {
+			return nil, err
+		}
+		col, err := get("col")
+		if err != nil {
+			return nil, err
+		}
+		if len(row) != len(data) || len(col) != len(data) {
+			return nil, fmt.Errorf("npz: %w: inconsistent coo arrays", ErrFormat)
+		}
+		for k, v := range data {
+			i, okRow := npzIndex(row[k])
+			j, okCol := npzIndex(col[k])
+			if !okRow || !okCol {
+				return nil, fmt.Errorf("npz: %w: invalid coo index (%v, %v)", ErrFormat, row[k], col[k])
+			}
+			if err := add(i, j, v); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("npz: %w: unsupported sparse format %q", ErrFormat, f)
+	}
+	return sm, nil
+}
+
+// npzIndex converts index array element (read as float64) to int, false if it is negative, fractional or too large
+func npzIndex(x float64) (int, bool) {
+	if x < 0 || x != math.Trunc(x) || x >= float64(math.MaxInt) {
+		return 0, false
+	}
+	return int(x), true
+}
+
+// WriteSparseNpz writes sparse matrix as a SciPy csr archive readable by `scipy.sparse.load_npz`
+func WriteSparseNpz(w io.Writer, sm *SparseMatrix) error {
+	c := sm.ToCSR()
+	intPayload := func(values []int) []byte {
+		payload := make([]byte, 8*len(values))
+		for k, v := range values {
+			binary.LittleEndian.PutUint64(payload[8*k:], uint64(v))
+		}
+		return payload
+	}
+	nw := npzWriter{zw: zip.NewWriter(w)}
+	if err := nw.write("indices", "<i8", []int{len(c.ColIdx)}, intPayload(c.ColIdx)); err != nil {
+		return err
+	}
+	if err := nw.write("indptr", "<i8", []int{len(c.RowPtr)}, intPayload(c.RowPtr)); err != nil {
+		return err
+	}
+	if err := nw.write("format", "|S3", nil, []byte("csr")); err != nil {
+		return err
+	}
+	if err := nw.write("shape", "<i8", []int{2}, intPayload([]int{c.Rows, c.Cols})); err != nil {
+		return err
+	}
+	if err := nw.write("data", "<f8", []int{len(c.Values)}, float64Payload(c.Values)); err != nil {
+		return err
+	}
+	return nw.zw.Close()
+}
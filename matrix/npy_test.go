@@ -0,0 +1,195 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// npyFixture builds npy bytes the way numpy.save does
+func npyFixture(header string, payload []byte) []byte {
+	total := 10 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+	buf := append([]byte("\x93NUMPY\x01\x00"), byte(len(header)), byte(len(header)>>8))
+	return append(append(buf, header...), payload...)
+}
+
+func TestReadNpy(t *testing.T) {
+	// numpy.arange(6.).reshape(2, 3)
+	c := npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }", float64Payload([]float64{0, 1, 2, 3, 4, 5}))
+	if (len(c)-48)%64 != 0 {
+		t.Fail()
+	}
+	mat, err := ReadNpy(bytes.NewReader(c))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{0, 1, 2}, {3, 4, 5}})) {
+		t.Fatal(err)
+	}
+	// numpy.asfortranarray of the same
+	f := npyFixture("{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3), }", float64Payload([]float64{0, 3, 1, 4, 2, 5}))
+	mat, err = ReadNpy(bytes.NewReader(f))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{0, 1, 2}, {3, 4, 5}})) {
+		t.Fatal(err)
+	}
+	// big endian float64 and int32 vectors
+	be := make([]byte, 16)
+	binary.BigEndian.PutUint64(be, math.Float64bits(1.5))
+	binary.BigEndian.PutUint64(be[8:], math.Float64bits(-2))
+	mat, err = ReadNpy(bytes.NewReader(npyFixture("{'descr': '>f8', 'fortran_order': False, 'shape': (2,), }", be)))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1.5, -2}})) {
+		t.Fatal(err)
+	}
+	i4 := []byte{1, 0, 0, 0, 0xff, 0xff, 0xff, 0xff}
+	mat, err = ReadNpy(bytes.NewReader(npyFixture("{'descr': '<i4', 'fortran_order': False, 'shape': (2, 1), }", i4)))
+	if err != nil || !MEqual(mat, new(Matrix).Init(Data{{1}, {-1}})) {
+		t.Fatal(err)
+	}
+}
+
+func TestReadNpyErrors(t *testing.T) {
+	cases := []struct {
+		input  []byte
+		offset string
+	}{
+		{[]byte("\x93NUMP"), "offset 5"},
+		{[]byte("NUMPY!\x01\x00\x00\x00"), "offset 0"},
+		{[]byte("\x93NUMPY\x09\x00\x00\x00"), "offset 6"},
+		{npyFixture("{'descr': '<c16', 'fortran_order': False, 'shape': (1,), }", make([]byte, 16)), "offset 10"},
+		{npyFixture("{'descr': '<f8', 'shape': (1,), }", make([]byte, 8)), "offset 10"},
+		{npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 2), }", make([]byte, 20)), "offset 148"},
+		// element count or byte size overflows int
+		{npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (4611686018427387904, 4), }", nil), "offset 10"},
+		{npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (1152921504606846976,), }", nil), "offset 10"},
+		// huge but valid shape of truncated file fails at the end of input
+		{npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (150000000,), }", make([]byte, 16)), "offset 144"},
+	}
+	for _, c := range cases {
+		_, err := ReadNpy(bytes.NewReader(c.input))
+		if !errors.Is(err, ErrFormat) || !strings.Contains(err.Error(), c.offset) {
+			t.Errorf("%q: %v", c.input, err)
+		}
+	}
+	if _, err := ReadNpy(bytes.NewReader(npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 1), }", make([]byte, 8)))); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+}
+
+func TestWriteNpy(t *testing.T) {
+	mat := GenerateRandomMatrix(7, 3)
+	mat.Data[2][1] = math.Inf(-1)
+	for _, fortran := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := WriteNpy(&buf, mat, fortran); err != nil {
+			t.Fatal(err)
+		}
+		// header is aligned
+		headerLen := int(binary.LittleEndian.Uint16(buf.Bytes()[8:10]))
+		if (10+headerLen)%64 != 0 || buf.Len() != 10+headerLen+8*21 {
+			t.Fail()
+		}
+		res, err := ReadNpy(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range mat.Data {
+			for j := range mat.Data[i] {
+				if math.Float64bits(res.Data[i][j]) != math.Float64bits(mat.Data[i][j]) {
+					t.Fail()
+				}
+			}
+		}
+	}
+}
+
+func TestNpz(t *testing.T) {
+	arrays := map[string]*Matrix{"a": GenerateRandomMatrix(3, 4), "b": GenerateRandomMatrix(1, 5)}
+	var buf bytes.Buffer
+	if err := WriteNpz(&buf, arrays); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ReadNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil || len(res) != 2 || !MEqual(res["a"], arrays["a"]) || !MEqual(res["b"], arrays["b"]) {
+		t.Fatal(err)
+	}
+	if _, err := ReadNpz(bytes.NewReader([]byte("not a zip")), 9); !errors.Is(err, ErrFormat) {
+		t.Fail()
+	}
+}
+
+func TestSparseNpz(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(20, 30, 50)
+	var buf bytes.Buffer
+	if err := WriteSparseNpz(&buf, sm); err != nil {
+		t.Fatal(err)
+	}
+	res, err := ReadSparseNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil || res.Rows != 20 || res.Cols != 30 || len(res.Data) != len(sm.Data) {
+		t.Fatal(err)
+	}
+	for idx, v := range sm.Data {
+		if res.Data[idx] != v {
+			t.Fail()
+		}
+	}
+
+	// scipy coo archive with int32 indices
+	buf.Reset()
+	zw := zip.NewWriter(&buf)
+	add := func(name, header string, payload []byte) {
+		f, _ := zw.Create(name + ".npy")
+		_, _ = f.Write(npyFixture(header, payload))
+	}
+	add("format", "{'descr': '|S3', 'fortran_order': False, 'shape': (), }", []byte("coo"))
+	add("shape", "{'descr': '<i8', 'fortran_order': False, 'shape': (2,), }", []byte{2, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0})
+	add("row", "{'descr': '<i4', 'fortran_order': False, 'shape': (2,), }", []byte{0, 0, 0, 0, 1, 0, 0, 0})
+	add("col", "{'descr': '<i4', 'fortran_order': False, 'shape': (2,), }", []byte{1, 0, 0, 0, 0, 0, 0, 0})
+	add("data", "{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }", float64Payload([]float64{3, 4}))
+	_ = zw.Close()
+	res, err = ReadSparseNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil || !MEqual(res.ToMatrix(), new(Matrix).Init(Data{{0, 3}, {4, 0}})) {
+		t.Fatal(err)
+	}
+}
+
+func TestSparseNpzMalformed(t *testing.T) {
+	// 2 x 2 csr archive with given indptr and indices, data has one element per index
+	csr := func(indptr, indices []float64) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		add := func(name string, values []float64) {
+			f, _ := zw.Create(name + ".npy")
+			header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%d,), }", len(values))
+			_, _ = f.Write(npyFixture(header, float64Payload(values)))
+		}
+		f, _ := zw.Create("format.npy")
+		_, _ = f.Write(npyFixture("{'descr': '|S3', 'fortran_order': False, 'shape': (), }", []byte("csr")))
+		add("shape", []float64{2, 2})
+		add("indptr", indptr)
+		add("indices", indices)
+		add("data", make([]float64, len(indices)))
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+	valid := csr([]float64{0, 1, 2}, []float64{1, 0})
+	if _, err := ReadSparseNpz(bytes.NewReader(valid), int64(len(valid))); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ indptr, indices []float64 }{
+		{[]float64{0, 5, 1}, []float64{0}},      // decreasing, beyond data
+		{[]float64{1, 1, 1}, []float64{0}},      // not starting at 0
+		{[]float64{0, -1, 1}, []float64{0}},     // negative
+		{[]float64{0, 0.5, 1}, []float64{0}},    // fractional
+		{[]float64{0, 1, 2}, []float64{-1, 0}},  // negative index
+		{[]float64{0, 1, 2}, []float64{0.5, 0}}, // fractional index
+		{[]float64{0, 1, 2}, []float64{math.NaN(), 0}},
+	} {
+		data := csr(c.indptr, c.indices)
+		if _, err := ReadSparseNpz(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrFormat) {
+			t.Error(c, err)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"sort"
+)
+
+// TransposeOperator is a `LinearOperator` whose transpose can also be multiplied with a vector,
+// `Matrix`, `SparseMatrix`, `CSR`, `CSC` and `FuncOperator` all satisfy it
+type TransposeOperator interface {
+	LinearOperator
+	MulVecT(v *Vector) *Vector
+}
+
+var (
+	_ TransposeOperator = (*Matrix)(nil)
+	_ TransposeOperator = (*SparseMatrix)(nil)
+	_ TransposeOperator = (*CSR)(nil)
+	_ TransposeOperator = (*CSC)(nil)
+	_ TransposeOperator = (*FuncOperator)(nil)
+)
+
+// FuncOperator is a matrix-free operator given by its matrix-vector product functions
+type FuncOperator struct {
+	Rows, Cols int
+	Mul        func(v *Vector) *Vector // A * v
+	MulT       func(v *Vector) *Vector // A.T() * v, optional, only needed as `TransposeOperator`
+}
+
+// Dims returns numbers of rows and columns
+func (f *FuncOperator) Dims() (int, int) {
+	return f.Rows, f.Cols
+}
+
+// MulVec returns A * v
+func (f *FuncOperator) MulVec(v *Vector) *Vector {
+	return f.Mul(v)
+}
+
+// MulVecT returns A.T() * v, panics if `MulT` is not given
+func (f *FuncOperator) MulVecT(v *Vector) *Vector {
+	if f.MulT == nil {
+		panic("FuncOperator: MulT is not given")
+	}
+	return f.MulT(v)
+}
+
+// EigenTarget tells partial eigen solvers which eigen values to look for
+type EigenTarget int
+
+const (
+	LargestMagnitude EigenTarget = iota // largest |lambda|
+	LargestReal                         // largest real part, largest algebraic for symmetric matrix
+	SmallestReal                        // smallest real part, smallest algebraic for symmetric matrix
+)
+
+// before reports whether eigen value a is wanted before b, of complex conjugate pair the positive imaginary part is the first
+func (t EigenTarget) before(a, b complex128) bool {
+	switch t {
+	case LargestReal:
+		if real(a) != real(b) {
+			return real(a) > real(b)
+		}
+	case SmallestReal:
+		if real(a) != real(b) {
+			return real(a) < real(b)
+		}
+	default:
+		if ma, mb := cmplx.Abs(a), cmplx.Abs(b); ma != mb {
+			return ma > mb
+		}
+	}
+	return imag(a) > imag(b)
+}
+
+// EigenSettings controls partial eigen solvers `Lanczos` and `Arnoldi`, zero values mean defaults
+type EigenSettings struct {
+	Target  EigenTarget // which eigen values, default LargestMagnitude
+	Tol     float64     // Ritz pair converges if ||A x - lambda x|| <= Tol * max(|lambda|, eps^(2/3)), default 1e-10
+	MaxIter int         // maximum restarts, default 300
+	NCV     int         // dimension of Krylov subspace (number of kept vectors), default min(n, max(2k + 1, 20))
+	V0      *Vector     // start vector, default a reproducible pseudo random vector
+}
+
+// LanczosResult holds k eigen pairs of symmetric operator found by `Lanczos`, the most wanted first
+type LanczosResult struct {
+	Values    *Vector   // eigen values
+	Vectors   *Matrix   // orthonormal eigen vectors in columns, n x k
+	Residuals []float64 // ||A x - lambda x|| of each pair
+	Restarts  int       // restarts used
+	MatVecs   int       // matrix-vector products used
+	Converged bool      // whether all k pairs reach tolerance
+}
+
+// ArnoldiResult holds k eigen pairs of general operator found by `Arnoldi`, the most wanted first
+type ArnoldiResult struct {
+	Values    []complex128   // eigen values
+	Vectors   *ComplexMatrix // eigen vectors in columns, n x k, unit 2-norm
+	Residuals []float64      // ||A x - lambda x|| of each pair
+	Restarts  int            // restarts used
+	MatVecs   int            // matrix-vector products used
+	Converged bool           // whether all k pairs reach tolerance
+}
+
+// Lanczos finds k eigen pairs of symmetric operator by thick-restart Lanczos with full re-orthogonalization
+//	https://en.wikipedia.org/wiki/Lanczos_algorithm
+//	only matrix-vector products are used, so a large sparse matrix or `FuncOperator` never has to be densified
+//	A must be symmetric, it is not checked; not converged result is returned with Converged false
+//	ErrNotSquare, ErrDimensionMismatch if k is not in [1, n] or V0 has wrong length, ErrZeroVector for zero V0
+func Lanczos(a LinearOperator, k int, settings *EigenSettings) (*LanczosResult, error) {
+	res, err := krylovSchur("Lanczos", a, k, settings, true)
+	if err != nil {
+		return nil, err
+	}
+	n, _ := a.Dims()
+	values := make(Vector, k)
+	vectors := ZeroMatrix(n, k)
+	for j, v := range res.Values {
+		values[j] = real(v)
+		for i := 0; i < n; i++ {
+			vectors.Data[i][j] = real(res.Vectors.Data[i][j])
+		}
+	}
+	return &LanczosResult{Values: &values, Vectors: vectors, Residuals: res.Residuals, Restarts: res.Restarts, MatVecs: res.MatVecs, Converged: res.Converged}, nil
+}
+
+// Arnoldi finds k eigen pairs of general square operator by restarted Arnoldi, it is equivalent to implicitly restarted Arnoldi
+//	https://en.wikipedia.org/wiki/Arnoldi_iteration
+//	restart keeps an orthonormal basis of the wanted Ritz vectors (Krylov-Schur restart, G. W. Stewart, 2001),
+//	complex conjugate pairs are kept together so everything except results is in real arithmetic
+//	not converged result is returned with Converged false, errors are the same as `Lanczos`
+func Arnoldi(a LinearOperator, k int, settings *EigenSettings) (*ArnoldiResult, error) {
+	return krylovSchur("Arnoldi", a, k, settings, false)
+}
+
+// orthogonalize removes components of basis (orthonormal) from w in place by classical Gram-Schmidt twice,
+// returns the coefficients
+func orthogonalize(basis []Vector, w Vector) []float64 {
+	h := make([]float64, len(basis))
+	for pass := 0; pass < 2; pass++ {
+		for i := range basis {
+			c := basis[i].Dot(&w)
+			h[i] += c
+			Axpy(-c, &basis[i], &w)
+		}
+	}
+	return h
+}
+
+// randomOrthogonal returns a unit random vector orthogonal to basis, a zero vector if basis spans the whole space
+func randomOrthogonal(basis []Vector, n int, rnd *rand.Rand) Vector {
+	w := make(Vector, n)
+	for try := 0; try < 3 && len(basis) < n; try++ {
+		for i := range w {
+			w[i] = rnd.NormFloat64()
+		}
+		orthogonalize(basis, w)
+		if norm := w.Norm(); norm > 1e-8 {
+			Scal(1/norm, &w)
+			return w
+		}
+	}
+	return make(Vector, n)
+}
+
+// orthonormalColumns orthonormalizes vectors in place by Gram-Schmidt, dependent ones are dropped
+func orthonormalColumns(cols []Vector) []Vector {
+	res := cols[:0]
+	for _, c := range cols {
+		norm0 := c.Norm()
+		orthogonalize(res, c)
+		if norm := c.Norm(); norm > 1e-10*norm0 && norm > 0 {
+			Scal(1/norm, &c)
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// krylovSchur is restarted Arnoldi process behind `Lanczos` and `Arnoldi`, H is symmetrized if symmetric is true
+func krylovSchur(op string, a LinearOperator, k int, settings *EigenSettings, symmetric bool) (*ArnoldiResult, error) {
+	n, col := a.Dims()
+	if n != col {
+		return nil, squareError(op, n, col)
+	}
+	if k < 1 || k > n {
+		return nil, fmt.Errorf("%s: %w (k = %d out of [1, %d])", op, ErrDimensionMismatch, k, n)
+	}
+	var st EigenSettings
+	if settings != nil {
+		st = *settings
+	}
+	if st.Tol <= 0 {
+		st.Tol = 1e-10
+	}
+	if st.MaxIter <= 0 {
+		st.MaxIter = 300
+	}
+	m := st.NCV
+	if m <= 0 {
+		m = MaxInt(2*k+1, 20)
+	}
+	m = MinInt(MaxInt(m, k+2), n)
+
+	rnd := rand.New(rand.NewSource(1))
+	V := make([]Vector, m+1)
+	if st.V0 != nil {
+		if st.V0.Length() != n {
+			return nil, lenError(op, n, st.V0.Length())
+		}
+		V[0] = append(Vector{}, *st.V0...)
+	} else {
+		V[0] = randomOrthogonal(nil, n, rnd)
+	}
+	norm := V[0].Norm()
+	if norm == 0 {
+		return nil, fmt.Errorf("%s: %w (start vector)", op, ErrZeroVector)
+	}
+	Scal(1/norm, &V[0])
+
+	eps23 := math.Pow(math.Pow(2, -52), 2./3)
+	H := ZeroMatrix(m+1, m)
+	res := &ArnoldiResult{}
+	p := 0 // number of kept vectors
+	for {
+		// expand Arnoldi decomposition A * V[:m] = V[:m] * H[:m] + H[m][m-1] * V[m] * e_m'
+		for j := p; j < m; j++ {
+			w := *a.MulVec(&V[j])
+			res.MatVecs++
+			wNorm := w.Norm()
+			for i, h := range orthogonalize(V[:j+1], w) {
+				H.Data[i][j] += h
+			}
+			if beta := w.Norm(); beta > 1e-12*wNorm {
+				H.Data[j+1][j] = beta
+				Scal(1/beta, &w)
+				V[j+1] = w
+			} else {
+				// invariant subspace is found, continue with a new direction
+				H.Data[j+1][j] = 0
+				V[j+1] = randomOrthogonal(V[:j+1], n, rnd)
+			}
+		}
+
+		// Rayleigh-Ritz
+		Hm := H.GetSubMatrix(0, 0, m, m)
+		if symmetric {
+			for i := 0; i < m; i++ {
+				for j := 0; j < i; j++ {
+					Hm.Data[i][j] = (Hm.Data[i][j] + Hm.Data[j][i]) / 2
+					Hm.Data[j][i] = Hm.Data[i][j]
+				}
+			}
+		}
+		ritz := EigenDecomposeComplex(Hm)
+		order := make([]int, m)
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(x, y int) bool {
+			return st.Target.before(ritz.Values[order[x]], ritz.Values[order[y]])
+		})
+		beta := H.Data[m][m-1]
+		residuals := make([]float64, k)
+		converged := 0
+		for t := 0; t < k; t++ {
+			o := order[t]
+			residuals[t] = math.Abs(beta) * cmplx.Abs(ritz.Vectors.Data[m-1][o])
+			if residuals[t] <= st.Tol*math.Max(cmplx.Abs(ritz.Values[o]), eps23) {
+				converged++
+			}
+		}
+		res.Restarts++
+		if converged == k || res.Restarts >= st.MaxIter {
+			res.Values = make([]complex128, k)
+			res.Vectors = ZeroComplexMatrix(n, k)
+			for t := 0; t < k; t++ {
+				o := order[t]
+				res.Values[t] = ritz.Values[o]
+				for j := 0; j < m; j++ {
+					y := ritz.Vectors.Data[j][o]
+					for i, v := range V[j] {
+						res.Vectors.Data[i][t] += y * complex(v, 0)
+					}
+				}
+				normalizeEigenVector(res.Vectors, t, false)
+			}
+			res.Residuals = residuals
+			res.Converged = converged == k
+			return res, nil
+		}
+
+		// thick restart with orthonormal basis of the kept Ritz vectors, complex conjugate pair is not split
+		keep := MinInt(k+(m-k)/2, m-1)
+		if v := ritz.Values[order[keep-1]]; imag(v) > 0 && keep < m && ritz.Values[order[keep]] == cmplx.Conj(v) {
+			if keep+1 < m {
+				keep++
+			} else {
+				keep--
+			}
+		}
+		var cols []Vector
+		for t := 0; t < keep; t++ {
+			o := order[t]
+			y := ritz.Vectors.Col(o)
+			re, im := make(Vector, m), make(Vector, m)
+			for j, v := range y {
+				re[j], im[j] = real(v), imag(v)
+			}
+			cols = append(cols, re)
+			if imag(ritz.Values[o]) > 0 {
+				cols = append(cols, im)
+			}
+		}
+		Y := orthonormalColumns(cols)
+		p = len(Y)
+		newV := make([]Vector, m+1)
+		for t, y := range Y {
+			newV[t] = make(Vector, n)
+			for j, c := range y {
+				Axpy(c, &V[j], &newV[t])
+			}
+		}
+		newV[p] = V[m]
+		// H = [Y' * Hm * Y; beta * e_m' * Y]
+		HY := make([]Vector, p)
+		for t, y := range Y {
+			HY[t] = *Hm.MulVec(&y)
+		}
+		H = ZeroMatrix(m+1, m)
+		for s := 0; s < p; s++ {
+			for t := 0; t < p; t++ {
+				H.Data[s][t] = Y[s].Dot(&HY[t])
+			}
+			H.Data[p][s] = beta * Y[s][m-1]
+		}
+		V = newV
+	}
+}
+
+// RandomizedSVDSettings controls `RandomizedSVD`, zero values mean defaults
+type RandomizedSVDSettings struct {
+	Oversample int   // extra random samples beyond k, default 10
+	PowerIter  int   // power iterations for slowly decaying singular values, default 2, negative for none
+	Seed       int64 // seed of the Gaussian test matrix
+}
+
+// RandomizedSVD returns truncated singular value decomposition A ≈ U * diag(S) * V.T() of rank k
+//	randomized range finder with power iterations (N. Halko, P. G. Martinsson, J. A. Tropp, 2011)
+//	https://arxiv.org/abs/0909.4061
+//	U: m x k, S: k singular values in descending order, V: n x k, columns of U and V are orthonormal
+//	only matrix-vector products with A and A.T() are used, k + Oversample of each per pass
+//	k is reduced to the numerical rank if the sampled range is smaller
+//	ErrDimensionMismatch if k is not in [1, min(m, n)]
+func RandomizedSVD(a TransposeOperator, k int, settings *RandomizedSVDSettings) (U *Matrix, S *Vector, V *Matrix, err error) {
+	m, n := a.Dims()
+	if k < 1 || k > MinInt(m, n) {
+		return nil, nil, nil, fmt.Errorf("RandomizedSVD: %w (k = %d out of [1, %d])", ErrDimensionMismatch, k, MinInt(m, n))
+	}
+	var st RandomizedSVDSettings
+	if settings != nil {
+		st = *settings
+	}
+	if st.Oversample <= 0 {
+		st.Oversample = 10
+	}
+	if st.PowerIter == 0 {
+		st.PowerIter = 2
+	}
+	l := MinInt(k+st.Oversample, MinInt(m, n))
+
+	rnd := rand.New(rand.NewSource(st.Seed))
+	omega := make([]Vector, l)
+	for j := range omega {
+		omega[j] = make(Vector, n)
+		for i := range omega[j] {
+			omega[j][i] = rnd.NormFloat64()
+		}
+	}
+	apply := func(X []Vector, transpose bool) []Vector {
+		Y := make([]Vector, len(X))
+		for j := range X {
+			if transpose {
+				Y[j] = *a.MulVecT(&X[j])
+			} else {
+				Y[j] = *a.MulVec(&X[j])
+			}
+		}
+		return Y
+	}
+	// orthonormal basis Q of range of A
+	Q := orthonormalColumns(apply(omega, false))
+	for q := 0; q < st.PowerIter; q++ {
+		Q = orthonormalColumns(apply(orthonormalColumns(apply(Q, true)), false))
+	}
+	l = len(Q)
+	if l == 0 {
+		return nil, nil, nil, fmt.Errorf("RandomizedSVD: %w (zero operator)", ErrZeroVector)
+	}
+	k = MinInt(k, l)
+
+	// B = Q.T() * A (l x n), SVD of B.T() = Ub * Sb * Vb.T() gives A ≈ (Q * Vb) * Sb * Ub.T()
+	Bt := ZeroMatrix(n, l)
+	for j, col := range apply(Q, true) {
+		for i, v := range col {
+			Bt.Data[i][j] = v
+		}
+	}
+	Ub, Sb, Vb, err := TrySVD(Bt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	U, V = ZeroMatrix(m, k), ZeroMatrix(n, k)
+	s := make(Vector, k)
+	for t := 0; t < k; t++ {
+		s[t] = Sb.Data[t][t]
+		for j := 0; j < l; j++ {
+			if c := Vb.Data[j][t]; c != 0 {
+				for i, v := range Q[j] {
+					U.Data[i][t] += c * v
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			V.Data[i][t] = Ub.Data[i][t]
+		}
+	}
+	return U, &s, V, nil
+}
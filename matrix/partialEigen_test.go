@@ -0,0 +1,188 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+)
+
+// laplacian1D returns n x n tridiagonal [-1 2 -1] matrix, its eigen values are 2 - 2cos(k pi / (n + 1))
+func laplacian1D(n int) *CSR {
+	var entries []Entry
+	for i := 0; i < n; i++ {
+		entries = append(entries, Entry{Row: i, Col: i, Value: 2})
+		if i > 0 {
+			entries = append(entries, Entry{Row: i, Col: i - 1, Value: -1}, Entry{Row: i - 1, Col: i, Value: -1})
+		}
+	}
+	return NewCSR(n, n, entries)
+}
+
+func TestMulVecT(t *testing.T) {
+	sm := GenerateRandomSparseMatrix(30, 20, 100)
+	mat := sm.ToMatrix()
+	v := GenerateRandomVector(30)
+	expected := mat.T().MulVec(v)
+	for _, op := range []TransposeOperator{mat, sm, sm.ToCSR(), sm.ToCSC()} {
+		if !VEqual(op.MulVecT(v), expected) {
+			t.Errorf("%T", op)
+		}
+	}
+}
+
+func TestLanczos(t *testing.T) {
+	n, k := 400, 6
+	a := laplacian1D(n)
+	exact := func(j int) float64 {
+		return 2 - 2*math.Cos(float64(j)*math.Pi/float64(n+1))
+	}
+	res, err := Lanczos(a, k, &EigenSettings{Target: LargestReal})
+	if err != nil || !res.Converged {
+		t.Fatal(err, res.Restarts)
+	}
+	for j := 0; j < k; j++ {
+		if math.Abs(res.Values.At(j)-exact(n-j)) > 1e-9 {
+			t.Fatal(res.Values)
+		}
+		x := res.Vectors.Col(j)
+		if r := a.MulVec(x).Sub(x.MulNum(res.Values.At(j))).Norm(); r > 1e-8 {
+			t.Fatal(j, r)
+		}
+	}
+	if !MEqual(res.Vectors.T().Mul(res.Vectors), IdentityMatrix(k)) {
+		t.Fail()
+	}
+
+	// smallest eigen values through a matrix-free operator
+	op := &FuncOperator{Rows: n, Cols: n, Mul: a.MulVec}
+	res, err = Lanczos(op, 3, &EigenSettings{Target: SmallestReal, NCV: 40, MaxIter: 2000})
+	if err != nil || !res.Converged {
+		t.Fatal(err)
+	}
+	for j := 0; j < 3; j++ {
+		if math.Abs(res.Values.At(j)-exact(j+1)) > 1e-9 {
+			t.Fatal(res.Values)
+		}
+	}
+
+	// the whole space
+	sym := GenerateRandomSymmetric33Matrix()
+	res, err = Lanczos(sym, 3, nil)
+	if err != nil || !res.Converged {
+		t.Fatal(err)
+	}
+	eig := *EigenValues33(sym)
+	sort.Slice(eig, func(i, j int) bool { return math.Abs(eig[i]) > math.Abs(eig[j]) })
+	if !VEqual(res.Values, &eig) {
+		t.Fatal(res.Values, eig)
+	}
+
+	if _, err = Lanczos(a, 0, nil); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	if _, err = Lanczos(GenerateRandomMatrix(3, 4), 1, nil); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+	if _, err = Lanczos(a, 1, &EigenSettings{V0: &Vector{1}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+	zero := make(Vector, n)
+	if _, err = Lanczos(a, 1, &EigenSettings{V0: &zero}); !errors.Is(err, ErrZeroVector) {
+		t.Fail()
+	}
+}
+
+func TestArnoldi(t *testing.T) {
+	// A = S * D * S⁻¹ with known eigen values, two complex conjugate pairs have the largest magnitudes
+	n := 60
+	D := ZeroMatrix(n, n)
+	for i := 0; i < n-4; i++ {
+		D.Data[i][i] = float64(i + 1)
+	}
+	a, b := float64(n+5), 3.
+	D.Data[n-4][n-4], D.Data[n-4][n-3], D.Data[n-3][n-4], D.Data[n-3][n-3] = a, b, -b, a
+	a, b = -float64(n+2), 1
+	D.Data[n-2][n-2], D.Data[n-2][n-1], D.Data[n-1][n-2], D.Data[n-1][n-1] = a, b, -b, a
+	S := IdentityMatrix(n).Add(GenerateRandomSquareMatrix(n).MulNum(0.1))
+	A := S.Mul(D).Mul(S.Inverse())
+
+	res, err := Arnoldi(A, 5, nil)
+	if err != nil || !res.Converged {
+		t.Fatal(err)
+	}
+	expected := []complex128{complex(float64(n+5), 3), complex(float64(n+5), -3), complex(-float64(n+2), 1), complex(-float64(n+2), -1), complex(float64(n-4), 0)}
+	cA := NewComplexMatrix(A, nil)
+	for j, v := range res.Values {
+		if cmplx.Abs(v-expected[j]) > 1e-8 {
+			t.Fatal(res.Values)
+		}
+		x := res.Vectors.Col(j)
+		Ax := cA.MulVec(x)
+		for i := range Ax {
+			Ax[i] -= v * x[i]
+		}
+		if complexNorm(Ax) > 1e-7 || !FloatEqual(complexNorm(x), 1) {
+			t.Fatal(j, complexNorm(Ax))
+		}
+	}
+
+	// sparse non-symmetric operator against dense eigen values
+	cd := convectionDiffusion2D(8, 0.5)
+	res, err = Arnoldi(cd.ToCSR(), 4, &EigenSettings{Target: SmallestReal, MaxIter: 1000})
+	if err != nil || !res.Converged {
+		t.Fatal(err)
+	}
+	all := EigenDecomposeComplex(cd.ToMatrix()).Values
+	sort.Slice(all, func(i, j int) bool { return SmallestReal.before(all[i], all[j]) })
+	for j, v := range res.Values {
+		if cmplx.Abs(v-all[j]) > 1e-8 {
+			t.Fatal(res.Values, all[:4])
+		}
+	}
+}
+
+func TestRandomizedSVD(t *testing.T) {
+	// matrix with fast decaying singular values
+	m, n, k := 120, 80, 8
+	X, Y := GenerateRandomMatrix(m, n), GenerateRandomMatrix(n, n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < m; i++ {
+			X.Data[i][j] *= math.Pow(0.5, float64(j))
+		}
+	}
+	A := X.Mul(Y)
+	_, S0, _ := SVD(A)
+	U, S, V, err := RandomizedSVD(A, k, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < k; j++ {
+		if math.Abs(S.At(j)-S0.At(j, j)) > 1e-8*S0.At(0, 0) {
+			t.Fatal(S, S0.GetDiagonalElements())
+		}
+	}
+	if !MEqual(U.T().Mul(U), IdentityMatrix(k)) || !MEqual(V.T().Mul(V), IdentityMatrix(k)) {
+		t.Fail()
+	}
+	// A * v = s * u
+	for j := 0; j < k; j++ {
+		if r := A.MulVec(V.Col(j)).Sub(U.Col(j).MulNum(S.At(j))).Norm(); r > 1e-8*S0.At(0, 0) {
+			t.Fatal(j, r)
+		}
+	}
+
+	// exact low rank sparse matrix, rank is found
+	sm := ZeroSparseMatrix(50, 40)
+	sm.Set(3, 7, 5)
+	sm.Set(10, 2, -2)
+	U, S, V, err = RandomizedSVD(sm, 4, &RandomizedSVDSettings{PowerIter: -1})
+	if err != nil || S.Length() != 2 || !VEqual(S, &Vector{5, 2}) || math.Abs(U.At(3, 0)*V.At(7, 0)) != 1 {
+		t.Fatal(S, err)
+	}
+
+	if _, _, _, err = RandomizedSVD(A, n+1, nil); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
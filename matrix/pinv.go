@@ -0,0 +1,99 @@
+package matrix
+
+// thinSVD returns A = U * diag(s) * V' with m x k U, n x k V and descending s, k = min(m, n), for any shape of A
+func thinSVD(t *Matrix) (U *Matrix, s *Vector, V *Matrix) {
+	m, n := t.Dims()
+	if m < n {
+		Ut, S, Vt := SVD(t.T())
+		return Vt, S.GetDiagonalElements(), Ut
+	}
+	U, S, V := SVD(t)
+	return U, S.GetDiagonalElements(), V
+}
+
+// Pinv returns Moore-Penrose pseudo-inverse A⁺ = V * Σ⁺ * U' of m x n matrix by `SVD`
+//	https://en.wikipedia.org/wiki/Moore%E2%80%93Penrose_inverse
+//	singular values not larger than tol * σ_max are treated as zero, tol <= 0 means max(m, n) * 2^-52 (see `NumericalRank`)
+//	A⁺ equals `Inverse` for non-singular square matrix, and A⁺ * b is the minimum norm least squares solution
+func Pinv(t *Matrix, tol float64) *Matrix {
+	m, n := t.Dims()
+	U, s, V := thinSVD(t)
+	rank := svdRank(s, m, n, tol)
+	res := ZeroMatrix(n, m)
+	for i := 0; i < n; i++ {
+		for j := 0; j < m; j++ {
+			sum := 0.
+			for k := 0; k < rank; k++ {
+				sum += V.Data[i][k] / s.At(k) * U.Data[j][k]
+			}
+			res.Data[i][j] = sum
+		}
+	}
+	return res
+}
+
+// TrySolveMinNorm returns minimum norm least squares solution x = A⁺ * b of min ||A * x - b|| by `SVD`
+//	among all least squares solutions of rank deficient or under-determined problem, x has the smallest ||x||,
+//	tol works like `Pinv`, ErrDimensionMismatch if length of b is not rows of A
+func TrySolveMinNorm(A *Matrix, b *Vector, tol float64) (*LeastSquares, error) {
+	m, n := A.Dims()
+	if b.Length() != m {
+		return nil, lenError("SolveMinNorm", m, b.Length())
+	}
+	U, s, V := thinSVD(A)
+	rank := svdRank(s, m, n, tol)
+	// c = Σ⁺ * U' * b, x = V * c
+	c := make(Vector, rank)
+	for k := range c {
+		for i, v := range *b {
+			c[k] += U.Data[i][k] * v
+		}
+		c[k] /= s.At(k)
+	}
+	x := make(Vector, n)
+	for i := range x {
+		for k, v := range c {
+			x[i] += V.Data[i][k] * v
+		}
+	}
+	return &LeastSquares{X: &x, Residual: A.MulVec(&x).Sub(b).Norm(), Rank: rank}, nil
+}
+
+// SolveMinNorm returns minimum norm least squares solution like `TrySolveMinNorm`, panics on error
+func SolveMinNorm(A *Matrix, b *Vector, tol float64) *LeastSquares {
+	ls, err := TrySolveMinNorm(A, b, tol)
+	must(err)
+	return ls
+}
+
+// NullSpace returns orthonormal basis of null space {x: A * x = 0} of m x n matrix in columns (n x (n - rank))
+//	it is right singular vectors of zero singular values, tol works like `Pinv`, nil for full column rank
+func NullSpace(t *Matrix, tol float64) *Matrix {
+	m, n := t.Dims()
+	if m < n {
+		// zero rows keep the null space and make V of `SVD` complete
+		padded := ZeroMatrix(n, n)
+		for i := range t.Data {
+			copy(padded.Data[i], t.Data[i])
+		}
+		t = padded
+	}
+	_, S, V := SVD(t)
+	rank := svdRank(S.GetDiagonalElements(), m, n, tol)
+	if rank == n {
+		return nil
+	}
+	return V.GetSubMatrix(0, rank, n, n-rank)
+}
+
+// RangeSpace returns orthonormal basis of range (column space) {A * x} of m x n matrix in columns (m x rank)
+//	it is left singular vectors of non-zero singular values, tol works like `Pinv`, nil for zero matrix
+func RangeSpace(t *Matrix, tol float64) *Matrix {
+	m, n := t.Dims()
+	U, s, _ := thinSVD(t)
+	rank := svdRank(s, m, n, tol)
+	if rank == 0 {
+		return nil
+	}
+	return U.GetSubMatrix(0, 0, m, rank)
+}
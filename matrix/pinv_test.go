@@ -0,0 +1,89 @@
+package matrix
+
+import (
+	"errors"
+	"testing"
+)
+
+// rankDeficient returns m x n matrix of rank r
+func rankDeficient(m, n, r int) *Matrix {
+	return GenerateRandomMatrix(m, r).Mul(GenerateRandomMatrix(r, n))
+}
+
+func TestPinv(t *testing.T) {
+	a := GenerateRandomSquareMatrix(5).Add(IdentityMatrix(5))
+	if !MEqual(Pinv(a, 0), a.Inverse()) {
+		t.Fail()
+	}
+	// pinv([1 2; 2 4]) = [1 2; 2 4] / 25
+	a = new(Matrix).Init(Data{{1, 2}, {2, 4}})
+	if !MEqual(Pinv(a, 0), a.MulNum(1./25)) {
+		t.Fail()
+	}
+	// Penrose conditions for tall and wide rank deficient matrices
+	for _, a := range []*Matrix{rankDeficient(7, 4, 2), rankDeficient(3, 6, 2)} {
+		p := Pinv(a, 0)
+		m, n := p.Dims()
+		if r, c := a.Dims(); m != c || n != r {
+			t.Fatal(m, n)
+		}
+		ap, pa := a.Mul(p), p.Mul(a)
+		if !MEqual(ap.Mul(a), a) || !MEqual(pa.Mul(p), p) || !MEqual(ap.T(), ap) || !MEqual(pa.T(), pa) {
+			t.Fail()
+		}
+	}
+	if !MEqual(Pinv(ZeroMatrix(2, 3), 0), ZeroMatrix(3, 2)) {
+		t.Fail()
+	}
+	// tolerance drops the small singular value
+	a = new(Matrix).Init(Data{{1, 0}, {0, 1e-10}})
+	if !MEqual(Pinv(a, 1e-8), new(Matrix).Init(Data{{1, 0}, {0, 0}})) {
+		t.Fail()
+	}
+}
+
+func TestSolveMinNorm(t *testing.T) {
+	// x + y = 2 has minimum norm solution (1, 1)
+	ls := SolveMinNorm(new(Matrix).Init(Data{{1, 1}}), &Vector{2}, 0)
+	if !VEqual(ls.X, &Vector{1, 1}) || ls.Rank != 1 || ls.Residual > 1e-12 {
+		t.Fail()
+	}
+	// rank deficient least squares: same residual as QR basic solution, smaller norm
+	a := rankDeficient(8, 5, 3)
+	b := GenerateRandomVector(8)
+	ls = SolveMinNorm(a, b, 0)
+	basic := SolveLeastSquares(a, b)
+	if ls.Rank != 3 || !FloatEqual(ls.Residual, basic.Residual) || ls.X.Norm() > basic.X.Norm() {
+		t.Fail()
+	}
+	if !VEqual(ls.X, Pinv(a, 0).MulVec(b)) {
+		t.Fail()
+	}
+	if _, err := TrySolveMinNorm(a, &Vector{1}, 0); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fail()
+	}
+}
+
+func TestNullRangeSpace(t *testing.T) {
+	for _, a := range []*Matrix{rankDeficient(7, 4, 2), rankDeficient(3, 6, 2)} {
+		m, n := a.Dims()
+		N := NullSpace(a, 0)
+		if r, c := N.Dims(); r != n || c != n-2 {
+			t.Fatal(r, c)
+		}
+		if !MEqual(a.Mul(N), ZeroMatrix(m, n-2)) || !MEqual(N.T().Mul(N), IdentityMatrix(n-2)) {
+			t.Fail()
+		}
+		R := RangeSpace(a, 0)
+		if r, c := R.Dims(); r != m || c != 2 {
+			t.Fatal(r, c)
+		}
+		// projection onto range is A * A⁺
+		if !MEqual(R.T().Mul(R), IdentityMatrix(2)) || !MEqual(R.Mul(R.T()), a.Mul(Pinv(a, 0))) {
+			t.Fail()
+		}
+	}
+	if NullSpace(IdentityMatrix(3), 0) != nil || RangeSpace(ZeroMatrix(3, 2), 0) != nil {
+		t.Fail()
+	}
+}
@@ -1,9 +1,5 @@
 package matrix
 
-import (
-	"math"
-)
-
 // SparseMatrix struct
 //	https://en.wikipedia.org/wiki/Sparse_matrix
 //	Dictionary of keys (DOK)
@@ -34,6 +30,11 @@ func NewSparseMatrix(data map[int]float64, rows, cols int) *SparseMatrix {
 	}
 }
 
+// Dims returns sparse matrix dimensions in row, col
+func (sm *SparseMatrix) Dims() (row, col int) {
+	return sm.Rows, sm.Cols
+}
+
 // RowColToIndex transfers row, col idx into internal data map idx
 func (sm *SparseMatrix) RowColToIndex(row, col int) (idx int) {
 	return row*sm.Cols + col - sm.Offset + sm.Offset%sm.Cols + sm.Offset/sm.Cols
@@ -116,8 +117,8 @@ func (sm *SparseMatrix) Row(n int) *Vector {
 
 // Col constructs and returns a column vector
 func (sm *SparseMatrix) Col(n int) *Vector {
-	v := make(Vector, sm.Cols)
-	for i := 0; i < sm.Cols; i++ {
+	v := make(Vector, sm.Rows)
+	for i := 0; i < sm.Rows; i++ {
 		entry, ok := sm.Data[sm.RowColToIndex(i, n)]
 		if !ok {
 			v[i] = 0.
@@ -190,8 +191,15 @@ func (sm *SparseMatrix) T() *SparseMatrix {
 
 // Add sums two sparse matrices and returns a new sparse matrix
 func (sm *SparseMatrix) Add(sm2 *SparseMatrix) *SparseMatrix {
+	nsm, err := sm.TryAdd(sm2)
+	must(err)
+	return nsm
+}
+
+// TryAdd sums two sparse matrices and returns a new sparse matrix, ErrDimensionMismatch if dims are different
+func (sm *SparseMatrix) TryAdd(sm2 *SparseMatrix) (*SparseMatrix, error) {
 	if sm.Rows != sm2.Rows || sm.Cols != sm2.Cols {
-		panic("Dimension mismatch")
+		return nil, dimsError("SparseMatrix.Add", sm.Rows, sm.Cols, sm2.Rows, sm2.Cols)
 	}
 	nsm := ZeroSparseMatrix(sm.Rows, sm.Cols)
 	for idx, value := range sm.Data {
@@ -201,7 +209,12 @@ func (sm *SparseMatrix) Add(sm2 *SparseMatrix) *SparseMatrix {
 			nsm.SetIndex(idx, value+sm2.Data[idx])
 		}
 	}
-	return nsm
+	for idx, value := range sm2.Data {
+		if _, ok := sm.Data[idx]; !ok {
+			nsm.SetIndex(idx, value)
+		}
+	}
+	return nsm, nil
 }
 
 // AddNum adds input number to all elements inside the sparse matrix and returns a new sparse matrix
@@ -218,8 +231,15 @@ func (sm *SparseMatrix) AddNum(n float64) *SparseMatrix {
 // Mul does sparse matrix multiplication
 //	TODO: Need Optimize
 func (sm *SparseMatrix) Mul(sm2 *SparseMatrix) *SparseMatrix {
+	nsm, err := sm.TryMul(sm2)
+	must(err)
+	return nsm
+}
+
+// TryMul does sparse matrix multiplication, ErrDimensionMismatch if it is not M x N and N x L
+func (sm *SparseMatrix) TryMul(sm2 *SparseMatrix) (*SparseMatrix, error) {
 	if sm.Cols != sm2.Rows {
-		panic("Dimension mismatch")
+		return nil, dimsError("SparseMatrix.Mul", sm.Rows, sm.Cols, sm2.Rows, sm2.Cols)
 	}
 	nsm := ZeroSparseMatrix(sm.Rows, sm2.Cols)
 	for idx, value := range sm.Data {
@@ -231,19 +251,40 @@ func (sm *SparseMatrix) Mul(sm2 *SparseMatrix) *SparseMatrix {
 			}
 		}
 	}
-	return nsm
+	return nsm, nil
 }
 
 // MulVec multiplies sparse matrix with input vector and returns a new vector
 func (sm *SparseMatrix) MulVec(v *Vector) *Vector {
+	nVec, err := sm.TryMulVec(v)
+	must(err)
+	return nVec
+}
+
+// TryMulVec multiplies sparse matrix with input vector and returns a new vector,
+// ErrDimensionMismatch if matrix columns is not equal to vector length
+func (sm *SparseMatrix) TryMulVec(v *Vector) (*Vector, error) {
 	if sm.Cols != v.Length() {
-		panic("Dimension mismatch")
+		return nil, dimsError("SparseMatrix.MulVec", sm.Rows, sm.Cols, v.Length(), 1)
 	}
 	nVec := make(Vector, sm.Rows)
 	for idx, value := range sm.Data {
 		r, c := sm.IndexToRowCol(idx)
 		nVec[r] += value * v.At(c)
 	}
+	return &nVec, nil
+}
+
+// MulVecT multiplies transpose of sparse matrix with input vector and returns a new vector, without transposing the matrix
+func (sm *SparseMatrix) MulVecT(v *Vector) *Vector {
+	if sm.Rows != v.Length() {
+		panic(dimsError("SparseMatrix.MulVecT", sm.Cols, sm.Rows, v.Length(), 1))
+	}
+	nVec := make(Vector, sm.Cols)
+	for idx, value := range sm.Data {
+		r, c := sm.IndexToRowCol(idx)
+		nVec[c] += value * v.At(r)
+	}
 	return &nVec
 }
 
@@ -260,18 +301,15 @@ func (sm *SparseMatrix) MulNum(n float64) *SparseMatrix {
 }
 
 // Det returns determinant of sparse matrix
-//	transfer to dense matrix and use LUP decomposition
-//	Notice: easy OOM and slow...
-//	TODO: Any better way???
+//	sparse LU decomposition with reverse Cuthill-McKee ordering, it never transfers to dense matrix
+//	returns 0 for singular matrix
 func (sm *SparseMatrix) Det() float64 {
-	defer func() {
-		if r := recover(); r != nil { // Can Not do LUP Decomposition with tolerance 1e-6
-			// return zero value of the specified return type
-		}
-	}()
-	det := sm.ToMatrix().Det()
-	if math.IsNaN(det) {
+	if sm.Rows != sm.Cols {
+		panic(squareError("SparseMatrix.Det", sm.Rows, sm.Cols))
+	}
+	f, err := NewSparseLU(sm.ToCSR(), RCMOrdering)
+	if err != nil {
 		return 0.
 	}
-	return det
+	return f.Det()
 }
@@ -154,6 +154,9 @@ func TestSparseMatrix_Add(t *testing.T) {
 			t.Fail()
 		}
 	}
+	if !MEqual(SC.ToMatrix(), SA.ToMatrix().Add(SB.ToMatrix())) {
+		t.Fail()
+	}
 }
 
 func TestSparseMatrix_AddNum(t *testing.T) {
@@ -0,0 +1,775 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// structured matrices store only their possibly non-zero elements
+//	elements outside the structure read as zero, `Set` of a non-zero value outside it panics
+
+// DiagonalMatrix is square matrix with non-zero elements only on the main diagonal
+type DiagonalMatrix struct {
+	Diag Vector
+}
+
+// NewDiagonalMatrix generates diagonal matrix with input diagonal elements (copy)
+func NewDiagonalMatrix(diag *Vector) *DiagonalMatrix {
+	return &DiagonalMatrix{Diag: append(Vector{}, *diag...)}
+}
+
+// IdentityDiagonal generates n x n identity as diagonal matrix
+func IdentityDiagonal(n int) *DiagonalMatrix {
+	d := &DiagonalMatrix{Diag: make(Vector, n)}
+	for i := range d.Diag {
+		d.Diag[i] = 1
+	}
+	return d
+}
+
+// Dims returns numbers of rows and columns
+func (d *DiagonalMatrix) Dims() (row, col int) {
+	return len(d.Diag), len(d.Diag)
+}
+
+// At returns element at row i, column j
+func (d *DiagonalMatrix) At(i, j int) float64 {
+	if i < 0 || j < 0 || i >= len(d.Diag) || j >= len(d.Diag) {
+		panic("index out of range")
+	}
+	if i != j {
+		return 0
+	}
+	return d.Diag[i]
+}
+
+// Set sets element at row i, column j, only zero can be set off the diagonal
+func (d *DiagonalMatrix) Set(i, j int, value float64) {
+	if i != j {
+		if d.At(i, j) != value {
+			panic("cannot set off-diagonal element of diagonal matrix")
+		}
+		return
+	}
+	d.At(i, j)
+	d.Diag[i] = value
+}
+
+// Row returns a copy of i-th row
+func (d *DiagonalMatrix) Row(i int) *Vector {
+	r := make(Vector, len(d.Diag))
+	r[i] = d.At(i, i)
+	return &r
+}
+
+// Col returns a copy of j-th column
+func (d *DiagonalMatrix) Col(j int) *Vector {
+	return d.Row(j)
+}
+
+// ToMatrix converts diagonal matrix into dense `Matrix`
+func (d *DiagonalMatrix) ToMatrix() *Matrix {
+	t := ZeroMatrix(len(d.Diag), len(d.Diag))
+	for i, v := range d.Diag {
+		t.Data[i][i] = v
+	}
+	return t
+}
+
+// MulVec returns d * v
+func (d *DiagonalMatrix) MulVec(v *Vector) *Vector {
+	if len(d.Diag) != len(*v) {
+		panic(dimsError("DiagonalMatrix.MulVec", len(d.Diag), len(d.Diag), len(*v), 1))
+	}
+	res := make(Vector, len(*v))
+	for i, x := range *v {
+		res[i] = d.Diag[i] * x
+	}
+	return &res
+}
+
+// Mul returns d * t, i.e. rows of t scaled by diagonal elements
+func (d *DiagonalMatrix) Mul(t *Matrix) *Matrix {
+	row, col := t.Dims()
+	if len(d.Diag) != row {
+		panic(dimsError("DiagonalMatrix.Mul", len(d.Diag), len(d.Diag), row, col))
+	}
+	nt := ZeroMatrix(row, col)
+	for i := range t.Data {
+		for j, x := range t.Data[i] {
+			nt.Data[i][j] = d.Diag[i] * x
+		}
+	}
+	return nt
+}
+
+// MulRight returns t * d, i.e. columns of t scaled by diagonal elements
+func (d *DiagonalMatrix) MulRight(t *Matrix) *Matrix {
+	row, col := t.Dims()
+	if len(d.Diag) != col {
+		panic(dimsError("DiagonalMatrix.MulRight", row, col, len(d.Diag), len(d.Diag)))
+	}
+	nt := ZeroMatrix(row, col)
+	for i := range t.Data {
+		for j, x := range t.Data[i] {
+			nt.Data[i][j] = x * d.Diag[j]
+		}
+	}
+	return nt
+}
+
+// TrySolve solves d * x = b, ErrDimensionMismatch or ErrSingular for zero diagonal element
+func (d *DiagonalMatrix) TrySolve(b *Vector) (*Vector, error) {
+	if len(d.Diag) != len(*b) {
+		return nil, dimsError("DiagonalMatrix.Solve", len(d.Diag), len(d.Diag), len(*b), 1)
+	}
+	x := make(Vector, len(*b))
+	for i, v := range *b {
+		if d.Diag[i] == 0 {
+			return nil, fmt.Errorf("DiagonalMatrix.Solve: %w (zero diagonal element %d)", ErrSingular, i)
+		}
+		x[i] = v / d.Diag[i]
+	}
+	return &x, nil
+}
+
+// Solve solves d * x = b like `TrySolve`, panics on error
+func (d *DiagonalMatrix) Solve(b *Vector) *Vector {
+	x, err := d.TrySolve(b)
+	must(err)
+	return x
+}
+
+// Inverse returns inverse diagonal matrix, panics with ErrSingular for zero diagonal element
+func (d *DiagonalMatrix) Inverse() *DiagonalMatrix {
+	inv := IdentityDiagonal(len(d.Diag))
+	inv.Diag = *d.Solve(&inv.Diag)
+	return inv
+}
+
+// Det returns determinant, product of diagonal elements
+func (d *DiagonalMatrix) Det() float64 {
+	det := 1.
+	for _, v := range d.Diag {
+		det *= v
+	}
+	return det
+}
+
+// TriangularMatrix is n x n upper or lower triangular matrix in row-major packed storage
+//	lower: row i holds columns [0, i] at Data[i*(i+1)/2:], upper: row i holds columns [i, n) at Data[i*n-i*(i-1)/2:]
+//	Unit: diagonal elements are all one and not read from Data (e.g. L of LU decomposition)
+type TriangularMatrix struct {
+	N     int
+	Upper bool
+	Unit  bool
+	Data  []float64
+}
+
+// ZeroTriangularMatrix generates n x n triangular matrix with all stored elements are zero
+func ZeroTriangularMatrix(n int, upper, unit bool) *TriangularMatrix {
+	return &TriangularMatrix{N: n, Upper: upper, Unit: unit, Data: make([]float64, n*(n+1)/2)}
+}
+
+// NewTriangularMatrix copies upper or lower triangle of square matrix into triangular matrix,
+//	the other triangle is ignored, as is the diagonal for unit triangular matrix
+func NewTriangularMatrix(t *Matrix, upper, unit bool) *TriangularMatrix {
+	row, col := t.Dims()
+	if row != col {
+		panic(squareError("NewTriangularMatrix", row, col))
+	}
+	tr := ZeroTriangularMatrix(row, upper, unit)
+	for i := 0; i < row; i++ {
+		lo, hi := tr.span(i)
+		copy(tr.Data[tr.offset(i):], t.Data[i][lo:hi])
+	}
+	return tr
+}
+
+// span returns column range [lo, hi) of stored row i
+func (tr *TriangularMatrix) span(i int) (lo, hi int) {
+	if tr.Upper {
+		return i, tr.N
+	}
+	return 0, i + 1
+}
+
+// offset returns index of the first stored element of row i inside Data
+func (tr *TriangularMatrix) offset(i int) int {
+	if tr.Upper {
+		return i*tr.N - i*(i-1)/2
+	}
+	return i * (i + 1) / 2
+}
+
+// row returns stored part of row i (columns [lo, hi) of `span`), it is a view of Data
+func (tr *TriangularMatrix) row(i int) []float64 {
+	lo, hi := tr.span(i)
+	s := tr.offset(i)
+	return tr.Data[s : s+hi-lo]
+}
+
+// diag returns i-th diagonal element, one for unit triangular matrix
+func (tr *TriangularMatrix) diag(i int) float64 {
+	if tr.Unit {
+		return 1
+	}
+	if tr.Upper {
+		return tr.Data[tr.offset(i)]
+	}
+	return tr.Data[tr.offset(i)+i]
+}
+
+// Dims returns numbers of rows and columns
+func (tr *TriangularMatrix) Dims() (row, col int) {
+	return tr.N, tr.N
+}
+
+// At returns element at row i, column j
+func (tr *TriangularMatrix) At(i, j int) float64 {
+	if i < 0 || j < 0 || i >= tr.N || j >= tr.N {
+		panic("index out of range")
+	}
+	if i == j {
+		return tr.diag(i)
+	}
+	lo, hi := tr.span(i)
+	if j < lo || j >= hi {
+		return 0
+	}
+	return tr.Data[tr.offset(i)+j-lo]
+}
+
+// Set sets element at row i, column j, only zero can be set outside the triangle and only one on unit diagonal
+func (tr *TriangularMatrix) Set(i, j int, value float64) {
+	lo, hi := tr.span(i)
+	if j < lo || j >= hi || (tr.Unit && i == j) {
+		if tr.At(i, j) != value {
+			panic("cannot set element outside the structure of triangular matrix")
+		}
+		return
+	}
+	tr.At(i, j)
+	tr.Data[tr.offset(i)+j-lo] = value
+}
+
+// Row returns a copy of i-th row
+func (tr *TriangularMatrix) Row(i int) *Vector {
+	r := make(Vector, tr.N)
+	tr.fillRow(i, r)
+	return &r
+}
+
+// fillRow writes i-th row into zeroed r of length N
+func (tr *TriangularMatrix) fillRow(i int, r []float64) {
+	lo, _ := tr.span(i)
+	copy(r[lo:], tr.row(i))
+	r[i] = tr.diag(i)
+}
+
+// Col returns a copy of j-th column
+func (tr *TriangularMatrix) Col(j int) *Vector {
+	c := make(Vector, tr.N)
+	for i := range c {
+		c[i] = tr.At(i, j)
+	}
+	return &c
+}
+
+// ToMatrix converts triangular matrix into dense `Matrix`
+func (tr *TriangularMatrix) ToMatrix() *Matrix {
+	t := ZeroMatrix(tr.N, tr.N)
+	for i := range t.Data {
+		tr.fillRow(i, t.Data[i])
+	}
+	return t
+}
+
+// T returns transpose, an upper triangular matrix for lower input and vice versa
+func (tr *TriangularMatrix) T() *TriangularMatrix {
+	nt := ZeroTriangularMatrix(tr.N, !tr.Upper, tr.Unit)
+	for i := 0; i < tr.N; i++ {
+		lo, _ := tr.span(i)
+		for k, v := range tr.row(i) {
+			j := lo + k
+			nlo, _ := nt.span(j)
+			nt.Data[nt.offset(j)+i-nlo] = v
+		}
+	}
+	return nt
+}
+
+// MulVec returns tr * v
+func (tr *TriangularMatrix) MulVec(v *Vector) *Vector {
+	if tr.N != len(*v) {
+		panic(dimsError("TriangularMatrix.MulVec", tr.N, tr.N, len(*v), 1))
+	}
+	res := make(Vector, tr.N)
+	for i := range res {
+		lo, _ := tr.span(i)
+		sum := 0.
+		for k, a := range tr.row(i) {
+			if lo+k != i {
+				sum += a * (*v)[lo+k]
+			}
+		}
+		res[i] = sum + tr.diag(i)*(*v)[i]
+	}
+	return &res
+}
+
+// Mul returns tr * t, only stored triangle is multiplied, so it takes about half of the dense flops
+func (tr *TriangularMatrix) Mul(t *Matrix) *Matrix {
+	row, col := t.Dims()
+	if tr.N != row {
+		panic(dimsError("TriangularMatrix.Mul", tr.N, tr.N, row, col))
+	}
+	nt := ZeroMatrix(row, col)
+	for i := range nt.Data {
+		out := nt.Data[i]
+		lo, _ := tr.span(i)
+		for k, a := range tr.row(i) {
+			j := lo + k
+			if j == i {
+				a = tr.diag(i)
+			}
+			if a == 0 {
+				continue
+			}
+			for c, b := range t.Data[j] {
+				out[c] += a * b
+			}
+		}
+	}
+	return nt
+}
+
+// singular returns ErrSingular for the first zero diagonal element, nil if there is none
+func (tr *TriangularMatrix) singular(op string) error {
+	for i := 0; i < tr.N; i++ {
+		if tr.diag(i) == 0 {
+			return fmt.Errorf("%s: %w (zero diagonal element %d)", op, ErrSingular, i)
+		}
+	}
+	return nil
+}
+
+// TrySolve solves tr * x = b by forward (lower) or back (upper) substitution,
+//	ErrDimensionMismatch or ErrSingular for zero diagonal element
+func (tr *TriangularMatrix) TrySolve(b *Vector) (*Vector, error) {
+	if tr.N != len(*b) {
+		return nil, dimsError("TriangularMatrix.Solve", tr.N, tr.N, len(*b), 1)
+	}
+	if err := tr.singular("TriangularMatrix.Solve"); err != nil {
+		return nil, err
+	}
+	x := append(Vector{}, *b...)
+	Trsv(false, tr, &x)
+	return &x, nil
+}
+
+// Solve solves tr * x = b like `TrySolve`, panics on error
+func (tr *TriangularMatrix) Solve(b *Vector) *Vector {
+	x, err := tr.TrySolve(b)
+	must(err)
+	return x
+}
+
+// TrySolveMatrix solves tr * X = B for all columns of B together, errors like `TrySolve`
+func (tr *TriangularMatrix) TrySolveMatrix(B *Matrix) (*Matrix, error) {
+	row, col := B.Dims()
+	if tr.N != row {
+		return nil, dimsError("TriangularMatrix.SolveMatrix", tr.N, tr.N, row, col)
+	}
+	if err := tr.singular("TriangularMatrix.SolveMatrix"); err != nil {
+		return nil, err
+	}
+	X := Copy(B)
+	Trsm(false, 1, tr, X)
+	return X, nil
+}
+
+// SolveMatrix solves tr * X = B like `TrySolveMatrix`, panics on error
+func (tr *TriangularMatrix) SolveMatrix(B *Matrix) *Matrix {
+	X, err := tr.TrySolveMatrix(B)
+	must(err)
+	return X
+}
+
+// Det returns determinant, product of diagonal elements
+func (tr *TriangularMatrix) Det() float64 {
+	det := 1.
+	for i := 0; i < tr.N; i++ {
+		det *= tr.diag(i)
+	}
+	return det
+}
+
+// SymmetricMatrix is n x n symmetric matrix, only lower triangle is stored in row-major packed storage
+//	element (i, j), i >= j, locates at Data[i*(i+1)/2+j]
+type SymmetricMatrix struct {
+	N    int
+	Data []float64
+}
+
+// ZeroSymmetricMatrix generates n x n symmetric matrix of zeros
+func ZeroSymmetricMatrix(n int) *SymmetricMatrix {
+	return &SymmetricMatrix{N: n, Data: make([]float64, n*(n+1)/2)}
+}
+
+// NewSymmetricMatrix copies lower triangle of square matrix into symmetric matrix, upper triangle is ignored
+func NewSymmetricMatrix(t *Matrix) *SymmetricMatrix {
+	row, col := t.Dims()
+	if row != col {
+		panic(squareError("NewSymmetricMatrix", row, col))
+	}
+	s := ZeroSymmetricMatrix(row)
+	for i := range t.Data {
+		copy(s.Data[i*(i+1)/2:], t.Data[i][:i+1])
+	}
+	return s
+}
+
+// index returns position of element (i, j) inside Data
+func (s *SymmetricMatrix) index(i, j int) int {
+	if i < 0 || j < 0 || i >= s.N || j >= s.N {
+		panic("index out of range")
+	}
+	if i < j {
+		i, j = j, i
+	}
+	return i*(i+1)/2 + j
+}
+
+// Dims returns numbers of rows and columns
+func (s *SymmetricMatrix) Dims() (row, col int) {
+	return s.N, s.N
+}
+
+// At returns element at row i, column j
+func (s *SymmetricMatrix) At(i, j int) float64 {
+	return s.Data[s.index(i, j)]
+}
+
+// Set sets both element (i, j) and (j, i)
+func (s *SymmetricMatrix) Set(i, j int, value float64) {
+	s.Data[s.index(i, j)] = value
+}
+
+// Row returns a copy of i-th row
+func (s *SymmetricMatrix) Row(i int) *Vector {
+	r := make(Vector, s.N)
+	for j := range r {
+		r[j] = s.At(i, j)
+	}
+	return &r
+}
+
+// Col returns a copy of j-th column, equal to j-th row
+func (s *SymmetricMatrix) Col(j int) *Vector {
+	return s.Row(j)
+}
+
+// ToMatrix converts symmetric matrix into dense `Matrix`
+func (s *SymmetricMatrix) ToMatrix() *Matrix {
+	t := ZeroMatrix(s.N, s.N)
+	for i := 0; i < s.N; i++ {
+		for j, v := range s.Data[i*(i+1)/2 : i*(i+1)/2+i+1] {
+			t.Data[i][j] = v
+			t.Data[j][i] = v
+		}
+	}
+	return t
+}
+
+// Add returns s + s2
+func (s *SymmetricMatrix) Add(s2 *SymmetricMatrix) *SymmetricMatrix {
+	if s.N != s2.N {
+		panic(dimsError("SymmetricMatrix.Add", s.N, s.N, s2.N, s2.N))
+	}
+	ns := ZeroSymmetricMatrix(s.N)
+	for i, v := range s.Data {
+		ns.Data[i] = v + s2.Data[i]
+	}
+	return ns
+}
+
+// MulNum returns s * n
+func (s *SymmetricMatrix) MulNum(n float64) *SymmetricMatrix {
+	ns := ZeroSymmetricMatrix(s.N)
+	for i, v := range s.Data {
+		ns.Data[i] = v * n
+	}
+	return ns
+}
+
+// MulVec returns s * v, every stored element is read once
+func (s *SymmetricMatrix) MulVec(v *Vector) *Vector {
+	if s.N != len(*v) {
+		panic(dimsError("SymmetricMatrix.MulVec", s.N, s.N, len(*v), 1))
+	}
+	res := make(Vector, s.N)
+	for i := 0; i < s.N; i++ {
+		xi := (*v)[i]
+		sum := 0.
+		for j, a := range s.Data[i*(i+1)/2 : i*(i+1)/2+i] {
+			sum += a * (*v)[j]
+			res[j] += a * xi
+		}
+		res[i] += sum + s.Data[i*(i+1)/2+i]*xi
+	}
+	return &res
+}
+
+// Mul returns s * t
+func (s *SymmetricMatrix) Mul(t *Matrix) *Matrix {
+	row, col := t.Dims()
+	if s.N != row {
+		panic(dimsError("SymmetricMatrix.Mul", s.N, s.N, row, col))
+	}
+	nt := ZeroMatrix(row, col)
+	for i := 0; i < s.N; i++ {
+		for j, a := range s.Data[i*(i+1)/2 : i*(i+1)/2+i+1] {
+			for c := 0; c < col; c++ {
+				nt.Data[i][c] += a * t.Data[j][c]
+			}
+			if j != i {
+				for c := 0; c < col; c++ {
+					nt.Data[j][c] += a * t.Data[i][c]
+				}
+			}
+		}
+	}
+	return nt
+}
+
+// TryCholesky returns lower triangular L with s = L * L.T(), ErrNotPositiveDefinite if a non-positive pivot is met
+func (s *SymmetricMatrix) TryCholesky() (*TriangularMatrix, error) {
+	L := ZeroTriangularMatrix(s.N, false, false)
+	for i := 0; i < s.N; i++ {
+		li := L.row(i)
+		for j := 0; j <= i; j++ {
+			lj := L.row(j)
+			sum := s.Data[i*(i+1)/2+j]
+			for k := 0; k < j; k++ {
+				sum -= li[k] * lj[k]
+			}
+			if j < i {
+				li[j] = sum / lj[j]
+			} else if !(sum > 0) {
+				return nil, fmt.Errorf("SymmetricMatrix.Cholesky: %w (pivot %d)", ErrNotPositiveDefinite, i)
+			} else {
+				li[i] = math.Sqrt(sum)
+			}
+		}
+	}
+	return L, nil
+}
+
+// Cholesky returns lower triangular L like `TryCholesky`, panics on error
+func (s *SymmetricMatrix) Cholesky() *TriangularMatrix {
+	L, err := s.TryCholesky()
+	must(err)
+	return L
+}
+
+// CovSymmetricMatrix returns covariance matrix like `CovMatrix` in packed symmetric storage,
+//	only lower triangle is accumulated, so it takes about half of the flops
+func (t *Matrix) CovSymmetricMatrix() *SymmetricMatrix {
+	row, col := t.Dims()
+	mean := t.Mean(0)
+	cov := ZeroSymmetricMatrix(col)
+	x := make(Vector, col)
+	for i := 0; i < row; i++ {
+		for j, v := range t.Data[i] {
+			x[j] = v - (*mean)[j]
+		}
+		for j := 0; j < col; j++ {
+			cj := cov.Data[j*(j+1)/2 : j*(j+1)/2+j+1]
+			for k := range cj {
+				cj[k] += x[j] * x[k]
+			}
+		}
+	}
+	f := 1. / float64(col-1)
+	for i := range cov.Data {
+		cov.Data[i] *= f
+	}
+	return cov
+}
+
+// TryCholeskyTriangular does Cholesky decomposition like `TryCholeskyDecomposition`, L is returned as packed
+//	lower triangular matrix, only lower triangle of t is read
+func TryCholeskyTriangular(t *Matrix) (*TriangularMatrix, error) {
+	row, col := t.Dims()
+	if row != col {
+		return nil, squareError("CholeskyDecomposition", row, col)
+	}
+	return NewSymmetricMatrix(t).TryCholesky()
+}
+
+// CholeskyTriangular does Cholesky decomposition like `TryCholeskyTriangular`, panics on error
+func CholeskyTriangular(t *Matrix) *TriangularMatrix {
+	L, err := TryCholeskyTriangular(t)
+	must(err)
+	return L
+}
+
+// Triangular returns L of Cholesky decomposition as packed lower triangular matrix (copy)
+func (c *Cholesky) Triangular() *TriangularMatrix {
+	return NewTriangularMatrix(c.L, false, false)
+}
+
+// BandMatrix is rows x cols matrix with non-zero elements only inside KL sub-diagonals and KU super-diagonals
+//	row i stores columns [i-KL, i+KU] at Data[i*(KL+KU+1):], element (i, j) locates at Data[i*(KL+KU+1)+j-i+KL],
+//	slots of columns outside the matrix are unused
+type BandMatrix struct {
+	Rows, Cols int
+	KL, KU     int
+	Data       []float64
+}
+
+// ZeroBandMatrix generates band matrix of zeros
+func ZeroBandMatrix(rows, cols, kl, ku int) *BandMatrix {
+	if rows < 0 || cols < 0 || kl < 0 || ku < 0 {
+		panic("negative dimension or bandwidth")
+	}
+	return &BandMatrix{Rows: rows, Cols: cols, KL: kl, KU: ku, Data: make([]float64, rows*(kl+ku+1))}
+}
+
+// NewBandMatrix copies band of matrix (kl sub-diagonals, ku super-diagonals) into band matrix, the rest is ignored
+func NewBandMatrix(t *Matrix, kl, ku int) *BandMatrix {
+	row, col := t.Dims()
+	b := ZeroBandMatrix(row, col, kl, ku)
+	for i := range t.Data {
+		if lo, hi := b.span(i); lo < hi {
+			copy(b.Data[i*b.width()+lo-i+kl:], t.Data[i][lo:hi])
+		}
+	}
+	return b
+}
+
+// width returns number of stored slots per row
+func (b *BandMatrix) width() int {
+	return b.KL + b.KU + 1
+}
+
+// span returns column range [lo, hi) of row i inside the band
+func (b *BandMatrix) span(i int) (lo, hi int) {
+	return MaxInt(0, i-b.KL), MinInt(b.Cols, i+b.KU+1)
+}
+
+// Dims returns numbers of rows and columns
+func (b *BandMatrix) Dims() (row, col int) {
+	return b.Rows, b.Cols
+}
+
+// At returns element at row i, column j
+func (b *BandMatrix) At(i, j int) float64 {
+	if i < 0 || j < 0 || i >= b.Rows || j >= b.Cols {
+		panic("index out of range")
+	}
+	if j < i-b.KL || j > i+b.KU {
+		return 0
+	}
+	return b.Data[i*b.width()+j-i+b.KL]
+}
+
+// Set sets element at row i, column j, only zero can be set outside the band
+func (b *BandMatrix) Set(i, j int, value float64) {
+	if j < i-b.KL || j > i+b.KU {
+		if b.At(i, j) != value {
+			panic("cannot set element outside the band of band matrix")
+		}
+		return
+	}
+	b.At(i, j)
+	b.Data[i*b.width()+j-i+b.KL] = value
+}
+
+// Row returns a copy of i-th row
+func (b *BandMatrix) Row(i int) *Vector {
+	r := make(Vector, b.Cols)
+	b.fillRow(i, r)
+	return &r
+}
+
+// fillRow writes i-th row into zeroed r of length Cols
+func (b *BandMatrix) fillRow(i int, r []float64) {
+	lo, hi := b.span(i)
+	for j := lo; j < hi; j++ {
+		r[j] = b.At(i, j)
+	}
+}
+
+// Col returns a copy of j-th column
+func (b *BandMatrix) Col(j int) *Vector {
+	c := make(Vector, b.Rows)
+	for i := MaxInt(0, j-b.KU); i < MinInt(b.Rows, j+b.KL+1); i++ {
+		c[i] = b.At(i, j)
+	}
+	return &c
+}
+
+// ToMatrix converts band matrix into dense `Matrix`
+func (b *BandMatrix) ToMatrix() *Matrix {
+	t := ZeroMatrix(b.Rows, b.Cols)
+	for i := range t.Data {
+		b.fillRow(i, t.Data[i])
+	}
+	return t
+}
+
+// T returns transpose, sub- and super-diagonal bandwidths are swapped
+func (b *BandMatrix) T() *BandMatrix {
+	nb := ZeroBandMatrix(b.Cols, b.Rows, b.KU, b.KL)
+	for i := 0; i < b.Rows; i++ {
+		lo, hi := b.span(i)
+		for j := lo; j < hi; j++ {
+			nb.Set(j, i, b.At(i, j))
+		}
+	}
+	return nb
+}
+
+// MulVec returns b * v in O(rows * (KL+KU+1))
+func (b *BandMatrix) MulVec(v *Vector) *Vector {
+	if b.Cols != len(*v) {
+		panic(dimsError("BandMatrix.MulVec", b.Rows, b.Cols, len(*v), 1))
+	}
+	res := make(Vector, b.Rows)
+	for i := range res {
+		lo, hi := b.span(i)
+		band := b.Data[i*b.width()+lo-i+b.KL:]
+		sum := 0.
+		for j := lo; j < hi; j++ {
+			sum += band[j-lo] * (*v)[j]
+		}
+		res[i] = sum
+	}
+	return &res
+}
+
+// Mul returns b * t
+func (b *BandMatrix) Mul(t *Matrix) *Matrix {
+	row, col := t.Dims()
+	if b.Cols != row {
+		panic(dimsError("BandMatrix.Mul", b.Rows, b.Cols, row, col))
+	}
+	nt := ZeroMatrix(b.Rows, col)
+	for i := range nt.Data {
+		out := nt.Data[i]
+		lo, hi := b.span(i)
+		band := b.Data[i*b.width()+lo-i+b.KL:]
+		for j := lo; j < hi; j++ {
+			a := band[j-lo]
+			if a == 0 {
+				continue
+			}
+			for c, x := range t.Data[j] {
+				out[c] += a * x
+			}
+		}
+	}
+	return nt
+}
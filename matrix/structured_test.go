@@ -0,0 +1,163 @@
+package matrix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiagonalMatrix(t *testing.T) {
+	d := NewDiagonalMatrix(&Vector{2, -1, 4})
+	dense := d.ToMatrix()
+	a := GenerateRandomMatrix(3, 4)
+	if !MEqual(d.Mul(a), dense.Mul(a)) || !MEqual(d.MulRight(a.T()), a.T().Mul(dense)) {
+		t.Fail()
+	}
+	v := GenerateRandomVector(3)
+	if !VEqual(d.MulVec(v), dense.MulVec(v)) || !VEqual(d.MulVec(d.Solve(v)), v) || d.Det() != -8 {
+		t.Fail()
+	}
+	if !MEqual(d.Inverse().ToMatrix(), dense.Inverse()) || !MEqual(AsMatrix(IdentityDiagonal(3)), IdentityMatrix(3)) {
+		t.Fail()
+	}
+	d.Set(0, 1, 0)
+	d.Set(1, 1, 0)
+	if _, err := d.TrySolve(v); !errors.Is(err, ErrSingular) {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	d.Set(0, 1, 1)
+}
+
+func TestTriangularMatrix(t *testing.T) {
+	a := GenerateRandomSquareMatrix(5)
+	b := GenerateRandomMatrix(5, 3)
+	for _, upper := range []bool{false, true} {
+		for _, unit := range []bool{false, true} {
+			tr := NewTriangularMatrix(a, upper, unit)
+			dense := tr.ToMatrix()
+			// rows are written into the block of ZeroMatrix, nothing else is allocated
+			if testing.AllocsPerRun(10, func() { tr.ToMatrix() }) != testing.AllocsPerRun(10, func() { ZeroMatrix(5, 5) }) {
+				t.Fail()
+			}
+			for i := 0; i < 5; i++ {
+				for j := 0; j < 5; j++ {
+					want := a.At(i, j)
+					if i == j && unit {
+						want = 1
+					} else if (upper && j < i) || (!upper && j > i) {
+						want = 0
+					}
+					if tr.At(i, j) != want || dense.At(i, j) != want || tr.Col(j).At(i) != want {
+						t.Fatal(upper, unit, i, j)
+					}
+				}
+			}
+			if !MEqual(tr.Mul(b), dense.Mul(b)) || !VEqual(tr.MulVec(b.Col(0)), dense.MulVec(b.Col(0))) {
+				t.Fatal(upper, unit)
+			}
+			if !MEqual(tr.T().ToMatrix(), dense.T()) || tr.T().Upper == upper {
+				t.Fatal(upper, unit)
+			}
+			if !MEqual(tr.Mul(tr.SolveMatrix(b)), b) || !VEqual(tr.MulVec(tr.Solve(b.Col(1))), b.Col(1)) {
+				t.Fatal(upper, unit)
+			}
+			if !FloatEqual(tr.Det(), dense.Det()) {
+				t.Fatal(upper, unit)
+			}
+		}
+	}
+	tr := ZeroTriangularMatrix(3, false, false)
+	tr.Set(1, 0, 2)
+	tr.Set(0, 2, 0)
+	if _, err := tr.TrySolve(&Vector{1, 1, 1}); !errors.Is(err, ErrSingular) || tr.At(1, 0) != 2 {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	tr.Set(0, 2, 1)
+}
+
+func TestSymmetricMatrix(t *testing.T) {
+	x := GenerateRandomMatrix(20, 4)
+	cov := x.CovSymmetricMatrix()
+	dense := cov.ToMatrix()
+	if !MEqual(dense, x.CovMatrix()) || !MEqual(NewSymmetricMatrix(dense).ToMatrix(), dense) {
+		t.Fail()
+	}
+	b := GenerateRandomMatrix(4, 2)
+	if !MEqual(cov.Mul(b), dense.Mul(b)) || !VEqual(cov.MulVec(b.Col(0)), dense.MulVec(b.Col(0))) {
+		t.Fail()
+	}
+	if !MEqual(cov.Add(cov).ToMatrix(), dense.Add(dense)) || !MEqual(cov.MulNum(3).ToMatrix(), dense.MulNum(3)) {
+		t.Fail()
+	}
+	cov.Set(0, 3, 7)
+	if cov.At(3, 0) != 7 || !VEqual(cov.Row(3), cov.Col(3)) {
+		t.Fail()
+	}
+
+	spd := x.T().Mul(x)
+	L := CholeskyTriangular(spd)
+	if L.Upper || !MEqual(L.ToMatrix(), CholeskyDecomposition(spd)) || !MEqual(L.Mul(L.T().ToMatrix()), spd) {
+		t.Fail()
+	}
+	if !MEqual(CholeskyFactorize(spd).Triangular().ToMatrix(), L.ToMatrix()) {
+		t.Fail()
+	}
+	if _, err := NewSymmetricMatrix(new(Matrix).Init(Data{{1, 2}, {2, 1}})).TryCholesky(); !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Fail()
+	}
+	if _, err := TryCholeskyTriangular(ZeroMatrix(2, 3)); !errors.Is(err, ErrNotSquare) {
+		t.Fail()
+	}
+}
+
+func TestBandMatrix(t *testing.T) {
+	for _, dims := range [][4]int{{6, 6, 1, 1}, {7, 4, 2, 0}, {3, 6, 0, 2}, {5, 5, 0, 0}} {
+		a := GenerateRandomMatrix(dims[0], dims[1])
+		b := NewBandMatrix(a, dims[2], dims[3])
+		dense := b.ToMatrix()
+		if testing.AllocsPerRun(10, func() { b.ToMatrix() }) != testing.AllocsPerRun(10, func() { ZeroMatrix(dims[0], dims[1]) }) {
+			t.Fail()
+		}
+		for i := 0; i < dims[0]; i++ {
+			for j := 0; j < dims[1]; j++ {
+				want := a.At(i, j)
+				if j < i-dims[2] || j > i+dims[3] {
+					want = 0
+				}
+				if b.At(i, j) != want || dense.At(i, j) != want || b.Col(j).At(i) != want {
+					t.Fatal(dims, i, j)
+				}
+			}
+		}
+		x := GenerateRandomMatrix(dims[1], 2)
+		if !MEqual(b.Mul(x), dense.Mul(x)) || !VEqual(b.MulVec(x.Col(0)), dense.MulVec(x.Col(0))) {
+			t.Fatal(dims)
+		}
+		if !MEqual(b.T().ToMatrix(), dense.T()) || !MEqual(AsMatrix(b), dense) {
+			t.Fatal(dims)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	ZeroBandMatrix(4, 4, 1, 0).Set(0, 2, 1)
+}
+
+func BenchmarkSymmetricMatrix_MulVec(b *testing.B) {
+	s := GenerateRandomMatrix(300, 200).CovSymmetricMatrix()
+	v := GenerateRandomVector(200)
+	for i := 0; i < b.N; i++ {
+		s.MulVec(v)
+	}
+}
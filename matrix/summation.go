@@ -0,0 +1,233 @@
+package matrix
+
+import "math"
+
+// numerically robust summation
+//	https://en.wikipedia.org/wiki/Kahan_summation_algorithm
+//	https://en.wikipedia.org/wiki/Pairwise_summation
+//	https://en.wikipedia.org/wiki/Algorithms_for_calculating_variance
+
+// pairwiseBlock is length below which `pairwiseSum` adds sequentially, error grows as O(ε log(n/pairwiseBlock))
+const pairwiseBlock = 128
+
+// pairwiseSum returns sum of x by recursive halving
+func pairwiseSum(x []float64) float64 {
+	if len(x) <= pairwiseBlock {
+		s := 0.
+		for _, v := range x {
+			s += v
+		}
+		return s
+	}
+	h := len(x) / 2
+	return pairwiseSum(x[:h]) + pairwiseSum(x[h:])
+}
+
+// compensated is running Kahan-Babuška (Neumaier) sum, lost low-order bits of every addition are kept in c
+type compensated struct {
+	sum, c float64
+}
+
+// add adds x into the running sum
+func (k *compensated) add(x float64) {
+	t := k.sum + x
+	if math.Abs(k.sum) >= math.Abs(x) {
+		k.c += (k.sum - t) + x
+	} else {
+		k.c += (x - t) + k.sum
+	}
+	k.sum = t
+}
+
+// value returns the compensated sum, Inf and NaN are returned as is
+func (k *compensated) value() float64 {
+	if math.IsInf(k.sum, 0) || math.IsNaN(k.sum) {
+		return k.sum
+	}
+	return k.sum + k.c
+}
+
+// KahanSum returns sum of vector elements by Kahan-Babuška (Neumaier) compensated summation,
+//	error does not grow with length, e.g. 1e6 + many small numbers are summed without losing the small ones
+func (v *Vector) KahanSum() float64 {
+	var k compensated
+	for _, e := range *v {
+		k.add(e)
+	}
+	return k.value()
+}
+
+// NanSum returns compensated sum of vector elements ignoring NaN, 0 if all elements are NaN
+func (v *Vector) NanSum() float64 {
+	s, _ := nanSum(*v)
+	return s
+}
+
+// nanSum returns compensated sum and number of non-NaN elements of x
+func nanSum(x []float64) (float64, int) {
+	var k compensated
+	n := 0
+	for _, e := range x {
+		if !math.IsNaN(e) {
+			k.add(e)
+			n++
+		}
+	}
+	return k.value(), n
+}
+
+// NanMean returns mean value of vector elements ignoring NaN, NaN if all elements are NaN
+func (v *Vector) NanMean() float64 {
+	s, n := nanSum(*v)
+	return s / float64(n)
+}
+
+// NanVariance returns variance (normalized by count, like `Variance`) of vector elements ignoring NaN
+func (v *Vector) NanVariance() float64 {
+	return twoPassVariance(*v, v.NanMean(), true)
+}
+
+// twoPassVariance returns Σ(x - mean)² / n by corrected two-pass algorithm, the rounding error of mean is removed
+//	by the compensation term (Σ(x - mean))² / n
+func twoPassVariance(x []float64, mean float64, skipNaN bool) float64 {
+	var d compensated
+	sq := 0.
+	n := 0
+	for _, e := range x {
+		if skipNaN && math.IsNaN(e) {
+			continue
+		}
+		d.add(e - mean)
+		sq += (e - mean) * (e - mean)
+		n++
+	}
+	s := d.value()
+	return (sq - s*s/float64(n)) / float64(n)
+}
+
+// Welford is online mean and variance accumulator by Welford's algorithm, values are pushed one by one
+//	without being stored, it stays accurate for data with large offset (e.g. UTM coordinates)
+//	the zero value is ready to use
+type Welford struct {
+	N    int
+	mean float64
+	m2   float64
+}
+
+// Push adds x into the accumulator
+func (w *Welford) Push(x float64) {
+	w.N++
+	d := x - w.mean
+	w.mean += d / float64(w.N)
+	w.m2 += d * (x - w.mean)
+}
+
+// Mean returns mean of pushed values, NaN if nothing is pushed
+func (w *Welford) Mean() float64 {
+	if w.N == 0 {
+		return math.NaN()
+	}
+	return w.mean
+}
+
+// Variance returns variance normalized by count (like `Vector.Variance`), NaN if nothing is pushed
+func (w *Welford) Variance() float64 {
+	return w.m2 / float64(w.N)
+}
+
+// SampleVariance returns unbiased variance normalized by count - 1, NaN for less than two values
+func (w *Welford) SampleVariance() float64 {
+	if w.N < 2 {
+		return math.NaN()
+	}
+	return w.m2 / float64(w.N-1)
+}
+
+// reduceSlots returns number of results and result index function of element (i, j) for reduction along dim
+//	dim: 0 -> one result per column, 1 -> one result per row, -1 -> one result of all
+func (t *Matrix) reduceSlots(op string, dim int) (int, func(i, j int) int) {
+	row, col := t.Dims()
+	switch dim {
+	case 0:
+		return col, func(_, j int) int { return j }
+	case 1:
+		return row, func(i, _ int) int { return i }
+	case -1:
+		return 1, func(_, _ int) int { return 0 }
+	default:
+		panic("invalid " + op + " dimension")
+	}
+}
+
+// sumAlong returns compensated sums and counts of elements along dim, NaN is skipped if skipNaN
+func (t *Matrix) sumAlong(op string, dim int, skipNaN bool) (*Vector, []int) {
+	n, slot := t.reduceSlots(op, dim)
+	acc := make([]compensated, n)
+	counts := make([]int, n)
+	for i := range t.Data {
+		for j, v := range t.Data[i] {
+			if skipNaN && math.IsNaN(v) {
+				continue
+			}
+			s := slot(i, j)
+			acc[s].add(v)
+			counts[s]++
+		}
+	}
+	sums := make(Vector, n)
+	for s := range acc {
+		sums[s] = acc[s].value()
+	}
+	return &sums, counts
+}
+
+// meanAlong returns means of elements along dim, NaN is skipped if skipNaN
+func (t *Matrix) meanAlong(op string, dim int, skipNaN bool) *Vector {
+	sums, counts := t.sumAlong(op, dim, skipNaN)
+	for s, c := range counts {
+		(*sums)[s] /= float64(c)
+	}
+	return sums
+}
+
+// varianceAlong returns variances of elements along dim by corrected two-pass algorithm, NaN is skipped if skipNaN
+func (t *Matrix) varianceAlong(op string, dim int, skipNaN bool) *Vector {
+	mean := t.meanAlong(op, dim, skipNaN)
+	_, slot := t.reduceSlots(op, dim)
+	acc := make([]compensated, len(*mean))
+	sq := make([]float64, len(*mean))
+	counts := make([]int, len(*mean))
+	for i := range t.Data {
+		for j, v := range t.Data[i] {
+			if skipNaN && math.IsNaN(v) {
+				continue
+			}
+			s := slot(i, j)
+			d := v - (*mean)[s]
+			acc[s].add(d)
+			sq[s] += d * d
+			counts[s]++
+		}
+	}
+	for s := range *mean {
+		d, n := acc[s].value(), float64(counts[s])
+		(*mean)[s] = (sq[s] - d*d/n) / n
+	}
+	return mean
+}
+
+// NanSum sums the matrix along certain dimension like `Sum` ignoring NaN
+func (t *Matrix) NanSum(dim int) *Vector {
+	sums, _ := t.sumAlong("sum", dim, true)
+	return sums
+}
+
+// NanMean returns mean vector of matrix along certain dimension like `Mean` ignoring NaN
+func (t *Matrix) NanMean(dim int) *Vector {
+	return t.meanAlong("mean", dim, true)
+}
+
+// NanVariance returns variance vector along input dim like `Variance` ignoring NaN
+func (t *Matrix) NanVariance(dim int) *Vector {
+	return t.varianceAlong("variance", dim, true)
+}
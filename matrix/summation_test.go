@@ -0,0 +1,128 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVector_KahanSum(t *testing.T) {
+	// naive summation loses every 1e-16 added to 1
+	v := Vector{1}
+	for i := 0; i < 10000; i++ {
+		v = append(v, 1e-16)
+	}
+	naive := 0.
+	for _, e := range v {
+		naive += e
+	}
+	if naive != 1 || v.KahanSum() != 1+1e-12 {
+		t.Fatal(naive, v.KahanSum())
+	}
+	if s := (Vector{1e100, 1, -1e100}); s.KahanSum() != 1 {
+		t.Fail()
+	}
+	if s := (Vector{math.Inf(1), 1}); !math.IsInf(s.KahanSum(), 1) || !math.IsInf(s.Sum(), 1) {
+		t.Fail()
+	}
+}
+
+func TestVector_SumPairwise(t *testing.T) {
+	v := make(Vector, 1<<20)
+	for i := range v {
+		v[i] = 0.1
+	}
+	if math.Abs(v.Sum()-v.KahanSum()) > 1e-9 || !FloatEqual(v.Mean(), 0.1) {
+		t.Fatal(v.Sum() - v.KahanSum())
+	}
+}
+
+func TestVector_VarianceOffset(t *testing.T) {
+	// UTM like coordinates: large offset, small spread
+	base := GenerateRandomVector(1000)
+	shifted := base.AddNum(1e6)
+	if math.Abs(shifted.Variance()-base.Variance()) > 1e-9*base.Variance() {
+		t.Fatal(shifted.Variance(), base.Variance())
+	}
+	if !FloatEqual(shifted.Mean()-1e6, base.Mean()) {
+		t.Fail()
+	}
+	var w Welford
+	for _, x := range *shifted {
+		w.Push(x)
+	}
+	if w.N != 1000 || !FloatEqual(w.Variance(), base.Variance()) || !FloatEqual(w.Mean(), shifted.Mean()) {
+		t.Fail()
+	}
+	if !FloatEqual(w.SampleVariance(), base.Variance()*1000/999) {
+		t.Fail()
+	}
+	var empty Welford
+	if !math.IsNaN(empty.Mean()) || !math.IsNaN(empty.Variance()) || !math.IsNaN(empty.SampleVariance()) {
+		t.Fail()
+	}
+}
+
+func TestVector_Nan(t *testing.T) {
+	v := Vector{1, math.NaN(), 2, 3, math.NaN()}
+	clean := Vector{1, 2, 3}
+	if v.NanSum() != 6 || v.NanMean() != 2 || !FloatEqual(v.NanVariance(), clean.Variance()) {
+		t.Fail()
+	}
+	if !math.IsNaN(v.Sum()) || !math.IsNaN(v.Mean()) {
+		t.Fail()
+	}
+	allNaN := Vector{math.NaN()}
+	if allNaN.NanSum() != 0 || !math.IsNaN(allNaN.NanMean()) {
+		t.Fail()
+	}
+}
+
+func TestMatrix_RobustStatistics(t *testing.T) {
+	a := GenerateRandomMatrix(50, 3)
+	shifted := a.AddNum(1e6)
+	for _, dim := range []int{0, 1, -1} {
+		va, vs := a.Variance(dim), shifted.Variance(dim)
+		for i := range *va {
+			if math.Abs(va.At(i)-vs.At(i)) > 1e-8*va.At(i) {
+				t.Fatal(dim, va.At(i), vs.At(i))
+			}
+		}
+	}
+	if !FloatEqual(a.Variance(-1).At(0), a.Flat().Variance()) || !VEqual(a.Variance(0), a.T().Variance(1)) {
+		t.Fail()
+	}
+	if !FloatEqual(a.Sum(-1).At(0), a.Flat().Sum()) || !VEqual(a.Mean(1), a.T().Mean(0)) {
+		t.Fail()
+	}
+	if !FloatEqual(a.SumCol(1), a.Col(1).Sum()) || !FloatEqual(a.SumRow(1), a.Row(1).Sum()) {
+		t.Fail()
+	}
+
+	m := new(Matrix).Init(Data{{1, math.NaN()}, {3, 4}, {math.NaN(), 6}})
+	if !VEqual(m.NanSum(0), &Vector{4, 10}) || !VEqual(m.NanMean(1), &Vector{1, 3.5, 6}) {
+		t.Fail()
+	}
+	if !VEqual(m.NanVariance(0), &Vector{1, 1}) || !FloatEqual(m.NanMean(-1).At(0), 3.5) {
+		t.Fail()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fail()
+		}
+	}()
+	m.NanSum(2)
+}
+
+func BenchmarkVector_Sum(b *testing.B) {
+	v := GenerateRandomVector(100000)
+	for i := 0; i < b.N; i++ {
+		v.Sum()
+	}
+}
+
+func BenchmarkVector_KahanSum(b *testing.B) {
+	v := GenerateRandomVector(100000)
+	for i := 0; i < b.N; i++ {
+		v.KahanSum()
+	}
+}
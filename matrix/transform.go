@@ -177,8 +177,8 @@ func Kabsch(P, Q *Matrix) (linear *Matrix, translation *Vector) { // X -> AX + B
 	Q = Q.MulNum(1. / scale)
 
 	// move to centroid
-	centeredP := P.Sub(P.Mean(0).Tile(0, rp))
-	centeredQ := Q.Sub(Q.Mean(0).Tile(0, rq))
+	centeredP := P.SubElem(P.Mean(0))
+	centeredQ := Q.SubElem(Q.Mean(0))
 	// SVD
 	U, _, V := SVD(centeredP.T().Mul(centeredQ))
 	// Rotation
@@ -204,3 +204,70 @@ func ToAffineMatrix(t *Matrix) *Matrix {
 	nt.Set(row, col, 1)
 	return nt
 }
+
+// Skew returns 3 x 3 skew-symmetric (cross product) matrix [w]x of 3D vector w, [w]x * v = w x v
+func Skew(w *Vector) *Matrix {
+	if w.Length() != 3 {
+		panic(lenError("Skew", 3, w.Length()))
+	}
+	x, y, z := w.At(0), w.At(1), w.At(2)
+	return new(Matrix).Init(Data{{0, -z, y}, {z, 0, -x}, {-y, x, 0}})
+}
+
+// SO3Exp returns rotation matrix e^[w]x of rotation vector w (unit axis * angle in radians) by Rodrigues' formula
+//	https://en.wikipedia.org/wiki/Rodrigues%27_rotation_formula
+//	R = I + sin(θ) / θ * [w]x + (1 - cos(θ)) / θ^2 * [w]x^2, θ = |w|, Taylor series are used for tiny θ
+func SO3Exp(w *Vector) *Matrix {
+	K := Skew(w)
+	theta := w.Norm()
+	var a, b float64
+	if theta > 1e-4 {
+		a, b = sin(theta)/theta, (1-cos(theta))/(theta*theta)
+	} else {
+		a, b = 1-theta*theta/6, 0.5-theta*theta/24
+	}
+	return IdentityMatrix(3).Add(K.MulNum(a)).Add(K.Mul(K).MulNum(b))
+}
+
+// SO3Log returns rotation vector w of rotation matrix R, R = SO3Exp(w) and |w| in [0, pi]
+//	it is the inverse of `SO3Exp`, e.g. rotation of `Kabsch` (linear transformation divided by its scale) to axis-angle,
+//	angle θ = atan2(|v| / 2, (trace(R) - 1) / 2) with v = vee(R - R'), axis is read from R + R' when θ is close to pi
+func SO3Log(R *Matrix) *Vector {
+	if m, n := R.Dims(); m != 3 || n != 3 {
+		panic(dimsError("SO3Log", m, n, 3, 3))
+	}
+	v := Vector{R.At(2, 1) - R.At(1, 2), R.At(0, 2) - R.At(2, 0), R.At(1, 0) - R.At(0, 1)}
+	c := (R.Trace() - 1) / 2
+	s := v.Norm() / 2
+	theta := math.Atan2(s, c)
+	if theta < 1e-4 {
+		// θ / (2 * sin(θ)) ≈ (1 + θ^2 / 6) / 2
+		return v.MulNum((1 + theta*theta/6) / 2)
+	}
+	if theta < math.Pi-1e-4 {
+		return v.MulNum(theta / (2 * s))
+	}
+	// R + R' = 2 * cos(θ) * I + 2 * (1 - cos(θ)) * n * n', pick the largest component of axis n first
+	k := 0
+	for i := 1; i < 3; i++ {
+		if R.At(i, i) > R.At(k, k) {
+			k = i
+		}
+	}
+	axis := make(Vector, 3)
+	axis[k] = math.Sqrt(math.Max(0, (R.At(k, k)-c)/(1-c)))
+	for i := 0; i < 3; i++ {
+		if i != k {
+			axis[i] = (R.At(i, k) + R.At(k, i)) / (2 * (1 - c) * axis[k])
+		}
+	}
+	if axis.Dot(&v) < 0 {
+		axis = *axis.MulNum(-1)
+	}
+	return axis.Normalize().MulNum(theta)
+}
+
+// SO3Interpolate returns rotation R0 * e^(t * log(R0' * R1)) along the geodesic, R0 for t = 0 and R1 for t = 1
+func SO3Interpolate(R0, R1 *Matrix, t float64) *Matrix {
+	return R0.Mul(SO3Exp(SO3Log(R0.T().Mul(R1)).MulNum(t)))
+}
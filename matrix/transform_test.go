@@ -110,6 +110,40 @@ func TestKabsch(t *testing.T) {
 	}
 }
 
+func TestSO3ExpLog(t *testing.T) {
+	axis := (&Vector{1, 2, -2}).Normalize()
+	for _, angle := range []float64{0, 1e-9, 1e-3, 0.5, 2, math.Pi - 1e-6, math.Pi} {
+		w := axis.MulNum(angle)
+		R := SO3Exp(w)
+		// rows of rotated identity are columns of R
+		if !MEqual(R.T(), Rotate3D(IdentityMatrix(3), angle*180/math.Pi, axis)) || !MEqual(R, Expm(Skew(w))) {
+			t.Fatal(angle)
+		}
+		if !MEqual(R.T().Mul(R), IdentityMatrix(3)) || !FloatEqual(R.Det(), 1) {
+			t.Fatal(angle)
+		}
+		if wLog := SO3Log(R); !FloatEqual(wLog.Norm(), angle) || !MEqual(SO3Exp(wLog), R) {
+			t.Fatal(angle, wLog)
+		}
+		if angle < math.Pi && !VEqual(SO3Log(R), w) {
+			t.Fatal(angle)
+		}
+	}
+	// half way rotation
+	w := axis.MulNum(1.2)
+	R0 := SO3Exp(&Vector{0.3, -0.1, 0.2})
+	R1 := R0.Mul(SO3Exp(w))
+	if !MEqual(SO3Interpolate(R0, R1, 0.5), R0.Mul(SO3Exp(w.MulNum(0.5)))) || !MEqual(SO3Interpolate(R0, R1, 1), R1) {
+		t.Fail()
+	}
+	// rotation recovered by `Kabsch`
+	P := GenerateRandomMatrix(10, 3)
+	linear, _ := Kabsch(P, Rotate3D(P, 60, axis))
+	if !VEqual(SO3Log(linear), axis.MulNum(math.Pi/3)) {
+		t.Fail()
+	}
+}
+
 func BenchmarkRotate3D(b *testing.B) {
 	for k := 1.0; k <= 3; k++ {
 		n := int(math.Pow(10, k))
@@ -1,10 +1,10 @@
 package matrix
 
 import (
-	"fmt"
 	"math"
 	"math/rand"
-	"os"
+	"runtime"
+	"sync"
 	"time"
 )
 
@@ -185,134 +185,177 @@ func GenerateRandomSparseMatrix(rows, cols, entriesNum int) *SparseMatrix {
 	return nsm
 }
 
-/*
-// Vector to iterable
-func VectorIter(v *Vector) interface{} {
+// VectorIter returns elements of vector for typed functional helpers (`Map`, `Reduce`, `Filter`, ...), it is a view
+func VectorIter(v *Vector) []float64 {
 	return *v
 }
 
-// Matrix to row iterable
-func MatrixRowIter(t *Matrix) interface{} {
+// MatrixRowIter returns rows of matrix for typed functional helpers, rows are views of the matrix
+func MatrixRowIter(t *Matrix) []Vector {
 	return t.Data
 }
 
-// Matrix to element iterable
-// 	row-wise
-func MatrixElementIter(t *Matrix) interface{} {
-	return *(t.Flat())
+// MatrixElementIter returns all elements of matrix row-wise for typed functional helpers (copy)
+func MatrixElementIter(t *Matrix) []float64 {
+	return *t.Flat()
 }
 
-// Map function on iterable
-func Map(input interface{}, mapper func(interface{}) interface{}) (output interface{}) {
-	val := reflect.ValueOf(input)
-	out := make([]interface{}, val.Len())
-	wg := &sync.WaitGroup{}
-	for i := 0; i < val.Len(); i++ {
-		wg.Add(1)
-		go func(i int) {
-			wg.Done()
-			out[i] = mapper(val.Index(i).Interface())
-		}(i)
+// parallelGrain is minimum number of elements handled by one goroutine in parallel helpers,
+//	inputs shorter than it are processed sequentially
+const parallelGrain = 4096
+
+// parallelChunks returns number and size of chunks [0, n) is split into by parallel helpers
+func parallelChunks(n int) (chunks, size int) {
+	chunks = MaxInt(1, MinInt(runtime.NumCPU(), (n+parallelGrain-1)/parallelGrain))
+	size = (n + chunks - 1) / chunks
+	return chunks, size
+}
+
+// parallelRange runs fn on c-th chunk [i, j) of [0, n) concurrently, chunks follow `parallelChunks`
+func parallelRange(n int, fn func(c, i, j int)) {
+	chunks, size := parallelChunks(n)
+	if chunks == 1 {
+		fn(0, 0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(chunks)
+	for c := 0; c < chunks; c++ {
+		go func(c int) {
+			defer wg.Done()
+			fn(c, MinInt(c*size, n), MinInt((c+1)*size, n))
+		}(c)
 	}
 	wg.Wait()
+}
+
+// Map applies mapper to every element of input, returns a new slice of results
+//	e.g. Map(VectorIter(v), math.Abs), Map(MatrixRowIter(t), func(r Vector) float64 { return r.Sum() })
+func Map[S ~[]E, E, R any](input S, mapper func(E) R) []R {
+	out := make([]R, len(input))
+	for i, e := range input {
+		out[i] = mapper(e)
+	}
 	return out
 }
 
-// Reduce iterable by function
-func Reduce(input interface{}, reducer func(interface{}, interface{}) interface{}) interface{} {
-	val := reflect.ValueOf(input)
-	tmp := val.Index(0).Interface()
-	for i := 0; i < val.Len()-1; i++ {
-		tmp = reducer(tmp, val.Index(i).Interface())
+// MapInPlace replaces every element of input by mapper result, returns input
+func MapInPlace[S ~[]E, E any](input S, mapper func(E) E) S {
+	for i, e := range input {
+		input[i] = mapper(e)
 	}
-	return tmp
+	return input
 }
 
-// Filter iterable by function
-func Filter(input interface{}, filter func(interface{}) bool) interface{} {
-	val := reflect.ValueOf(input)
-	out := make([]interface{}, 0, val.Len())
-	for i := 0; i < val.Len(); i++ {
-		if filter(val.Index(i).Interface()) {
-			out = append(out, val.Index(i).Interface())
+// ParallelMap is `Map` with input split among goroutines, mapper must be safe for concurrent use
+func ParallelMap[S ~[]E, E, R any](input S, mapper func(E) R) []R {
+	out := make([]R, len(input))
+	parallelRange(len(input), func(_, i, j int) {
+		for k := i; k < j; k++ {
+			out[k] = mapper(input[k])
 		}
-	}
+	})
 	return out
 }
-*/
 
-func getFileSize(filename string) int64 {
-	fileStat, err := os.Stat(filename)
-	if err != nil {
-		panic(err)
-	}
-	fileSize := fileStat.Size()
-	return fileSize
+// ParallelMapInPlace is `MapInPlace` with input split among goroutines, mapper must be safe for concurrent use
+func ParallelMapInPlace[S ~[]E, E any](input S, mapper func(E) E) S {
+	parallelRange(len(input), func(_, i, j int) {
+		for k := i; k < j; k++ {
+			input[k] = mapper(input[k])
+		}
+	})
+	return input
 }
 
-// Load3DToMatrix reads 3D data into matrix
-func Load3DToMatrix(path string) (*Matrix, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// Reduce folds input from left to right starting with init, returns init for empty input
+//	e.g. Reduce(VectorIter(v), 0., func(acc, x float64) float64 { return acc + x })
+func Reduce[S ~[]E, E, A any](input S, init A, reducer func(A, E) A) A {
+	acc := init
+	for _, e := range input {
+		acc = reducer(acc, e)
 	}
-	defer file.Close()
+	return acc
+}
 
-	fileSize := getFileSize(path)
-	est := 3 * fileSize / (4*8*3 + 1*2)
-	lines := make(Data, 0, est)
+// ParallelReduce folds chunks of input concurrently then combines partial results in order,
+//	reducer must be associative and identity must be its neutral element (e.g. 0 for +, 1 for *)
+func ParallelReduce[S ~[]E, E any](input S, identity E, reducer func(E, E) E) E {
+	chunks, _ := parallelChunks(len(input))
+	partial := make([]E, chunks)
+	parallelRange(len(input), func(c, i, j int) {
+		partial[c] = Reduce(input[i:j], identity, reducer)
+	})
+	return Reduce(partial, identity, reducer)
+}
 
-	var x, y, z float64
-	for {
-		rowNum, err := fmt.Fscanln(file, &x, &y, &z)
-		if rowNum == 0 || err != nil {
-			break
+// Filter returns a new slice of elements satisfying keep, order preserved
+func Filter[S ~[]E, E any](input S, keep func(E) bool) S {
+	out := make(S, 0, len(input))
+	for _, e := range input {
+		if keep(e) {
+			out = append(out, e)
 		}
-		lines = append(lines, Vector{x, y, z})
 	}
-	return new(Matrix).Init(lines), err
+	return out
 }
 
-// Load2DToMatrix reads 2D data into matrix
-func Load2DToMatrix(path string) (*Matrix, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// FilterInPlace moves elements satisfying keep to the front of input without allocation, returns the shortened slice
+func FilterInPlace[S ~[]E, E any](input S, keep func(E) bool) S {
+	n := 0
+	for _, e := range input {
+		if keep(e) {
+			input[n] = e
+			n++
+		}
 	}
-	defer file.Close()
-
-	fileSize := getFileSize(path)
-	est := 3 * fileSize / (4*8*2 + 1)
-	lines := make(Data, 0, est)
+	return input[:n]
+}
 
-	var x, y float64
-	for {
-		rowNum, err := fmt.Fscanln(file, &x, &y)
-		if rowNum == 0 || err != nil {
-			break
+// MapElements applies f to every element of matrix, returns a new matrix
+func MapElements(t *Matrix, f func(float64) float64) *Matrix {
+	row, col := t.Dims()
+	nt := ZeroMatrix(row, col)
+	for i := range t.Data {
+		for j, v := range t.Data[i] {
+			nt.Data[i][j] = f(v)
 		}
-		lines = append(lines, Vector{x, y})
 	}
-	return new(Matrix).Init(lines), err
+	return nt
 }
 
-// WriteMatrixToTxt writes matrix data into file
-func WriteMatrixToTxt(path string, t *Matrix) error {
-	file, err := os.Create(path)
-	if err != nil {
-		return err
+// MapElementsInPlace replaces every element of matrix by f result, returns t
+func MapElementsInPlace(t *Matrix, f func(float64) float64) *Matrix {
+	for i := range t.Data {
+		MapInPlace(t.Data[i], f)
 	}
-	defer file.Close()
-
-	_, c := t.Dims()
+	return t
+}
 
-	for i := range t.Data {
-		for j := range t.Data[i] {
-			_, err = fmt.Fprintf(file, "%f ", t.Data[i][j])
-			if j == c-1 {
-				_, err = fmt.Fprintf(file, "\n")
-			}
+// ParallelMapElementsInPlace is `MapElementsInPlace` with elements split among goroutines,
+//	f must be safe for concurrent use
+func ParallelMapElementsInPlace(t *Matrix, f func(float64) float64) *Matrix {
+	_, col := t.Dims()
+	parallelRange(len(t.Data)*col, func(_, i, j int) {
+		for k := i; k < j; k++ {
+			r, c := k/col, k%col
+			t.Data[r][c] = f(t.Data[r][c])
 		}
-	}
-	return err
+	})
+	return t
+}
+
+// Load3DToMatrix reads 3D data (first three whitespace separated columns) into matrix
+func Load3DToMatrix(path string) (*Matrix, error) {
+	return LoadText(path, TextReadOptions{Comment: "#", Columns: []int{0, 1, 2}})
+}
+
+// Load2DToMatrix reads 2D data (first two whitespace separated columns) into matrix
+func Load2DToMatrix(path string) (*Matrix, error) {
+	return LoadText(path, TextReadOptions{Comment: "#", Columns: []int{0, 1}})
+}
+
+// WriteMatrixToTxt writes matrix data into file, space separated with 6 decimal places
+func WriteMatrixToTxt(path string, t *Matrix) error {
+	return SaveText(path, t, TextWriteOptions{Format: 'f', Precision: 6})
 }
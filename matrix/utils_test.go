@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"math"
 	"os"
 	"testing"
 )
@@ -28,41 +29,85 @@ func TestVEqual(t *testing.T) {
 	}
 }
 
-/*
 func TestMap(t *testing.T) {
 	v := GenerateRandomVector(10)
-	m := Map(VectorIter(v), func(item interface{}) interface{} {
-		return item.(float64)
+	m := Map(VectorIter(v), func(item float64) float64 {
+		return item * 2
 	})
-	switch reflect.TypeOf(m).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(m)
-		for i := 0; i < s.Len(); i++ {
-			if reflect.ValueOf(s.Index(i).Interface()).Convert(reflect.TypeOf(float64(0))).Float() != v.At(i) {
-				t.Fail()
-			}
+	for i, x := range m {
+		if x != v.At(i)*2 {
+			t.Fail()
+		}
+	}
+	// result type may differ from element type
+	signs := Map(*v, func(item float64) bool { return item > 0 })
+	if len(signs) != 10 || signs[3] != (v.At(3) > 0) {
+		t.Fail()
+	}
+	w := &Vector{}
+	*w = append(*w, *v...)
+	if MapInPlace(*w, math.Abs); !VEqual(w, v.MapFloat(math.Abs)) {
+		t.Fail()
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	for _, n := range []int{0, 10, 3*parallelGrain + 7} {
+		v := GenerateRandomVector(n)
+		want := Map(*v, math.Exp)
+		if got := ParallelMap(*v, math.Exp); len(got) != n || !VEqual((*Vector)(&got), (*Vector)(&want)) {
+			t.Fatal(n)
+		}
+		if got := ParallelMapInPlace(append(Vector{}, *v...), math.Exp); !VEqual(&got, (*Vector)(&want)) {
+			t.Fatal(n)
 		}
 	}
 }
 
 func TestReduce(t *testing.T) {
 	v := GenerateRandomVector(10)
-	m := Reduce(VectorIter(v), func(item0, item1 interface{}) interface{} {
-		return item0.(float64) + item1.(float64)
+	sum := Reduce(VectorIter(v), 0., func(acc, item float64) float64 {
+		return acc + item
 	})
-	switch reflect.TypeOf(m).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(m)
-		if reflect.ValueOf(s.Interface()).Convert(reflect.TypeOf(float64(0))).Float() != v.Sum() {
-			t.Fail()
-		}
+	if !FloatEqual(sum, v.Sum()) || Reduce(Vector{}, 1., math.Max) != 1 {
+		t.Fail()
+	}
+	// accumulator type may differ from element type
+	count := Reduce(*v, 0, func(acc int, item float64) int {
+		return acc + int(Ternary(item > 0, 1, 0).(int))
+	})
+	if count != len(Filter(*v, func(item float64) bool { return item > 0 })) {
+		t.Fail()
+	}
+	big := GenerateRandomVector(5*parallelGrain + 3)
+	if !FloatEqual(ParallelReduce(*big, 0., func(a, b float64) float64 { return a + b }), big.Sum()) {
+		t.Fail()
+	}
+	if _, largest := big.Max(); ParallelReduce(*big, math.Inf(-1), math.Max) != largest || ParallelReduce(Vector{}, 0., math.Max) != 0 {
+		t.Fail()
 	}
 }
 
-func TestFilter(t *testing.T) {
+func TestVectorIter(t *testing.T) {
 	v := GenerateRandomVector(10)
-	m := Filter(VectorIter(v), func(item interface{}) bool {
-		return item.(float64) > 0
+	m := Map(VectorIter(v), func(item float64) float64 {
+		return item * 2
+	})
+	if mv := Vector(m); !VEqual(&mv, v.MulNum(2)) {
+		t.Fail()
+	}
+	// view of the vector, not a copy
+	VectorIter(v)[3] = 42
+	if v.At(3) != 42 {
+		t.Fail()
+	}
+}
+
+func TestFilter(t *testing.T) {
+	// fixed input, so the filtered result is never empty
+	v := &Vector{0.5, -1, 2, 0, -3, 4, 1e-9, -1e-9, 7, -8}
+	m := Filter(VectorIter(v), func(item float64) bool {
+		return item > 0
 	})
 	vv := make(Vector, 0, 10)
 	for _, j := range *v {
@@ -70,69 +115,70 @@ func TestFilter(t *testing.T) {
 			vv = append(vv, j)
 		}
 	}
-	switch reflect.TypeOf(m).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(m)
-		for i := 0; i < s.Len(); i++ {
-			if reflect.ValueOf(s.Index(i).Interface()).Convert(reflect.TypeOf(float64(0))).Float() != vv.At(i) {
-				t.Fail()
-			}
-		}
+	if mv := Vector(m); !VEqual(&mv, &vv) {
+		t.Fail()
 	}
-}
-
-func TestVectorIter(t *testing.T) {
-	v := GenerateRandomVector(10)
-	m := Map(VectorIter(v), func(item interface{}) interface{} {
-		return item.(float64) * 2
-	})
-	switch reflect.TypeOf(m).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(m)
-		for i := 0; i < s.Len(); i++ {
-			if reflect.ValueOf(s.Index(i).Interface()).Convert(reflect.TypeOf(float64(0))).Float() != (v.At(i) * 2) {
-				t.Fail()
-			}
-		}
+	// Vector in, Vector out
+	w := &Vector{}
+	*w = append(*w, *v...)
+	if f := FilterInPlace(*w, func(item float64) bool { return item > 0 }); !VEqual(&f, &vv) || &f[0] != &(*w)[0] {
+		t.Fail()
 	}
 }
 
 func TestMatrixRowIter(t *testing.T) {
 	mat := GenerateRandomMatrix(10, 3)
-	m := Map(MatrixRowIter(mat), func(item interface{}) interface{} {
-		// https://stackoverflow.com/questions/44543374/cannot-take-the-address-of-and-cannot-call-pointer-method-on
-		v := item.(Vector)
-		return v.Sum()
+	m := Map(MatrixRowIter(mat), func(item Vector) float64 {
+		return item.Sum()
 	})
-	switch reflect.TypeOf(m).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(m)
-		for i := 0; i < s.Len(); i++ {
-			v := mat.Row(i)
-			if reflect.ValueOf(s.Index(i).Interface()).Convert(reflect.TypeOf(float64(0))).Float() != v.Sum() {
-				t.Fail()
-			}
+	for i, x := range m {
+		v := mat.Row(i)
+		if x != v.Sum() {
+			t.Fail()
+		}
+	}
+	rows := Filter(MatrixRowIter(mat), func(item Vector) bool { return item[0] > 0 })
+	for _, r := range rows {
+		if r[0] <= 0 {
+			t.Fail()
 		}
 	}
 }
 
 func TestMatrixElementIter(t *testing.T) {
 	mat := GenerateRandomMatrix(10, 3)
-	m := Map(MatrixElementIter(mat), func(item interface{}) interface{} {
-		return item.(float64) * 2
+	m := Map(MatrixElementIter(mat), func(item float64) float64 {
+		return item * 2
 	})
-	switch reflect.TypeOf(m).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(m)
-		v := mat.Flat()
-		for i := 0; i < s.Len(); i++ {
-			if reflect.ValueOf(s.Index(i).Interface()).Convert(reflect.TypeOf(float64(0))).Float() != (v.At(i) * 2) {
-				t.Fail()
-			}
+	v := mat.Flat()
+	for i, x := range m {
+		if x != v.At(i)*2 {
+			t.Fail()
 		}
 	}
+	if !MEqual(MapElements(mat, math.Abs), MapElementsInPlace(Copy(mat), math.Abs)) {
+		t.Fail()
+	}
+	big := GenerateRandomMatrix(parallelGrain, 3)
+	if !MEqual(ParallelMapElementsInPlace(Copy(big), math.Exp), MapElements(big, math.Exp)) {
+		t.Fail()
+	}
+}
+
+func BenchmarkMap(b *testing.B) {
+	v := GenerateRandomVector(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		MapInPlace(*v, math.Sin)
+	}
+}
+
+func BenchmarkParallelMap(b *testing.B) {
+	v := GenerateRandomVector(100000)
+	for i := 0; i < b.N; i++ {
+		ParallelMapInPlace(*v, math.Sin)
+	}
 }
-*/
 
 func TestLoad3DToMatrix(t *testing.T) {
 	mat := GenerateRandomMatrix(10, 3)
@@ -25,14 +25,21 @@ func (v *Vector) At(n int) float64 {
 // Add adds two vectors and returns a new vector
 //	notice: two vectors should have the same length otherwise it will panic
 func (v *Vector) Add(v1 *Vector) *Vector {
+	res, err := v.TryAdd(v1)
+	must(err)
+	return res
+}
+
+// TryAdd adds two vectors and returns a new vector, ErrDimensionMismatch if lengths are different
+func (v *Vector) TryAdd(v1 *Vector) (*Vector, error) {
 	if len(*v) != len(*v1) {
-		panic("add requires equal-length vectors")
+		return nil, lenError("Vector.Add", len(*v), len(*v1))
 	}
 	res := make(Vector, len(*v))
 	for i := range *v {
 		res[i] = (*v)[i] + (*v1)[i]
 	}
-	return &res
+	return &res, nil
 }
 
 // AddNum adds input number to all elements inside and returns a new vector
@@ -46,14 +53,21 @@ func (v *Vector) AddNum(n interface{}) *Vector {
 
 // Sub subtracts two vectors and returns a new vector
 func (v *Vector) Sub(v1 *Vector) *Vector {
+	res, err := v.TrySub(v1)
+	must(err)
+	return res
+}
+
+// TrySub subtracts two vectors and returns a new vector, ErrDimensionMismatch if lengths are different
+func (v *Vector) TrySub(v1 *Vector) (*Vector, error) {
 	if len(*v) != len(*v1) {
-		panic("sub requires equal-length vectors")
+		return nil, lenError("Vector.Sub", len(*v), len(*v1))
 	}
 	res := make(Vector, len(*v))
 	for i := range *v {
 		res[i] = (*v)[i] - (*v1)[i]
 	}
-	return &res
+	return &res, nil
 }
 
 // SubNum subtracts vector with number and returns a new vector
@@ -76,14 +90,21 @@ func (v *Vector) MulNum(n interface{}) *Vector {
 
 // Dot returns vector dot production
 func (v *Vector) Dot(v1 *Vector) float64 {
+	res, err := v.TryDot(v1)
+	must(err)
+	return res
+}
+
+// TryDot returns vector dot production, ErrDimensionMismatch if lengths are different
+func (v *Vector) TryDot(v1 *Vector) (float64, error) {
 	if len(*v) != len(*v1) {
-		panic("dot product requires equal-length vectors")
+		return 0, lenError("Vector.Dot", len(*v), len(*v1))
 	}
 	res := 0.
 	for i := range *v {
 		res += (*v)[i] * (*v1)[i]
 	}
-	return res
+	return res, nil
 }
 
 // OuterProduct returns vector outer product: v1, v2 -> matrix
@@ -101,10 +122,17 @@ func (v *Vector) OuterProduct(v1 *Vector) *Matrix {
 
 // Cross returns vector cross product, 3D only
 func (v *Vector) Cross(v1 *Vector) *Vector {
+	res, err := v.TryCross(v1)
+	must(err)
+	return res
+}
+
+// TryCross returns vector cross product, ErrDimensionMismatch if any of vectors is not 3D
+func (v *Vector) TryCross(v1 *Vector) (*Vector, error) {
 	if len(*v) != len(*v1) || len(*v) != 3 {
-		panic("cross product requires 3d vectors in 3d space!")
+		return nil, fmt.Errorf("Vector.Cross: %w (length %d vs %d, requires 3d vectors)", ErrDimensionMismatch, len(*v), len(*v1))
 	}
-	return &Vector{(*v)[1]*(*v1)[2] - (*v)[2]*(*v1)[1], (*v)[2]*(*v1)[0] - (*v)[0]*(*v1)[2], (*v)[0]*(*v1)[1] - (*v)[1]*(*v1)[0]}
+	return &Vector{(*v)[1]*(*v1)[2] - (*v)[2]*(*v1)[1], (*v)[2]*(*v1)[0] - (*v)[0]*(*v1)[2], (*v)[0]*(*v1)[1] - (*v)[1]*(*v1)[0]}, nil
 }
 
 // SquareSum returns vector elements square sum
@@ -120,15 +148,22 @@ func (v *Vector) Norm() float64 {
 
 // Normalize normalizes vector
 func (v *Vector) Normalize() *Vector {
+	res, err := v.TryNormalize()
+	must(err)
+	return res
+}
+
+// TryNormalize normalizes vector, ErrZeroVector if its norm is 0
+func (v *Vector) TryNormalize() (*Vector, error) {
 	n := v.Norm()
 	if n == 0 {
-		panic("invalid input vector with norm equal to 0")
+		return nil, fmt.Errorf("Vector.Normalize: %w", ErrZeroVector)
 	}
 	res := make(Vector, len(*v))
 	for i := range *v {
 		res[i] = (*v)[i] / n
 	}
-	return &res
+	return &res, nil
 }
 
 // ToMatrix transfers vector to matrix, row-wise
@@ -145,13 +180,9 @@ func (v *Vector) ToMatrix(rows, cols int) *Matrix {
 	return nt
 }
 
-// Sum returns sum of vector's elements
+// Sum returns sum of vector's elements by pairwise summation, error grows only logarithmically with length
 func (v *Vector) Sum() float64 {
-	s := 0.
-	for _, e := range *v {
-		s += e
-	}
-	return s
+	return pairwiseSum(*v)
 }
 
 // AbsSum returns sum of vector elements' absolute value
@@ -168,9 +199,9 @@ func (v *Vector) Mean() float64 {
 	return v.Sum() / float64(len(*v))
 }
 
-// Variance returns variance value of vector
+// Variance returns variance value of vector by corrected two-pass algorithm, accurate for data with large offset
 func (v *Vector) Variance() float64 {
-	return v.SubNum(v.Mean()).SquareSum() / float64(v.Length())
+	return twoPassVariance(*v, v.Mean(), false)
 }
 
 // StandardDeviation returns standard deviation of vector
@@ -418,19 +449,13 @@ func ARRange(start, step, stop int) *Vector {
 
 // MapFloat maps input func (float64) to all elements inside the vector
 func (v *Vector) MapFloat(f func(float64) float64) *Vector {
-	nv := make(Vector, v.Length())
-	for i := range *v {
-		nv[i] = f((*v)[i])
-	}
+	nv := Vector(Map(*v, f))
 	return &nv
 }
 
 // MapInt maps input func (int) to all elements inside the vector
 func (v *Vector) MapInt(f func(float64) int) *[]int {
-	nv := make([]int, v.Length())
-	for i := range *v {
-		nv[i] = f((*v)[i])
-	}
+	nv := Map(*v, f)
 	return &nv
 }
 
@@ -60,7 +60,7 @@ func (pwv PointsWithVoxelID) String() string {
 // voxel_id = depth_idx * (cols * rows) + row_idx * cols + col_idx
 // []int
 func (g *Grid) ConvertXYZToVoxelID() PointsWithVoxelID {
-	idx := g.Points.Sub(g.MinXYZ.Tile(0, g.NumOfPoints))
+	idx := g.Points.SubElem(g.MinXYZ.Vector)
 	idxX := idx.Col(0).MulNum(1. / g.VoxelSize[0]).MapFloat(math.Floor)
 	idxY := idx.Col(1).MulNum(1. / g.VoxelSize[1]).MapFloat(math.Floor)
 	idxZ := idx.Col(2).MulNum(1. / g.VoxelSize[2]).MapFloat(math.Floor)
@@ -35,9 +35,14 @@ func (v *Voxel) AddPoints(points Points) {
 
 // compute plane on valid voxels, call on demand
 func (v *Voxel) ComputePlane() {
-	cov := v.Points.CovMatrix()
+	// accumulated from current Points, so direct changes of the exported field are never missed
+	_, col := v.Points.Dims()
+	acc := matrix.NewCovAccumulator(col)
+	acc.AddRows(v.Points.Matrix, nil)
+	// same normalization as `Matrix.CovMatrix`, so thresholds on PlaneMSE keep their meaning
+	cov := acc.Scatter().MulNum(1. / float64(col-1)).ToMatrix()
 	eigVec, eigVal := matrix.EigenDecompose(cov)
-	v.PlaneCenter = Point{v.Points.Mean(0)}
+	v.PlaneCenter = Point{acc.Mean()}
 	v.PlaneNormal = Point{eigVec.Col(0)}
 	v.PlaneMSE = eigVal.At(0, 0)
 	v.PlaneCurvature = eigVal.At(0, 0) / eigVal.Sum(-1).At(0)
@@ -23,7 +23,7 @@ type HausdorffDistance struct {
 	LIndex, RIndex int
 }
 
-func DirectedHausdorffDistance(pts1, pts2 *matrix.Matrix) *HausdorffDistance {
+func DirectedHausdorffDistance(pts1, pts2 matrix.Interface) *HausdorffDistance {
 	r1, c1 := pts1.Dims()
 	r2, c2 := pts2.Dims()
 	if c1 != c2 {
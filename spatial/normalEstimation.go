@@ -9,7 +9,7 @@ import (
 
 // common solution: `Principle Component Analysis`
 // https://en.wikipedia.org/wiki/Principal_component_analysis
-func PlanePcaEigen(points *matrix.Matrix) *matrix.Vector {
+func PlanePcaEigen(points matrix.Interface) *matrix.Vector {
 	row, col := points.Dims()
 	if col > 3 {
 		panic("Only 3D points is supported")
@@ -17,9 +17,22 @@ func PlanePcaEigen(points *matrix.Matrix) *matrix.Vector {
 	if row < 3 {
 		panic("Not enough points to fit a plane")
 	}
-	cov := points.CovMatrix()
+	acc := matrix.NewCovAccumulator(col)
+	acc.AddRows(points, nil)
+	return PlanePcaAccumulator(acc)
+}
+
+// PlanePcaAccumulator fits plane normal from mean and covariance accumulated by `matrix.CovAccumulator`,
+// so points can be streamed (or merged from several voxels) without materializing them as one matrix
+func PlanePcaAccumulator(acc *matrix.CovAccumulator) *matrix.Vector {
+	if acc.Dim() > 3 {
+		panic("Only 3D points is supported")
+	}
+	if acc.Count() < 3 {
+		panic("Not enough points to fit a plane")
+	}
 	// _, eigVec := Eigen33(cov)
-	eigVec, _ := matrix.EigenDecompose(cov) // new `EigenDecompose` function is about one times faster than `Eigen33`
+	eigVec, _ := matrix.EigenDecompose(acc.Covariance()) // new `EigenDecompose` function is about one times faster than `Eigen33`
 	return eigVec.Col(0)
 }
 
@@ -32,8 +45,8 @@ func PlanePcaSVD(points *matrix.Matrix) *matrix.Vector {
 	if row < 3 {
 		panic("Not enough points to fit a plane")
 	}
-	_, _, V := matrix.SVD(points.Sub(points.Mean(0).Tile(0, row))) // U, S, V
-	return V.Col(2)                                                // V's column corresponding to smallest value in S
+	_, _, V := matrix.SVD(points.SubElem(points.Mean(0))) // U, S, V
+	return V.Col(2)                                       // V's column corresponding to smallest value in S
 }
 
 // https://www.ilikebigbits.com/2017_09_25_plane_from_points_2.html
@@ -56,7 +69,7 @@ func PlanePcaSVD(points *matrix.Matrix) *matrix.Vector {
 // 	but this works only when z-component of the plane normal is non-zero, if it is, then we can use the x or y component for calculation
 // 	sine the above only minimize the squares of the residuals as perpendicular to the main axis, not the residuals perpendicular to the plane,
 // 	then use the below weighted way to calculate the components of plain normal is more reasonable.
-func PlaneLinearSolveWeighted(points *matrix.Matrix) *matrix.Vector {
+func PlaneLinearSolveWeighted(points matrix.Interface) *matrix.Vector {
 	row, col := points.Dims()
 	if col > 3 {
 		panic("Only 3D points is supported")
@@ -64,7 +77,7 @@ func PlaneLinearSolveWeighted(points *matrix.Matrix) *matrix.Vector {
 	if row < 3 {
 		panic("Not enough points to fit a plane")
 	}
-	cov := points.CovMatrix()
+	cov := matrix.CovMatrixOf(points, nil)
 	xx, xy, xz, yy, yz, zz := cov.At(0, 0), cov.At(0, 1), cov.At(0, 2), cov.At(1, 1), cov.At(1, 2), cov.At(2, 2)
 	/*
 		// calculate cov
@@ -17,6 +17,20 @@ func TestPlanePcaEigen(t *testing.T) {
 	}
 }
 
+func TestPlanePcaAccumulator(t *testing.T) {
+	// points streamed in two chunks give the same normal as the whole matrix
+	points := matrix.GenerateRandomMatrix(50, 3)
+	acc := matrix.NewCovAccumulator(3)
+	acc.AddRows(points.GetSubMatrix(0, 0, 20, 3), nil)
+	for i := 20; i < 50; i++ {
+		acc.Add(points.Row(i))
+	}
+	n, want := PlanePcaAccumulator(acc), PlanePcaSVD(points)
+	if !matrix.VEqual(n, want) && !matrix.VEqual(n.MulNum(-1.), want) {
+		t.Fail()
+	}
+}
+
 func TestPlanePcaSVD(t *testing.T) {
 	a := matrix.Data{{-1, 2, -1}, {2, -1, -2}, {-1, 3, -1}}
 	points := new(matrix.Matrix).Init(a)
@@ -16,8 +16,8 @@ func CanonicalCorrelation(X, Y *matrix.Matrix) (A, B *matrix.Matrix, r *matrix.V
 	if xm != ym {
 		panic("X, Y should have the same number of rows (observations)")
 	}
-	Ux, Sx, Vx := matrix.SVD(X.Sub(X.Mean(0).Tile(0, xm)))
-	Uy, Sy, Vy := matrix.SVD(Y.Sub(Y.Mean(0).Tile(0, xm)))
+	Ux, Sx, Vx := matrix.SVD(X.SubElem(X.Mean(0)))
+	Uy, Sy, Vy := matrix.SVD(Y.SubElem(Y.Mean(0)))
 
 	/*
 		// Method 1
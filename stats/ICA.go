@@ -51,7 +51,7 @@ func FastICA(C int, tol float64, maxIter int, whitening bool, nonLinearFunc func
 func PreWhitening(C int, dataSet *matrix.Matrix) (X, K *matrix.Matrix) {
 	// step 1: centering
 	N, M := dataSet.Dims()
-	data := dataSet.Sub(dataSet.Mean(1).Tile(1, M))
+	data := dataSet.SubElem(matrix.AsColumn(dataSet.Mean(1)))
 	// step 2: whitening
 	_, D, V := matrix.SVD(data.T())
 	K = matrix.ZeroMatrix(C, N)
@@ -74,7 +74,7 @@ func CalW(C int, tol float64, maxIter int, nonLinearFunc func(w *matrix.Vector,
 	for i := 0; i < C; i++ {
 		cnt := 0
 		w := W.Row(i)
-		w = w.MulNum(1. / w.Norm())
+		matrix.Scal(1./w.Norm(), w)
 		wp := w
 		for {
 			wp = nonLinearFunc(w, dataSet)
@@ -83,7 +83,7 @@ func CalW(C int, tol float64, maxIter int, nonLinearFunc func(w *matrix.Vector,
 				s.Add(W.Row(j).MulNum(wp.Dot(W.Row(j))))
 			}
 			wp = wp.Sub(&s)
-			wp = wp.MulNum(1. / wp.Norm())
+			matrix.Scal(1./wp.Norm(), wp)
 			lim := math.Abs(math.Abs(wp.Dot(w)) - 1)
 			w = wp
 			cnt++
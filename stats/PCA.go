@@ -9,23 +9,15 @@ import "golina/matrix"
 //	SVD or Eigen (https://stats.stackexchange.com/questions/314046/why-does-andrew-ng-prefer-to-use-svd-and-not-eig-of-covariance-matrix-to-do-pca)
 //	Here i prefer to use Eigen, since my Eigen implementation is faster than SVD due to covariance matrix is always real symmetric matrix
 //	SVD way can be found in `PlanePcaSVD` of `spatial/normalEstimation.go`
-func PrincipalComponents(dataSet *matrix.Matrix, weights *matrix.Vector) (pcs *matrix.Matrix, colVars *matrix.Vector) {
+//	dataSet can be any `matrix.Interface` (e.g. SparseMatrix), it is read row by row without densifying
+func PrincipalComponents(dataSet matrix.Interface, weights *matrix.Vector) (pcs *matrix.Matrix, colVars *matrix.Vector) {
 	row, col := dataSet.Dims()
 	if weights != nil && weights.Length() != row {
 		panic("length of weights vector should be equal to data matrix's rows")
 	}
 	// From wiki: https://en.wikipedia.org/wiki/Mahalanobis_distance
 	// the ellipsoid that best represents the set's probability distribution can be estimated by building the covariance matrix of the samples
-	cov := new(matrix.Matrix)
-	if weights == nil {
-		cov = dataSet.CovMatrix()
-	} else {
-		data := make(matrix.Data, row)
-		for i, v := range dataSet.Data {
-			data[i] = *(v.MulNum(weights.At(i)))
-		}
-		cov = new(matrix.Matrix).Init(data).CovMatrix()
-	}
+	cov := matrix.CovMatrixOf(dataSet, weights)
 	eigVec, eigVal := matrix.EigenDecompose(cov) // eigVec is in ascending way
 	tmpV := make(matrix.Vector, col)
 	tmpM := matrix.ZeroMatrix(col, col)
@@ -37,3 +29,76 @@ func PrincipalComponents(dataSet *matrix.Matrix, weights *matrix.Vector) (pcs *m
 	pcs = tmpM.T()
 	return
 }
+
+// PrincipalComponentsTopK calculates only the first k principal components (largest column variances first) like `PrincipalComponents`
+//	covariance matrix is used as a matrix-free operator by `matrix.Lanczos`, so it is never formed: each product costs
+//	two passes over the data set, which is much faster than full Eigen decomposition for k << columns
+//	pcs: columns x k, colVars: k variances (same normalization as `matrix.CovMatrixOf`)
+func PrincipalComponentsTopK(dataSet matrix.Interface, weights *matrix.Vector, k int) (pcs *matrix.Matrix, colVars *matrix.Vector) {
+	row, _ := dataSet.Dims()
+	if weights != nil && weights.Length() != row {
+		panic("length of weights vector should be equal to data matrix's rows")
+	}
+	res, err := matrix.Lanczos(covOperator(dataSet, weights), k, &matrix.EigenSettings{Target: matrix.LargestReal})
+	if err != nil {
+		panic(err)
+	}
+	return res.Vectors, res.Values
+}
+
+// covOperator returns covariance matrix of data set as operator, C * v = Xc.T() * (Xc * v) / (columns - 1)
+//	Xc = W * X - 1 * mean.T(), W is diagonal matrix of weights
+func covOperator(dataSet matrix.Interface, weights *matrix.Vector) *matrix.FuncOperator {
+	row, col := dataSet.Dims()
+	mulVec, mulVecT := rowProducts(dataSet)
+	w := make(matrix.Vector, row)
+	for i := range w {
+		w[i] = 1
+		if weights != nil {
+			w[i] = weights.At(i)
+		}
+	}
+	mean := mulVecT(&w).MulNum(1. / float64(row))
+	return &matrix.FuncOperator{Rows: col, Cols: col, Mul: func(v *matrix.Vector) *matrix.Vector {
+		u := mulVec(v)
+		mv := mean.Dot(v)
+		sum := 0.
+		for i := range *u {
+			(*u)[i] = w[i]*(*u)[i] - mv
+			sum += (*u)[i]
+			(*u)[i] *= w[i]
+		}
+		r := mulVecT(u)
+		for j := range *r {
+			(*r)[j] = ((*r)[j] - sum*(*mean)[j]) / float64(col-1)
+		}
+		return r
+	}}
+}
+
+// rowProducts returns X * v and X.T() * u of data set, through `Row` if it is not a `matrix.TransposeOperator`
+func rowProducts(dataSet matrix.Interface) (mulVec, mulVecT func(v *matrix.Vector) *matrix.Vector) {
+	if op, ok := dataSet.(matrix.TransposeOperator); ok {
+		return op.MulVec, op.MulVecT
+	}
+	row, col := dataSet.Dims()
+	mulVec = func(v *matrix.Vector) *matrix.Vector {
+		u := make(matrix.Vector, row)
+		for i := range u {
+			u[i] = dataSet.Row(i).Dot(v)
+		}
+		return &u
+	}
+	mulVecT = func(u *matrix.Vector) *matrix.Vector {
+		r := make(matrix.Vector, col)
+		for i := 0; i < row; i++ {
+			if x := u.At(i); x != 0 {
+				for j, v := range *dataSet.Row(i) {
+					r[j] += x * v
+				}
+			}
+		}
+		return &r
+	}
+	return
+}
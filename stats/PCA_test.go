@@ -36,3 +36,38 @@ func BenchmarkPrincipalComponents(b *testing.B) {
 		})
 	}
 }
+
+func TestPrincipalComponentsSparse(t *testing.T) {
+	sm := matrix.GenerateRandomSparseMatrix(50, 4, 80)
+	pc, colVars := PrincipalComponents(sm, nil)
+	dpc, dColVars := PrincipalComponents(sm.ToMatrix(), nil)
+	if !matrix.MEqual(pc, dpc) || !matrix.VEqual(colVars, dColVars) {
+		t.Fail()
+	}
+}
+
+func TestPrincipalComponentsTopK(t *testing.T) {
+	points := matrix.GenerateRandomMatrix(200, 30)
+	weights := matrix.GenerateRandomVector(200)
+	pc, colVars := PrincipalComponents(points, weights)
+	for _, data := range []matrix.Interface{points, matrix.NewDense(200, 30, *points.Flat())} {
+		topPC, topVars := PrincipalComponentsTopK(data, weights, 4)
+		if r, c := topPC.Dims(); r != 30 || c != 4 || topVars.Length() != 4 {
+			t.Fatal(r, c)
+		}
+		for i := 0; i < 4; i++ {
+			if !matrix.FloatEqual(topVars.At(i), colVars.At(i)) {
+				t.Fatal(topVars, colVars)
+			}
+			if !matrix.VEqual(topPC.Col(i), pc.Col(i)) && !matrix.VEqual(topPC.Col(i).MulNum(-1), pc.Col(i)) {
+				t.Fail()
+			}
+		}
+	}
+
+	sm := matrix.GenerateRandomSparseMatrix(500, 40, 2000)
+	_, dColVars := PrincipalComponents(sm.ToMatrix(), nil)
+	if _, topVars := PrincipalComponentsTopK(sm.ToCSR(), nil, 3); !matrix.VEqual(topVars, &matrix.Vector{dColVars.At(0), dColVars.At(1), dColVars.At(2)}) {
+		t.Fail()
+	}
+}
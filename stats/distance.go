@@ -9,8 +9,17 @@ import (
 //	https://en.wikipedia.org/wiki/Mahalanobis_distance
 // From wiki: the ellipsoid that best represents the set's probability distribution can be estimated by building the covariance matrix of the samples.
 // The Mahalanobis distance is the distance of the test point from the center of mass divided by the width of the ellipsoid in the direction of the test point.
+// Degenerate data (e.g. fewer samples than dimensions, collinear features) has singular covariance matrix,
+// 	then pseudo-inverse `matrix.Pinv` is used instead of `Inverse`, directions without variance are ignored
 func MahalanobisDistance(x, y *matrix.Vector, dataSet *matrix.Matrix) float64 {
 	cov := dataSet.CovMatrix()
+	n, _ := cov.Dims()
+	var vi *matrix.Matrix
+	if cov.Cond1Est() < 1/(float64(n)*math.Pow(2, -52)) {
+		vi = cov.Inverse()
+	} else {
+		vi = matrix.Pinv(cov, 0)
+	}
 	tmp := &matrix.Vector{}
 	if x != nil && y == nil {
 		tmp = x.Sub(dataSet.Mean(0))
@@ -19,7 +28,7 @@ func MahalanobisDistance(x, y *matrix.Vector, dataSet *matrix.Matrix) float64 {
 	} else {
 		panic("at least input non-nil x as input vector")
 	}
-	return math.Sqrt((tmp.ToMatrix(1, x.Length()).Mul(cov.Inverse())).Row(0).Dot(tmp))
+	return math.Sqrt((tmp.ToMatrix(1, x.Length()).Mul(vi)).Row(0).Dot(tmp))
 }
 
 // Keep the same api from scipy: x, y -> Vector, vi -> Inverse of Covariance Matrix
@@ -2,15 +2,24 @@ package stats
 
 import (
 	"golina/matrix"
+	"math"
 	"testing"
 )
 
 func TestMahalanobisDistance(t *testing.T) {
-	dataSet := &matrix.Matrix{Data: matrix.Data{{4, 3, 5}, {2, 6, 8}, {5, 3, 7}}}
-	if !matrix.FloatEqual(MahalanobisDistance(dataSet.Row(0), nil, dataSet), 0.5773502691896252) {
+	dataSet := &matrix.Matrix{Data: matrix.Data{{4, 3, 5}, {2, 6, 8}, {5, 3, 7}, {1, 2, 2}, {3, 4, 6}}}
+	if !matrix.FloatEqual(MahalanobisDistance(dataSet.Row(0), nil, dataSet), 1.1771636613972944) {
 		t.Fail()
 	}
-	if !matrix.FloatEqual(MahalanobisDistance(dataSet.Row(0), dataSet.Row(1), dataSet), 3.7416573867739413) {
+	if !matrix.FloatEqual(MahalanobisDistance(dataSet.Row(0), dataSet.Row(1), dataSet), 2) {
+		t.Fail()
+	}
+	// 3 samples in 3D have singular covariance, squared distance of sample i is (n - 1) * leverage h[i] = 2 * 2 / 3
+	dataSet = &matrix.Matrix{Data: matrix.Data{{4, 3, 5}, {2, 6, 8}, {5, 3, 7}}}
+	if !matrix.FloatEqual(MahalanobisDistance(dataSet.Row(0), nil, dataSet), 2/math.Sqrt(3)) {
+		t.Fail()
+	}
+	if !matrix.FloatEqual(MahalanobisDistance(dataSet.Row(0), dataSet.Row(1), dataSet), 2) {
 		t.Fail()
 	}
 }